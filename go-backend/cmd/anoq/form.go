@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/config"
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/formdef"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// runForm handles the form export|import subcommands
+func runForm(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing form subcommand (export or import)")
+	}
+
+	database, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "export":
+		return runFormExport(database, args[1:])
+	case "import":
+		return runFormImport(database, args[1:])
+	default:
+		return fmt.Errorf("unknown form subcommand %q", args[0])
+	}
+}
+
+// runFormExport writes the definition of a form, looked up by ID or slug
+func runFormExport(database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("form export", flag.ContinueOnError)
+	output := flags.String("o", "-", "File to write, - for stdout")
+	formatName := flags.String("format", "", "json or yaml (default from the file extension, json for stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: anoq form export [-o file] [-format json|yaml] <form ID or slug>")
+	}
+
+	format, err := definitionFormat(*formatName, *output)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	formRepo := repository.NewFormRepository(database)
+	questionRepo := repository.NewQuestionRepository(database)
+
+	var form *model.Form
+	if formID, parseErr := uuid.Parse(flags.Arg(0)); parseErr == nil {
+		form, err = formRepo.GetFormByID(ctx, formID)
+	} else {
+		form, err = formRepo.GetFormBySlug(ctx, flags.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	questions, err := questionRepo.GetQuestionsByFormID(ctx, form.ID)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *output, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return formdef.Encode(w, formdef.Export(form, questions), format)
+}
+
+// runFormImport creates a form from a definition file, owned by the user with
+// the given email
+func runFormImport(database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("form import", flag.ContinueOnError)
+	author := flags.String("author", "", "Email of the user who will own the form (required)")
+	slug := flags.String("slug", "", "Slug of the new form (default from the definition)")
+	formatName := flags.String("format", "", "json or yaml (default from the file extension, json for stdin)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *author == "" {
+		return fmt.Errorf("usage: anoq form import -author email [-slug slug] [-format json|yaml] <file>")
+	}
+
+	path := flags.Arg(0)
+	format, err := definitionFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	doc, err := formdef.Decode(r, format)
+	if err != nil {
+		return err
+	}
+
+	formSlug, err := doc.ResolveSlug(*slug)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	user, err := repository.NewUserRepository(database).GetUserByEmail(ctx, *author)
+	if err != nil {
+		return err
+	}
+
+	form, questions, err := doc.FormDefinition.NewForm(user.ID, "", formSlug, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := repository.NewFormRepository(database).CreateFormWithQuestions(ctx, form, questions); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported draft form %s (%s) with %d question(s)\n", form.Slug, form.ID, len(questions))
+	return nil
+}
+
+// definitionFormat returns the format named by the -format flag, or the
+// format of the file at path when the flag is empty
+func definitionFormat(name, path string) (formdef.Format, error) {
+	if name == "" {
+		return formdef.FormatFromPath(path), nil
+	}
+
+	format := formdef.Format(name)
+	if !format.IsValid() {
+		return "", fmt.Errorf("invalid format %q: must be json or yaml", name)
+	}
+	return format, nil
+}
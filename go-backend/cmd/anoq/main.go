@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ayan-sh03/anoq/internal/config"
+	"github.com/ayan-sh03/anoq/internal/db"
+)
+
+const usage = `Usage: anoq <command> [arguments]
+
+Commands:
+  migrate up            Apply all pending migrations
+  migrate down [N]      Roll back the last N migrations (default 1)
+  migrate status        Show applied and pending migrations
+  migrate baseline N    Record migrations up to N as applied without running
+                        them, for databases set up by hand
+  form export [-o file] [-format json|yaml] <form ID or slug>
+                        Write a form's definition
+  form import -author email [-slug slug] [-format json|yaml] <file>
+                        Create a form from a definition file (- for stdin)
+`
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	var cmdErr error
+	switch os.Args[1] {
+	case "migrate":
+		cmdErr = runMigrate(cfg, os.Args[2:])
+	case "form":
+		cmdErr = runForm(cfg, os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if cmdErr != nil {
+		log.Fatal().Err(cmdErr).Msg("Command failed")
+	}
+}
+
+// connect opens a database connection using the loaded configuration
+func connect(cfg *config.Config) (*db.DB, error) {
+	return db.New(db.Config{
+		Host:     cfg.Database.Host,
+		Port:     fmt.Sprintf("%d", cfg.Database.Port),
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+}
@@ -17,11 +17,22 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "github.com/ayan-sh03/anoq/docs" // docs is generated by Swag CLI, you have to import it.
+	"github.com/ayan-sh03/anoq/internal/accesstoken"
+	"github.com/ayan-sh03/anoq/internal/apikey"
+	"github.com/ayan-sh03/anoq/internal/authz"
+	"github.com/ayan-sh03/anoq/internal/cleanup"
 	"github.com/ayan-sh03/anoq/internal/config"
 	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/edittoken"
 	"github.com/ayan-sh03/anoq/internal/handler"
 	"github.com/ayan-sh03/anoq/internal/middleware"
+	"github.com/ayan-sh03/anoq/internal/previewtoken"
 	"github.com/ayan-sh03/anoq/internal/repository"
+	"github.com/ayan-sh03/anoq/internal/respondent"
+	"github.com/ayan-sh03/anoq/internal/scheduler"
+	"github.com/ayan-sh03/anoq/internal/starttoken"
+	"github.com/ayan-sh03/anoq/internal/webhook"
+	"github.com/ayan-sh03/anoq/migrations"
 )
 
 // @title           AnoQ Backend API
@@ -41,7 +52,12 @@ import (
 // @securityDefinitions.apikey Bearer
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and an access token from /api/auth/token or a session token from /api/auth/login.
+
+// @securityDefinitions.apikey ApiKey
+// @in header
+// @name X-API-Key
+// @description API key created under /api/user/api-keys, limited to its scopes
 
 // @securityDefinitions.apikey SessionToken
 // @in header
@@ -58,6 +74,9 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
+	if err := cfg.ValidateSecrets(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid signing secrets")
+	}
 
 	// Set log level
 	if cfg.IsDevelopment() {
@@ -89,21 +108,70 @@ func main() {
 	}
 	defer database.Close()
 
+	// Apply pending schema migrations if enabled
+	if cfg.Database.AutoMigrate {
+		migrator, err := db.NewMigrator(database, migrations.FS)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load migrations")
+		}
+
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to apply migrations")
+		}
+		log.Info().Int("applied", applied).Msg("Database migrations up to date")
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(database)
 	formRepo := repository.NewFormRepository(database)
 	questionRepo := repository.NewQuestionRepository(database)
 	responseRepo := repository.NewResponseRepository(database)
+	webhookRepo := repository.NewWebhookRepository(database)
+	templateRepo := repository.NewTemplateRepository(database)
+	versionRepo := repository.NewVersionRepository(database)
+	workspaceRepo := repository.NewWorkspaceRepository(database)
+	apiKeyRepo := repository.NewAPIKeyRepository(database)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(database)
+
+	// Background workers run until shutdown
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Deliver webhooks
+	dispatcher := webhook.NewDispatcher(webhookRepo, cfg.Webhooks.AllowPrivateTargets)
+	go dispatcher.Run(workersCtx)
+
+	// Open and close scheduled forms; safe to run on every replica
+	formScheduler := scheduler.NewScheduler(formRepo, dispatcher, cfg.Forms.ScheduleInterval)
+	go formScheduler.Run(workersCtx)
+
+	// Purge expired sessions
+	sessionCleaner := cleanup.NewCleaner(userRepo, cfg.Auth.SessionCleanupInterval)
+	go sessionCleaner.Run(workersCtx)
 
 	// Initialize handlers with new constructors
-	userHandler := handler.NewUserHandler(userRepo)
-	formHandler := handler.NewFormHandler(formRepo, responseRepo)
-	questionHandler := handler.NewQuestionHandler(questionRepo, formRepo)
-	responseHandler := handler.NewResponseHandler(responseRepo, formRepo, questionRepo)
+	userHandler := handler.NewUserHandler(userRepo, refreshTokenRepo, cfg.Auth.JWTExpiration)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyRepo)
+	accessTokens := accesstoken.NewSigner(cfg.Auth.AccessTokenSecret, cfg.Auth.AccessTokenTTL)
+	tokenHandler := handler.NewTokenHandler(userRepo, refreshTokenRepo, accessTokens, cfg.Auth.RefreshTokenTTL)
+	startTokens := starttoken.NewSigner(cfg.Responses.StartTokenSecret, cfg.Responses.StartTokenTTL)
+	previews := previewtoken.NewSigner(cfg.Forms.PreviewTokenSecret, cfg.Forms.PreviewTokenTTL)
+	formHandler := handler.NewFormHandler(formRepo, responseRepo, questionRepo, versionRepo, workspaceRepo, startTokens, previews, dispatcher)
+	questionHandler := handler.NewQuestionHandler(questionRepo)
+	identities := respondent.NewIdentifier(cfg.Responses.RespondentHashSecret)
+	responseHandler := handler.NewResponseHandler(responseRepo, formRepo, questionRepo, versionRepo, startTokens, cfg.Responses.FastThreshold, dispatcher, identities, cfg.IsProduction())
+	webhookHandler := handler.NewWebhookHandler(webhookRepo, dispatcher)
+	templateHandler := handler.NewTemplateHandler(templateRepo, formRepo, questionRepo)
+	versionHandler := handler.NewVersionHandler(versionRepo)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceRepo, formRepo, userRepo, cfg.Workspaces.InvitationTTL)
 	healthHandler := handler.NewHealthHandler(database)
 
+	// Every API route is checked against its policy in the authz package
+	authorizer := authz.NewAuthorizer(middleware.Authenticate(userRepo, apiKeyRepo, accessTokens, cfg.Auth.JWTExpiration, cfg.Auth.APIKeyRateLimit), formRepo, questionRepo, responseRepo, webhookRepo, workspaceRepo)
+
 	// Setup router
-	router := setupRouter(cfg, userHandler, formHandler, questionHandler, responseHandler, healthHandler, userRepo)
+	router := setupRouter(cfg, userHandler, tokenHandler, apiKeyHandler, formHandler, questionHandler, responseHandler, webhookHandler, templateHandler, versionHandler, workspaceHandler, healthHandler, authorizer)
 
 	// Setup server
 	server := &http.Server{
@@ -146,11 +214,17 @@ func main() {
 func setupRouter(
 	cfg *config.Config,
 	userHandler *handler.UserHandler,
+	tokenHandler *handler.TokenHandler,
+	apiKeyHandler *handler.APIKeyHandler,
 	formHandler *handler.FormHandler,
 	questionHandler *handler.QuestionHandler,
 	responseHandler *handler.ResponseHandler,
+	webhookHandler *handler.WebhookHandler,
+	templateHandler *handler.TemplateHandler,
+	versionHandler *handler.VersionHandler,
+	workspaceHandler *handler.WorkspaceHandler,
 	healthHandler *handler.HealthHandler,
-	userRepo *repository.UserRepository,
+	authorizer *authz.Authorizer,
 ) *gin.Engine {
 	router := gin.New()
 
@@ -163,8 +237,8 @@ func setupRouter(
 	// CORS configuration
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"http://localhost:3000", "https://anoq.vercel.app"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", edittoken.Header, apikey.Header}
 	corsConfig.ExposeHeaders = []string{"X-Request-ID"}
 	corsConfig.AllowCredentials = true
 	router.Use(cors.New(corsConfig))
@@ -182,20 +256,31 @@ func setupRouter(
 		// Rate limiting for all API routes
 		api.Use(middleware.RateLimit(cfg.App.Environment == "production"))
 
+		// Authentication and form roles, by the policy of each route
+		api.Use(authorizer.Middleware())
+
 		// Authentication routes (public - no auth required)
 		authRoutes := api.Group("/auth")
 		{
 			authRoutes.POST("/register", userHandler.Register)
 			authRoutes.POST("/login", userHandler.Login)
-			authRoutes.POST("/logout", middleware.Auth(cfg, userRepo), userHandler.Logout)
+			authRoutes.POST("/logout", userHandler.Logout)
+			authRoutes.POST("/token", tokenHandler.IssueToken)
+			authRoutes.POST("/refresh", tokenHandler.RefreshToken)
+			authRoutes.POST("/revoke", tokenHandler.RevokeToken)
 		}
 
 		// User routes
 		userRoutes := api.Group("/user")
-		userRoutes.Use(middleware.Auth(cfg, userRepo))
 		{
 			userRoutes.GET("/", userHandler.GetUser)
 			userRoutes.PUT("/", userHandler.UpdateUser)
+			userRoutes.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+			userRoutes.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			userRoutes.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+			userRoutes.GET("/sessions", userHandler.ListSessions)
+			userRoutes.DELETE("/sessions", userHandler.LogoutEverywhere)
+			userRoutes.DELETE("/sessions/:id", userHandler.DeleteSession)
 		}
 
 		// Form routes
@@ -207,27 +292,89 @@ func setupRouter(
 
 		// Protected form routes (require authentication)
 		protectedFormRoutes := api.Group("/form")
-		protectedFormRoutes.Use(middleware.Auth(cfg, userRepo))
 		{
 			protectedFormRoutes.GET("/", formHandler.ListForms)
 			// protectedFormRoutes.GET("/:id", formHandler.GetForm)
 			protectedFormRoutes.POST("/", formHandler.CreateForm)
+			protectedFormRoutes.POST("/import", formHandler.ImportForm)
 			protectedFormRoutes.PUT("/:id", formHandler.UpdateForm)
 			protectedFormRoutes.DELETE("/:id", formHandler.DeleteForm)
+			protectedFormRoutes.POST("/:id/publish", formHandler.PublishForm)
+			protectedFormRoutes.POST("/:id/preview-token", formHandler.CreatePreviewToken)
 			protectedFormRoutes.POST("/open/:slug", formHandler.OpenForm)
 			protectedFormRoutes.POST("/close/:slug", formHandler.CloseForm)
 			protectedFormRoutes.GET("/submissions/:slug", formHandler.GetFormSubmissions)
+			protectedFormRoutes.GET("/:id/export", formHandler.ExportResponses)
+			protectedFormRoutes.GET("/:id/analytics", formHandler.GetFormAnalytics)
+			protectedFormRoutes.GET("/:id/stats", formHandler.GetFormSubmissionStats)
+			protectedFormRoutes.POST("/:id/duplicate", formHandler.DuplicateForm)
+			protectedFormRoutes.GET("/:id/definition", formHandler.ExportFormDefinition)
+			protectedFormRoutes.POST("/:id/template", templateHandler.PublishTemplate)
+			protectedFormRoutes.PUT("/:id/workspace", formHandler.MoveForm)
+
+			// Form versions
+			protectedFormRoutes.GET("/:id/versions", versionHandler.ListVersions)
+			protectedFormRoutes.GET("/:id/versions/:version", versionHandler.GetVersion)
+			protectedFormRoutes.GET("/:id/versions/:version/diff", versionHandler.DiffVersions)
+
+			// Response management within forms
+			protectedFormRoutes.GET("/:id/responses/search", formHandler.SearchResponses)
+			protectedFormRoutes.PATCH("/:id/responses/:responseId", responseHandler.TriageResponse)
+			protectedFormRoutes.DELETE("/:id/responses/:responseId", responseHandler.DeleteFormResponse)
+			protectedFormRoutes.POST("/:id/responses/delete", responseHandler.BulkDeleteResponses)
 
 			// Question routes within forms - using same :id parameter to avoid conflicts
 			protectedFormRoutes.POST("/:id/questions", questionHandler.CreateQuestion)
 			protectedFormRoutes.GET("/:id/questions", questionHandler.GetFormQuestions)
 			protectedFormRoutes.POST("/:id/questions/batch", questionHandler.CreateMultipleQuestions)
 			protectedFormRoutes.PUT("/:id/questions/reorder", questionHandler.ReorderQuestions)
+
+			// Webhook routes within forms
+			protectedFormRoutes.POST("/:id/webhooks", webhookHandler.CreateWebhook)
+			protectedFormRoutes.GET("/:id/webhooks", webhookHandler.ListWebhooks)
+		}
+
+		// Webhook routes (standalone)
+		webhookRoutes := api.Group("/webhooks")
+		{
+			webhookRoutes.GET("/:id", webhookHandler.GetWebhook)
+			webhookRoutes.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhookRoutes.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverDelivery)
+		}
+
+		// Template routes
+		templateRoutes := api.Group("/templates")
+		{
+			templateRoutes.GET("/", templateHandler.ListTemplates)
+			templateRoutes.GET("/:id", templateHandler.GetTemplate)
+			templateRoutes.PUT("/:id", templateHandler.UpdateTemplate)
+			templateRoutes.DELETE("/:id", templateHandler.DeleteTemplate)
+			templateRoutes.POST("/:id/forms", templateHandler.CreateFormFromTemplate)
+		}
+
+		// Workspace routes
+		workspaceRoutes := api.Group("/workspaces")
+		{
+			workspaceRoutes.GET("/", workspaceHandler.ListWorkspaces)
+			workspaceRoutes.POST("/", workspaceHandler.CreateWorkspace)
+			workspaceRoutes.GET("/:id", workspaceHandler.GetWorkspace)
+			workspaceRoutes.PUT("/:id", workspaceHandler.UpdateWorkspace)
+			workspaceRoutes.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+			workspaceRoutes.GET("/:id/forms", workspaceHandler.ListWorkspaceForms)
+			workspaceRoutes.GET("/:id/members", workspaceHandler.ListMembers)
+			workspaceRoutes.PUT("/:id/members/:userId", workspaceHandler.UpdateMember)
+			workspaceRoutes.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+			workspaceRoutes.POST("/:id/leave", workspaceHandler.LeaveWorkspace)
+			workspaceRoutes.GET("/:id/invitations", workspaceHandler.ListInvitations)
+			workspaceRoutes.POST("/:id/invitations", workspaceHandler.CreateInvitation)
+			workspaceRoutes.DELETE("/:id/invitations/:invitationId", workspaceHandler.DeleteInvitation)
 		}
+		api.POST("/invitations/accept", workspaceHandler.AcceptInvitation)
 
 		// Question routes (standalone)
 		questionRoutes := api.Group("/questions")
-		questionRoutes.Use(middleware.Auth(cfg, userRepo))
 		{
 			questionRoutes.GET("/:id", questionHandler.GetQuestion)
 			questionRoutes.PUT("/:id", questionHandler.UpdateQuestion)
@@ -236,11 +383,12 @@ func setupRouter(
 
 		// Response routes (public for form submissions)
 		api.POST("/response", middleware.FormRateLimit(), responseHandler.SubmitResponse)
-		api.GET("/response/:id", middleware.Auth(cfg, userRepo), responseHandler.GetResponse)
+		api.GET("/response/:id", responseHandler.GetResponse)
+		api.PUT("/response/:id", middleware.FormRateLimit(), responseHandler.UpdateResponse)
+		api.DELETE("/response/:id", middleware.FormRateLimit(), responseHandler.DeleteResponse)
 
 		// Dashboard routes
 		dashboard := api.Group("/dashboard")
-		dashboard.Use(middleware.Auth(cfg, userRepo))
 		{
 			dashboard.GET("/", formHandler.GetDashboard)
 			dashboard.GET("/stats", formHandler.GetStats)
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ayan-sh03/anoq/internal/authz"
+	"github.com/ayan-sh03/anoq/internal/config"
+	"github.com/ayan-sh03/anoq/internal/handler"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const userHeader = "X-Test-User"
+
+// strangersForms finds a form owned by someone else for any ID or slug, and
+// a workspace the caller is not a member of for any ID
+type strangersForms struct {
+	formID uuid.UUID
+	author uuid.UUID
+}
+
+func (s *strangersForms) GetFormByID(ctx context.Context, id uuid.UUID) (*model.Form, error) {
+	return &model.Form{ID: id, AuthorID: s.author}, nil
+}
+
+func (s *strangersForms) GetFormBySlug(ctx context.Context, slug string) (*model.Form, error) {
+	return &model.Form{ID: s.formID, AuthorID: s.author, Slug: slug}, nil
+}
+
+func (s *strangersForms) GetQuestionByID(ctx context.Context, id uuid.UUID) (*model.Question, error) {
+	return &model.Question{ID: id, FormID: s.formID}, nil
+}
+
+func (s *strangersForms) GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error) {
+	return &model.FilledForm{ID: id, FormID: s.formID}, nil
+}
+
+func (s *strangersForms) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	return &model.Webhook{ID: id, FormID: s.formID}, nil
+}
+
+func (s *strangersForms) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	return &model.Workspace{ID: id}, nil
+}
+
+func (s *strangersForms) GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error) {
+	return nil, errors.New("member not found")
+}
+
+// newTestRouter builds the server's router with handlers that are never
+// reached, since every request is refused by authorization
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	store := &strangersForms{formID: uuid.New(), author: uuid.New()}
+	authenticate := func(c *gin.Context) bool {
+		userID := c.GetHeader(userHeader)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication token"})
+			return false
+		}
+		c.Set("user_id", userID)
+		return true
+	}
+	authorizer := authz.NewAuthorizer(authenticate, store, store, store, store, store)
+
+	return setupRouter(
+		&config.Config{App: config.AppConfig{Environment: "test"}},
+		&handler.UserHandler{},
+		&handler.TokenHandler{},
+		&handler.APIKeyHandler{},
+		&handler.FormHandler{},
+		&handler.QuestionHandler{},
+		&handler.ResponseHandler{},
+		&handler.WebhookHandler{},
+		&handler.TemplateHandler{},
+		&handler.VersionHandler{},
+		&handler.WorkspaceHandler{},
+		&handler.HealthHandler{},
+		authorizer,
+	)
+}
+
+// requestPath fills the parameters of a route pattern
+func requestPath(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = uuid.NewString()
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func apiRoutes(router *gin.Engine) gin.RoutesInfo {
+	var routes gin.RoutesInfo
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/api/") {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func TestRoutes_HavePolicies(t *testing.T) {
+	router := newTestRouter()
+
+	registered := make(map[string]bool)
+	for _, route := range apiRoutes(router) {
+		key := authz.RouteKey(route.Method, route.Path)
+		registered[key] = true
+
+		_, ok := authz.Lookup(route.Method, route.Path)
+		assert.True(t, ok, "route has no authorization policy: %s", key)
+	}
+
+	for _, key := range authz.Routes() {
+		assert.True(t, registered[key], "policy for a route that is not registered: %s", key)
+	}
+}
+
+func TestRoutes_RequireAuthentication(t *testing.T) {
+	router := newTestRouter()
+
+	for _, route := range apiRoutes(router) {
+		policy, _ := authz.Lookup(route.Method, route.Path)
+		if policy.Public {
+			continue
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(route.Method, requestPath(route.Path), nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.Method, route.Path)
+	}
+}
+
+func TestRoutes_RefuseOtherUsersFormsAndWorkspaces(t *testing.T) {
+	router := newTestRouter()
+	caller := uuid.NewString()
+
+	for _, route := range apiRoutes(router) {
+		policy, _ := authz.Lookup(route.Method, route.Path)
+		if policy.Resource == nil && policy.Workspace == "" {
+			continue
+		}
+
+		req := httptest.NewRequest(route.Method, requestPath(route.Path), nil)
+		req.Header.Set(userHeader, caller)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", route.Method, route.Path)
+	}
+}
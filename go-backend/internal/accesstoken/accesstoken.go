@@ -0,0 +1,133 @@
+// Package accesstoken issues and verifies the short-lived access tokens
+// clients get along with a refresh token. They are JWTs signed with
+// HMAC-SHA256, so they are verified without a database lookup. Revoking the
+// refresh token or signing out therefore only takes effect once the access
+// tokens already issued expire, which is why their lifetime is kept short.
+package accesstoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalid = errors.New("invalid access token")
+	ErrExpired = errors.New("access token expired")
+)
+
+// issuer names this server in the tokens it issues
+const issuer = "anoq"
+
+// header is the encoded JOSE header of every token
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims are the claims of an access token
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"` // User ID
+	FamilyID  string `json:"fam"` // Refresh token family the token was issued for
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+}
+
+// UserID returns the user the token was issued to
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// Signer issues and verifies access tokens
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner creates a signer issuing tokens valid for ttl
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// TTL returns how long issued tokens are valid
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue returns an access token for the user and when it expires
+func (s *Signer) Issue(userID, familyID uuid.UUID, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+
+	claims := Claims{
+		Issuer:    issuer,
+		Subject:   userID.String(),
+		FamilyID:  familyID.String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ID:        uuid.NewString(),
+	}
+	payload, _ := json.Marshal(claims) // Marshalling strings and integers cannot fail
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(s.sign(signingInput)), expiresAt
+}
+
+// Verify checks the token's signature and expiry and returns its claims
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalid
+	}
+
+	// Only the header this signer writes is accepted, which rules out
+	// tokens claiming another algorithm such as "none"
+	if parts[0] != header {
+		return nil, ErrInvalid
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, s.sign(signingInput)) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Issuer != issuer {
+		return nil, ErrInvalid
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
+
+// IsToken reports whether a bearer token has the shape of an access token
+// rather than a session token
+func IsToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// sign returns the HMAC-SHA256 of the signing input
+func (s *Signer) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
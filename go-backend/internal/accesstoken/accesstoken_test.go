@@ -0,0 +1,65 @@
+package accesstoken
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret", 15*time.Minute)
+	userID, familyID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	token, expiresAt := signer.Issue(userID, familyID, now)
+	assert.True(t, IsToken(token))
+	assert.WithinDuration(t, now.Add(15*time.Minute), expiresAt, time.Second)
+
+	claims, err := signer.Verify(token, now.Add(14*time.Minute))
+	require.NoError(t, err)
+	got, err := claims.UserID()
+	require.NoError(t, err)
+	assert.Equal(t, userID, got)
+	assert.Equal(t, familyID.String(), claims.FamilyID)
+	assert.NotEmpty(t, claims.ID)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret", time.Minute)
+	now := time.Now()
+	token, _ := signer.Issue(uuid.New(), uuid.New(), now)
+	otherSecret, _ := NewSigner("other", time.Minute).Issue(uuid.New(), uuid.New(), now)
+
+	parts := strings.Split(token, ".")
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{"other secret", otherSecret, now, ErrInvalid},
+		{"tampered payload", parts[0] + "." + parts[1] + "x." + parts[2], now, ErrInvalid},
+		{"alg none", noneHeader + "." + parts[1] + ".", now, ErrInvalid},
+		{"session token", "3f9a0c", now, ErrInvalid},
+		{"expired", token, now.Add(time.Minute), ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token, tt.now)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestIsToken(t *testing.T) {
+	assert.False(t, IsToken("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+	assert.True(t, IsToken("a.b.c"))
+}
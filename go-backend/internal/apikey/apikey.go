@@ -0,0 +1,39 @@
+// Package apikey generates the keys scripts use to call the API. Only the
+// hash of a key is stored, along with a short prefix to tell keys apart.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Header carries the API key on requests
+const Header = "X-API-Key"
+
+// keyPrefix marks a string as an AnoQ API key
+const keyPrefix = "anoq_"
+
+const (
+	keySize       = 32
+	displayLength = len(keyPrefix) + 6
+)
+
+// Generate returns a new key along with its display prefix and the hash to store
+func Generate() (key, prefix, hash string, err error) {
+	buf := make([]byte, keySize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:displayLength], Hash(key), nil
+}
+
+// Hash returns the hex encoded SHA-256 of a key, by which it is looked up
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, prefix, hash, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "anoq_"))
+	assert.Len(t, key, 48)
+	assert.Len(t, prefix, 11)
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, hash, 64)
+	assert.Equal(t, Hash(key), hash)
+
+	other, _, _, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
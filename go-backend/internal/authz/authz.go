@@ -0,0 +1,356 @@
+// Package authz authenticates requests and checks the caller's role on the
+// form or workspace a route refers to. Every route has a policy in one table,
+// and routes without a policy are refused.
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// Role is what a user may do with a form. Roles are ordered, and each role
+// may do everything the roles below it may do.
+type Role int
+
+const (
+	RoleNone    Role = iota
+	RoleViewer       // Read the form and its questions
+	RoleAnalyst      // Read the form's responses, exports and analytics
+	RoleEditor       // Change the form and its questions and triage responses
+	RoleOwner        // Delete the form and manage its webhooks
+)
+
+// roleInWorkspace is the role on a form that each workspace role grants to
+// the members of the form's workspace
+var roleInWorkspace = map[model.WorkspaceRole]Role{
+	model.WorkspaceRoleViewer:  RoleViewer,
+	model.WorkspaceRoleAnalyst: RoleAnalyst,
+	model.WorkspaceRoleEditor:  RoleEditor,
+	model.WorkspaceRoleAdmin:   RoleOwner,
+}
+
+// String returns the name of the role
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleAnalyst:
+		return "analyst"
+	case RoleEditor:
+		return "editor"
+	case RoleOwner:
+		return "owner"
+	}
+	return "none"
+}
+
+// Allows returns true if the role includes the required role
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// Context keys for what the middleware resolved
+const (
+	formKey      = "authz.form"
+	roleKey      = "authz.role"
+	workspaceKey = "authz.workspace"
+	memberKey    = "authz.member"
+)
+
+// APIKeyKey is the context key an Authenticator sets to the *model.APIKey a
+// request was authenticated with
+const APIKeyKey = "api_key"
+
+// Authenticator identifies the caller, setting user_id in the context and
+// APIKeyKey when an API key was used. It writes the error response and
+// returns false when the caller is not authenticated.
+type Authenticator func(c *gin.Context) bool
+
+// FormStore finds forms
+type FormStore interface {
+	GetFormByID(ctx context.Context, id uuid.UUID) (*model.Form, error)
+	GetFormBySlug(ctx context.Context, slug string) (*model.Form, error)
+}
+
+// QuestionStore finds questions
+type QuestionStore interface {
+	GetQuestionByID(ctx context.Context, questionID uuid.UUID) (*model.Question, error)
+}
+
+// ResponseStore finds responses
+type ResponseStore interface {
+	GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error)
+}
+
+// WebhookStore finds webhooks
+type WebhookStore interface {
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+}
+
+// WorkspaceStore finds workspaces and their members
+type WorkspaceStore interface {
+	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error)
+	GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error)
+}
+
+// Authorizer enforces the route policies
+type Authorizer struct {
+	authenticate Authenticator
+	forms        FormStore
+	questions    QuestionStore
+	responses    ResponseStore
+	webhooks     WebhookStore
+	workspaces   WorkspaceStore
+}
+
+// NewAuthorizer creates an authorizer enforcing the route policies
+func NewAuthorizer(authenticate Authenticator, forms FormStore, questions QuestionStore, responses ResponseStore, webhooks WebhookStore, workspaces WorkspaceStore) *Authorizer {
+	return &Authorizer{
+		authenticate: authenticate,
+		forms:        forms,
+		questions:    questions,
+		responses:    responses,
+		webhooks:     webhooks,
+		workspaces:   workspaces,
+	}
+}
+
+// Middleware applies the policy of the matched route. Unmatched requests
+// pass through so the router can answer them.
+func (a *Authorizer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+
+		policy, ok := Lookup(c.Request.Method, route)
+		if !ok {
+			log.Error().Str("method", c.Request.Method).Str("route", route).Msg("Route has no authorization policy")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+
+		if policy.Public {
+			c.Next()
+			return
+		}
+
+		if !a.authenticate(c) {
+			c.Abort()
+			return
+		}
+
+		if key, ok := APIKeyFrom(c); ok && !allowsKey(c, policy, key) {
+			c.Abort()
+			return
+		}
+
+		switch {
+		case policy.Resource != nil:
+			if !a.authorizeForm(c, policy) {
+				c.Abort()
+				return
+			}
+		case policy.Workspace != "":
+			if !a.authorizeWorkspace(c, policy) {
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// allowsKey checks an API key has the scope the route needs. Routes without a
+// scope need a signed in session. It writes the error response and returns
+// false on failure.
+func allowsKey(c *gin.Context, policy Policy, key *model.APIKey) bool {
+	if policy.Scope == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This route cannot be called with an API key"})
+		return false
+	}
+	if !key.HasScope(policy.Scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key is missing the " + string(policy.Scope) + " scope"})
+		return false
+	}
+	return true
+}
+
+// authorizeForm resolves the form of the request and checks the caller's
+// role on it. It writes the error response and returns false on failure.
+func (a *Authorizer) authorizeForm(c *gin.Context, policy Policy) bool {
+	resource := policy.Resource
+
+	id := c.Param(resource.Param)
+	form, err := resource.load(c.Request.Context(), a, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, errInvalidID):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + resource.Name + " ID"})
+		case errors.Is(err, errNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": capitalize(resource.Name) + " not found"})
+		default:
+			log.Error().Err(err).Str("resource", resource.Name).Str("id", id).Msg("Failed to resolve form for authorization")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get " + resource.Name})
+		}
+		return false
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return false
+	}
+
+	role, err := a.Role(c.Request.Context(), form, userID)
+	if err != nil {
+		log.Error().Err(err).Str("form_id", form.ID.String()).Msg("Failed to get workspace member for authorization")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check access to form"})
+		return false
+	}
+
+	if !role.Allows(policy.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't have " + policy.Role.String() + " access to this form"})
+		return false
+	}
+
+	c.Set(formKey, form)
+	c.Set(roleKey, role)
+	return true
+}
+
+// authorizeWorkspace resolves the workspace of the request and checks the
+// caller's role in it. It writes the error response and returns false on
+// failure.
+func (a *Authorizer) authorizeWorkspace(c *gin.Context, policy Policy) bool {
+	workspaceID, err := uuid.Parse(c.Param(policy.Workspace))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return false
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return false
+	}
+
+	workspace, err := a.workspaces.GetWorkspaceByID(c.Request.Context(), workspaceID)
+	if err != nil {
+		if err.Error() == "workspace not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			return false
+		}
+		log.Error().Err(err).Str("workspace_id", workspaceID.String()).Msg("Failed to get workspace for authorization")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workspace"})
+		return false
+	}
+
+	member, err := a.workspaces.GetMember(c.Request.Context(), workspaceID, userID)
+	if err != nil && err.Error() != "member not found" {
+		log.Error().Err(err).Str("workspace_id", workspaceID.String()).Msg("Failed to get workspace member for authorization")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workspace"})
+		return false
+	}
+
+	if member == nil || !member.Role.Allows(policy.WorkspaceRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't have " + string(policy.WorkspaceRole) + " access to this workspace"})
+		return false
+	}
+
+	workspace.Role = member.Role
+	c.Set(workspaceKey, workspace)
+	c.Set(memberKey, member)
+	return true
+}
+
+// Role returns the role of a user on a form. The author owns the form, and
+// members of the form's workspace get the role their workspace role grants.
+func (a *Authorizer) Role(ctx context.Context, form *model.Form, userID uuid.UUID) (Role, error) {
+	if form.AuthorID == userID {
+		return RoleOwner, nil
+	}
+
+	if form.WorkspaceID == nil {
+		return RoleNone, nil
+	}
+
+	member, err := a.workspaces.GetMember(ctx, *form.WorkspaceID, userID)
+	if err != nil {
+		if err.Error() == "member not found" {
+			return RoleNone, nil
+		}
+		return RoleNone, err
+	}
+
+	return roleInWorkspace[member.Role], nil
+}
+
+// FormFrom returns the form the request was authorized for
+func FormFrom(c *gin.Context) (*model.Form, bool) {
+	value, exists := c.Get(formKey)
+	if !exists {
+		return nil, false
+	}
+	form, ok := value.(*model.Form)
+	return form, ok
+}
+
+// RoleFrom returns the caller's role on the form the request was authorized for
+func RoleFrom(c *gin.Context) Role {
+	if role, ok := c.Get(roleKey); ok {
+		return role.(Role)
+	}
+	return RoleNone
+}
+
+// WorkspaceFrom returns the workspace the request was authorized for, with
+// the caller's role in it
+func WorkspaceFrom(c *gin.Context) (*model.Workspace, bool) {
+	value, exists := c.Get(workspaceKey)
+	if !exists {
+		return nil, false
+	}
+	workspace, ok := value.(*model.Workspace)
+	return workspace, ok
+}
+
+// MemberFrom returns the caller's membership of the workspace the request
+// was authorized for
+func MemberFrom(c *gin.Context) (*model.WorkspaceMember, bool) {
+	value, exists := c.Get(memberKey)
+	if !exists {
+		return nil, false
+	}
+	member, ok := value.(*model.WorkspaceMember)
+	return member, ok
+}
+
+// APIKeyFrom returns the API key the request was authenticated with
+func APIKeyFrom(c *gin.Context) (*model.APIKey, bool) {
+	value, exists := c.Get(APIKeyKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*model.APIKey)
+	return key, ok
+}
+
+// capitalize upper-cases the first letter of a resource name
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,432 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const (
+	userHeader   = "X-Test-User"
+	scopesHeader = "X-Test-Key-Scopes" // Signs in with an API key with these comma separated scopes
+)
+
+type fakeStore struct {
+	forms      map[uuid.UUID]*model.Form
+	questions  map[uuid.UUID]*model.Question
+	responses  map[uuid.UUID]*model.FilledForm
+	webhooks   map[uuid.UUID]*model.Webhook
+	workspaces map[uuid.UUID]*model.Workspace
+	members    map[uuid.UUID]*model.WorkspaceMember // By user ID
+	err        error
+}
+
+func (s *fakeStore) GetFormByID(ctx context.Context, id uuid.UUID) (*model.Form, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if form, ok := s.forms[id]; ok {
+		return form, nil
+	}
+	return nil, errors.New("form not found")
+}
+
+func (s *fakeStore) GetFormBySlug(ctx context.Context, slug string) (*model.Form, error) {
+	for _, form := range s.forms {
+		if form.Slug == slug {
+			return form, nil
+		}
+	}
+	return nil, errors.New("form not found")
+}
+
+func (s *fakeStore) GetQuestionByID(ctx context.Context, id uuid.UUID) (*model.Question, error) {
+	if question, ok := s.questions[id]; ok {
+		return question, nil
+	}
+	return nil, errors.New("question not found")
+}
+
+func (s *fakeStore) GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error) {
+	if response, ok := s.responses[id]; ok {
+		return response, nil
+	}
+	return nil, errors.New("response not found")
+}
+
+func (s *fakeStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	if hook, ok := s.webhooks[id]; ok {
+		return hook, nil
+	}
+	return nil, errors.New("webhook not found")
+}
+
+func (s *fakeStore) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	if workspace, ok := s.workspaces[id]; ok {
+		return workspace, nil
+	}
+	return nil, errors.New("workspace not found")
+}
+
+func (s *fakeStore) GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error) {
+	if member, ok := s.members[userID]; ok && member.WorkspaceID == workspaceID {
+		return member, nil
+	}
+	return nil, errors.New("member not found")
+}
+
+// authenticateByHeader treats the user ID in a test header as signed in
+func authenticateByHeader(c *gin.Context) bool {
+	userID := c.GetHeader(userHeader)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication token"})
+		return false
+	}
+	c.Set("user_id", userID)
+	if scopes, ok := c.Request.Header[scopesHeader]; ok {
+		key := &model.APIKey{ID: uuid.New(), Scopes: model.JSONStringArray{}}
+		if scopes[0] != "" {
+			key.Scopes = strings.Split(scopes[0], ",")
+		}
+		c.Set(APIKeyKey, key)
+	}
+	return true
+}
+
+type fixture struct {
+	owner     uuid.UUID
+	form      *model.Form
+	question  *model.Question
+	response  *model.FilledForm
+	webhook   *model.Webhook
+	workspace *model.Workspace
+	store     *fakeStore
+	router    *gin.Engine
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	f := &fixture{owner: uuid.New()}
+	f.form = &model.Form{ID: uuid.New(), AuthorID: f.owner, Slug: "survey"}
+	f.question = &model.Question{ID: uuid.New(), FormID: f.form.ID}
+	f.response = &model.FilledForm{ID: uuid.New(), FormID: f.form.ID}
+	f.webhook = &model.Webhook{ID: uuid.New(), FormID: f.form.ID}
+	f.workspace = &model.Workspace{ID: uuid.New(), Name: "Research"}
+	f.store = &fakeStore{
+		forms:      map[uuid.UUID]*model.Form{f.form.ID: f.form},
+		questions:  map[uuid.UUID]*model.Question{f.question.ID: f.question},
+		responses:  map[uuid.UUID]*model.FilledForm{f.response.ID: f.response},
+		webhooks:   map[uuid.UUID]*model.Webhook{f.webhook.ID: f.webhook},
+		workspaces: map[uuid.UUID]*model.Workspace{f.workspace.ID: f.workspace},
+		members:    map[uuid.UUID]*model.WorkspaceMember{},
+	}
+
+	authorizer := NewAuthorizer(authenticateByHeader, f.store, f.store, f.store, f.store, f.store)
+	f.router = gin.New()
+	f.router.Use(authorizer.Middleware())
+
+	// Echo what the middleware resolved
+	handler := func(c *gin.Context) {
+		body := gin.H{"role": RoleFrom(c).String()}
+		if form, ok := FormFrom(c); ok {
+			body["form_id"] = form.ID.String()
+		}
+		if workspace, ok := WorkspaceFrom(c); ok {
+			body["workspace_id"] = workspace.ID.String()
+			body["workspace_role"] = string(workspace.Role)
+		}
+		c.JSON(http.StatusOK, body)
+	}
+	for _, route := range Routes() {
+		method, path, _ := strings.Cut(route, " ")
+		f.router.Handle(method, path, handler)
+	}
+	f.router.GET("/api/unlisted", handler)
+
+	return f
+}
+
+// join adds a user to the fixture's workspace with a role
+func (f *fixture) join(role model.WorkspaceRole) uuid.UUID {
+	userID := uuid.New()
+	f.store.members[userID] = &model.WorkspaceMember{WorkspaceID: f.workspace.ID, UserID: userID, Role: role}
+	return userID
+}
+
+func (f *fixture) do(method, path string, userID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if userID != uuid.Nil {
+		req.Header.Set(userHeader, userID.String())
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRole_Allows(t *testing.T) {
+	assert.True(t, RoleOwner.Allows(RoleEditor))
+	assert.True(t, RoleEditor.Allows(RoleEditor))
+	assert.True(t, RoleEditor.Allows(RoleAnalyst))
+	assert.True(t, RoleAnalyst.Allows(RoleViewer))
+	assert.False(t, RoleAnalyst.Allows(RoleEditor))
+	assert.False(t, RoleViewer.Allows(RoleAnalyst))
+	assert.False(t, RoleNone.Allows(RoleViewer))
+}
+
+func TestMiddleware_PublicRoute(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodGet, "/api/form/slug/survey", uuid.Nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMiddleware_AuthenticatedRoute(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodGet, "/api/form/", uuid.Nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = f.do(http.MethodGet, "/api/form/", uuid.New())
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMiddleware_UnlistedRouteIsRefused(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodGet, "/api/unlisted", f.owner)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMiddleware_UnmatchedRoutePassesThrough(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodGet, "/api/nothing-here", uuid.Nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMiddleware_FormRoutes(t *testing.T) {
+	f := newFixture(t)
+	stranger := uuid.New()
+
+	paths := map[string]string{
+		"form id":  "/api/form/" + f.form.ID.String() + "/stats",
+		"slug":     "/api/form/submissions/" + f.form.Slug,
+		"question": "/api/questions/" + f.question.ID.String(),
+		"response": "/api/response/" + f.response.ID.String(),
+		"webhook":  "/api/webhooks/" + f.webhook.ID.String(),
+	}
+
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			w := f.do(http.MethodGet, path, uuid.Nil)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			w = f.do(http.MethodGet, path, stranger)
+			assert.Equal(t, http.StatusForbidden, w.Code)
+
+			w = f.do(http.MethodGet, path, f.owner)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `"form_id":"`+f.form.ID.String()+`"`)
+			assert.Contains(t, w.Body.String(), `"role":"owner"`)
+		})
+	}
+}
+
+func TestMiddleware_ResolveErrors(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		errMsg string
+	}{
+		{"invalid form ID", "/api/form/not-a-uuid/stats", http.StatusBadRequest, "Invalid form ID"},
+		{"unknown form", "/api/form/" + uuid.NewString() + "/stats", http.StatusNotFound, "Form not found"},
+		{"unknown slug", "/api/form/submissions/missing", http.StatusNotFound, "Form not found"},
+		{"invalid question ID", "/api/questions/nope", http.StatusBadRequest, "Invalid question ID"},
+		{"unknown question", "/api/questions/" + uuid.NewString(), http.StatusNotFound, "Question not found"},
+		{"unknown response", "/api/response/" + uuid.NewString(), http.StatusNotFound, "Response not found"},
+		{"unknown webhook", "/api/webhooks/" + uuid.NewString(), http.StatusNotFound, "Webhook not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := f.do(http.MethodGet, tt.path, f.owner)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.errMsg)
+		})
+	}
+}
+
+func TestMiddleware_WorkspaceMembersShareForms(t *testing.T) {
+	f := newFixture(t)
+	f.form.WorkspaceID = &f.workspace.ID
+
+	viewer := f.join(model.WorkspaceRoleViewer)
+	analyst := f.join(model.WorkspaceRoleAnalyst)
+	editor := f.join(model.WorkspaceRoleEditor)
+	admin := f.join(model.WorkspaceRoleAdmin)
+
+	formPath := "/api/form/" + f.form.ID.String()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status map[uuid.UUID]int
+	}{
+		{"read questions", http.MethodGet, formPath + "/questions", map[uuid.UUID]int{viewer: 200, analyst: 200, editor: 200, admin: 200}},
+		{"read stats", http.MethodGet, formPath + "/stats", map[uuid.UUID]int{viewer: 403, analyst: 200, editor: 200, admin: 200}},
+		{"update form", http.MethodPut, formPath, map[uuid.UUID]int{viewer: 403, analyst: 403, editor: 200, admin: 200}},
+		{"delete form", http.MethodDelete, formPath, map[uuid.UUID]int{viewer: 403, analyst: 403, editor: 403, admin: 200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for userID, status := range tt.status {
+				w := f.do(tt.method, tt.path, userID)
+				assert.Equal(t, status, w.Code, "role %s", f.store.members[userID].Role)
+			}
+		})
+	}
+}
+
+func TestMiddleware_FormOutsideWorkspace(t *testing.T) {
+	f := newFixture(t)
+	member := f.join(model.WorkspaceRoleAdmin)
+
+	w := f.do(http.MethodGet, "/api/form/"+f.form.ID.String()+"/questions", member)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMiddleware_WorkspaceRoutes(t *testing.T) {
+	f := newFixture(t)
+	viewer := f.join(model.WorkspaceRoleViewer)
+	admin := f.join(model.WorkspaceRoleAdmin)
+	path := "/api/workspaces/" + f.workspace.ID.String()
+
+	w := f.do(http.MethodGet, path, uuid.Nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = f.do(http.MethodGet, path, uuid.New())
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = f.do(http.MethodGet, path, viewer)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"workspace_id":"`+f.workspace.ID.String()+`"`)
+	assert.Contains(t, w.Body.String(), `"workspace_role":"viewer"`)
+
+	w = f.do(http.MethodPost, path+"/invitations", viewer)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "admin access to this workspace")
+
+	w = f.do(http.MethodPost, path+"/invitations", admin)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = f.do(http.MethodGet, "/api/workspaces/nope", admin)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid workspace ID")
+
+	w = f.do(http.MethodGet, "/api/workspaces/"+uuid.NewString(), admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Workspace not found")
+}
+
+func TestMiddleware_APIKeyScopes(t *testing.T) {
+	f := newFixture(t)
+	formPath := "/api/form/" + f.form.ID.String()
+
+	withKey := func(method, path, scopes string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(userHeader, f.owner.String())
+		req.Header.Set(scopesHeader, scopes)
+		w := httptest.NewRecorder()
+		f.router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		scopes string
+		status int
+		errMsg string
+	}{
+		{"read with forms:read", http.MethodGet, formPath + "/questions", "forms:read", http.StatusOK, ""},
+		{"write without forms:write", http.MethodPut, formPath, "forms:read", http.StatusForbidden, "API key is missing the forms:write scope"},
+		{"write with forms:write", http.MethodPut, formPath, "forms:write", http.StatusOK, ""},
+		{"responses without responses:read", http.MethodGet, formPath + "/stats", "forms:read,forms:write", http.StatusForbidden, "API key is missing the responses:read scope"},
+		{"responses with responses:read", http.MethodGet, formPath + "/stats", "responses:read", http.StatusOK, ""},
+		{"list forms", http.MethodGet, "/api/form/", "forms:read", http.StatusOK, ""},
+		{"session only route", http.MethodGet, formPath + "/webhooks", "forms:read,forms:write,responses:read", http.StatusForbidden, "cannot be called with an API key"},
+		{"keys cannot manage keys", http.MethodPost, "/api/user/api-keys", "forms:write", http.StatusForbidden, "cannot be called with an API key"},
+		{"no scopes", http.MethodGet, "/api/form/", "", http.StatusForbidden, "API key is missing the forms:read scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := withKey(tt.method, tt.path, tt.scopes)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.errMsg)
+		})
+	}
+
+	// A key acts with its user's role
+	req := httptest.NewRequest(http.MethodGet, formPath+"/questions", nil)
+	req.Header.Set(userHeader, uuid.NewString())
+	req.Header.Set(scopesHeader, "forms:read")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMiddleware_StoreFailure(t *testing.T) {
+	f := newFixture(t)
+	f.store.err = errors.New("connection refused")
+
+	w := f.do(http.MethodGet, "/api/form/"+f.form.ID.String()+"/stats", f.owner)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Failed to get form")
+}
+
+func TestPolicies(t *testing.T) {
+	// Deleting a form and its webhooks' secrets are for the owner only
+	for _, route := range []string{
+		RouteKey(http.MethodDelete, "/api/form/:id"),
+		RouteKey(http.MethodGet, "/api/form/:id/webhooks"),
+		RouteKey(http.MethodGet, "/api/webhooks/:id"),
+	} {
+		policy, ok := routePolicies[route]
+		require.True(t, ok, route)
+		assert.Equal(t, RoleOwner, policy.Role, route)
+	}
+
+	for route, policy := range routePolicies {
+		if policy.Resource != nil {
+			assert.NotEqual(t, RoleNone, policy.Role, route)
+			assert.False(t, policy.Public, route)
+			assert.Empty(t, policy.Workspace, route)
+		}
+		if policy.Workspace != "" {
+			assert.True(t, policy.WorkspaceRole.IsValid(), route)
+			assert.False(t, policy.Public, route)
+		}
+		if policy.Scope != "" {
+			assert.True(t, policy.Scope.IsValid(), route)
+			assert.False(t, policy.Public, route)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+var (
+	errInvalidID = errors.New("invalid ID")
+	errNotFound  = errors.New("not found")
+)
+
+// Resource finds the form a route refers to from one of its path parameters
+type Resource struct {
+	Name  string // Named in error responses, e.g. "question"
+	Param string
+	load  func(ctx context.Context, a *Authorizer, value string) (*model.Form, error)
+}
+
+// Policy is the access rule of a route
+type Policy struct {
+	Public        bool                // Anyone may call the route; it checks any token it needs itself
+	Resource      *Resource           // Form the caller needs a role on; nil when signing in is enough
+	Role          Role                // Minimum role on the form
+	Workspace     string              // Path parameter of the workspace the caller needs a role in
+	WorkspaceRole model.WorkspaceRole // Minimum role in the workspace
+	Scope         model.APIKeyScope   // Scope an API key needs; without one the route needs a session
+}
+
+// RouteKey identifies a route by its method and path pattern
+func RouteKey(method, path string) string {
+	return method + " " + path
+}
+
+// Lookup returns the policy of a route
+func Lookup(method, path string) (Policy, bool) {
+	policy, ok := routePolicies[RouteKey(method, path)]
+	return policy, ok
+}
+
+// Routes returns the keys of every route with a policy
+func Routes() []string {
+	routes := make([]string, 0, len(routePolicies))
+	for route := range routePolicies {
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// FormID finds the form by the ID in a path parameter
+func FormID(param string) *Resource {
+	return &Resource{Name: "form", Param: param, load: func(ctx context.Context, a *Authorizer, value string) (*model.Form, error) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errInvalidID
+		}
+		return notFound(a.forms.GetFormByID(ctx, id))
+	}}
+}
+
+// FormSlug finds the form by the slug in a path parameter
+func FormSlug(param string) *Resource {
+	return &Resource{Name: "form", Param: param, load: func(ctx context.Context, a *Authorizer, value string) (*model.Form, error) {
+		return notFound(a.forms.GetFormBySlug(ctx, value))
+	}}
+}
+
+// QuestionID finds the form of the question whose ID is in a path parameter
+func QuestionID(param string) *Resource {
+	return &Resource{Name: "question", Param: param, load: func(ctx context.Context, a *Authorizer, value string) (*model.Form, error) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errInvalidID
+		}
+		question, err := a.questions.GetQuestionByID(ctx, id)
+		if err != nil {
+			return notFound(nil, err)
+		}
+		return notFound(a.forms.GetFormByID(ctx, question.FormID))
+	}}
+}
+
+// ResponseID finds the form of the response whose ID is in a path parameter
+func ResponseID(param string) *Resource {
+	return &Resource{Name: "response", Param: param, load: func(ctx context.Context, a *Authorizer, value string) (*model.Form, error) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errInvalidID
+		}
+		response, err := a.responses.GetResponseByID(ctx, id)
+		if err != nil {
+			return notFound(nil, err)
+		}
+		return notFound(a.forms.GetFormByID(ctx, response.FormID))
+	}}
+}
+
+// WebhookID finds the form of the webhook whose ID is in a path parameter
+func WebhookID(param string) *Resource {
+	return &Resource{Name: "webhook", Param: param, load: func(ctx context.Context, a *Authorizer, value string) (*model.Form, error) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errInvalidID
+		}
+		hook, err := a.webhooks.GetWebhookByID(ctx, id)
+		if err != nil {
+			return notFound(nil, err)
+		}
+		return notFound(a.forms.GetFormByID(ctx, hook.FormID))
+	}}
+}
+
+// notFound turns the "... not found" errors of the repositories into errNotFound
+func notFound(form *model.Form, err error) (*model.Form, error) {
+	if err == nil {
+		return form, nil
+	}
+	switch err.Error() {
+	case "form not found", "question not found", "response not found", "webhook not found":
+		return nil, errNotFound
+	}
+	return nil, err
+}
+
+var (
+	public        = Policy{Public: true}
+	authenticated = Policy{}
+)
+
+// Scopes of API keys
+const (
+	formsRead     = model.APIKeyScopeFormsRead
+	formsWrite    = model.APIKeyScopeFormsWrite
+	responsesRead = model.APIKeyScopeResponsesRead
+)
+
+// inWorkspace requires a role in the workspace whose ID is the id parameter
+func inWorkspace(role model.WorkspaceRole) Policy {
+	return Policy{Workspace: "id", WorkspaceRole: role}
+}
+
+// routePolicies lists the policy of every route under /api. API keys may
+// only call routes with a scope.
+var routePolicies = map[string]Policy{
+	// Accounts
+	RouteKey(http.MethodPost, "/api/auth/register"): public,
+	RouteKey(http.MethodPost, "/api/auth/login"):    public,
+	RouteKey(http.MethodPost, "/api/auth/token"):    public,
+	RouteKey(http.MethodPost, "/api/auth/refresh"):  public, // Refresh tokens are checked by the handler
+	RouteKey(http.MethodPost, "/api/auth/revoke"):   public,
+	RouteKey(http.MethodPost, "/api/auth/logout"):   authenticated,
+	RouteKey(http.MethodGet, "/api/user/"):          authenticated,
+	RouteKey(http.MethodPut, "/api/user/"):          authenticated,
+
+	// API keys of the caller; keys cannot manage keys
+	RouteKey(http.MethodGet, "/api/user/api-keys"):        authenticated,
+	RouteKey(http.MethodPost, "/api/user/api-keys"):       authenticated,
+	RouteKey(http.MethodDelete, "/api/user/api-keys/:id"): authenticated,
+
+	// Sessions of the caller
+	RouteKey(http.MethodGet, "/api/user/sessions"):        authenticated,
+	RouteKey(http.MethodDelete, "/api/user/sessions"):     authenticated,
+	RouteKey(http.MethodDelete, "/api/user/sessions/:id"): authenticated,
+
+	// Respondents; edits and deletions check the response's edit token
+	RouteKey(http.MethodGet, "/api/form/slug/:slug"): public,
+	RouteKey(http.MethodPost, "/api/response"):       public,
+	RouteKey(http.MethodPut, "/api/response/:id"):    public,
+	RouteKey(http.MethodDelete, "/api/response/:id"): public,
+
+	// Forms of the caller
+	RouteKey(http.MethodGet, "/api/form/"):           {Scope: formsRead},
+	RouteKey(http.MethodPost, "/api/form/"):          {Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/import"):    {Scope: formsWrite},
+	RouteKey(http.MethodGet, "/api/dashboard/"):      {Scope: formsRead},
+	RouteKey(http.MethodGet, "/api/dashboard/stats"): {Scope: formsRead},
+
+	// A form
+	RouteKey(http.MethodPut, "/api/form/:id"):                {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodDelete, "/api/form/:id"):             {Resource: FormID("id"), Role: RoleOwner, Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/:id/publish"):       {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/:id/preview-token"): {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/open/:slug"):        {Resource: FormSlug("slug"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/close/:slug"):       {Resource: FormSlug("slug"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodPost, "/api/form/:id/duplicate"):     {Resource: FormID("id"), Role: RoleViewer, Scope: formsWrite},
+	RouteKey(http.MethodGet, "/api/form/:id/definition"):     {Resource: FormID("id"), Role: RoleViewer, Scope: formsRead},
+	RouteKey(http.MethodPost, "/api/form/:id/template"):      {Resource: FormID("id"), Role: RoleEditor},
+	RouteKey(http.MethodPut, "/api/form/:id/workspace"):      {Resource: FormID("id"), Role: RoleOwner},
+
+	// Versions of a form
+	RouteKey(http.MethodGet, "/api/form/:id/versions"):               {Resource: FormID("id"), Role: RoleViewer, Scope: formsRead},
+	RouteKey(http.MethodGet, "/api/form/:id/versions/:version"):      {Resource: FormID("id"), Role: RoleViewer, Scope: formsRead},
+	RouteKey(http.MethodGet, "/api/form/:id/versions/:version/diff"): {Resource: FormID("id"), Role: RoleViewer, Scope: formsRead},
+
+	// Responses to a form
+	RouteKey(http.MethodGet, "/api/form/submissions/:slug"):            {Resource: FormSlug("slug"), Role: RoleAnalyst, Scope: responsesRead},
+	RouteKey(http.MethodGet, "/api/form/:id/export"):                   {Resource: FormID("id"), Role: RoleAnalyst, Scope: responsesRead},
+	RouteKey(http.MethodGet, "/api/form/:id/analytics"):                {Resource: FormID("id"), Role: RoleAnalyst, Scope: responsesRead},
+	RouteKey(http.MethodGet, "/api/form/:id/stats"):                    {Resource: FormID("id"), Role: RoleAnalyst, Scope: responsesRead},
+	RouteKey(http.MethodGet, "/api/form/:id/responses/search"):         {Resource: FormID("id"), Role: RoleAnalyst, Scope: responsesRead},
+	RouteKey(http.MethodPatch, "/api/form/:id/responses/:responseId"):  {Resource: FormID("id"), Role: RoleEditor},
+	RouteKey(http.MethodDelete, "/api/form/:id/responses/:responseId"): {Resource: FormID("id"), Role: RoleEditor},
+	RouteKey(http.MethodPost, "/api/form/:id/responses/delete"):        {Resource: FormID("id"), Role: RoleEditor},
+	RouteKey(http.MethodGet, "/api/response/:id"):                      {Resource: ResponseID("id"), Role: RoleAnalyst, Scope: responsesRead},
+
+	// Questions of a form
+	RouteKey(http.MethodPost, "/api/form/:id/questions"):        {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodGet, "/api/form/:id/questions"):         {Resource: FormID("id"), Role: RoleViewer, Scope: formsRead},
+	RouteKey(http.MethodPost, "/api/form/:id/questions/batch"):  {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodPut, "/api/form/:id/questions/reorder"): {Resource: FormID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodGet, "/api/questions/:id"):              {Resource: QuestionID("id"), Role: RoleViewer, Scope: formsRead},
+	RouteKey(http.MethodPut, "/api/questions/:id"):              {Resource: QuestionID("id"), Role: RoleEditor, Scope: formsWrite},
+	RouteKey(http.MethodDelete, "/api/questions/:id"):           {Resource: QuestionID("id"), Role: RoleEditor, Scope: formsWrite},
+
+	// Webhooks of a form; they hold signing secrets
+	RouteKey(http.MethodPost, "/api/form/:id/webhooks"):                             {Resource: FormID("id"), Role: RoleOwner},
+	RouteKey(http.MethodGet, "/api/form/:id/webhooks"):                              {Resource: FormID("id"), Role: RoleOwner},
+	RouteKey(http.MethodGet, "/api/webhooks/:id"):                                   {Resource: WebhookID("id"), Role: RoleOwner},
+	RouteKey(http.MethodPut, "/api/webhooks/:id"):                                   {Resource: WebhookID("id"), Role: RoleOwner},
+	RouteKey(http.MethodDelete, "/api/webhooks/:id"):                                {Resource: WebhookID("id"), Role: RoleOwner},
+	RouteKey(http.MethodGet, "/api/webhooks/:id/deliveries"):                        {Resource: WebhookID("id"), Role: RoleOwner},
+	RouteKey(http.MethodPost, "/api/webhooks/:id/deliveries/:deliveryId/redeliver"): {Resource: WebhookID("id"), Role: RoleOwner},
+
+	// Workspaces of the caller
+	RouteKey(http.MethodGet, "/api/workspaces/"):         authenticated,
+	RouteKey(http.MethodPost, "/api/workspaces/"):        authenticated,
+	RouteKey(http.MethodPost, "/api/invitations/accept"): authenticated,
+
+	// A workspace
+	RouteKey(http.MethodGet, "/api/workspaces/:id"):                              inWorkspace(model.WorkspaceRoleViewer),
+	RouteKey(http.MethodPut, "/api/workspaces/:id"):                              inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodDelete, "/api/workspaces/:id"):                           inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodGet, "/api/workspaces/:id/forms"):                        inWorkspace(model.WorkspaceRoleViewer),
+	RouteKey(http.MethodGet, "/api/workspaces/:id/members"):                      inWorkspace(model.WorkspaceRoleViewer),
+	RouteKey(http.MethodPut, "/api/workspaces/:id/members/:userId"):              inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodDelete, "/api/workspaces/:id/members/:userId"):           inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodPost, "/api/workspaces/:id/leave"):                       inWorkspace(model.WorkspaceRoleViewer),
+	RouteKey(http.MethodGet, "/api/workspaces/:id/invitations"):                  inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodPost, "/api/workspaces/:id/invitations"):                 inWorkspace(model.WorkspaceRoleAdmin),
+	RouteKey(http.MethodDelete, "/api/workspaces/:id/invitations/:invitationId"): inWorkspace(model.WorkspaceRoleAdmin),
+
+	// Templates belong to users; handlers check the template's author
+	RouteKey(http.MethodGet, "/api/templates/"):           authenticated,
+	RouteKey(http.MethodGet, "/api/templates/:id"):        authenticated,
+	RouteKey(http.MethodPut, "/api/templates/:id"):        authenticated,
+	RouteKey(http.MethodDelete, "/api/templates/:id"):     authenticated,
+	RouteKey(http.MethodPost, "/api/templates/:id/forms"): authenticated,
+}
@@ -0,0 +1,52 @@
+// Package cleanup periodically purges expired sessions
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Store is the persistence the cleaner needs
+type Store interface {
+	CleanupExpiredSessions(ctx context.Context) error
+}
+
+// Cleaner periodically deletes expired sessions. Deleting is idempotent, so
+// every replica can run its own cleaner.
+type Cleaner struct {
+	store    Store
+	interval time.Duration
+}
+
+// NewCleaner creates a cleaner purging expired sessions every interval
+func NewCleaner(store Store, interval time.Duration) *Cleaner {
+	return &Cleaner{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Run purges expired sessions until ctx is cancelled
+func (c *Cleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick purges the sessions that have expired
+func (c *Cleaner) Tick(ctx context.Context) {
+	if err := c.store.CleanupExpiredSessions(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to purge expired sessions")
+	}
+}
@@ -0,0 +1,38 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	calls int
+	err   error
+}
+
+func (s *fakeStore) CleanupExpiredSessions(ctx context.Context) error {
+	s.calls++
+	return s.err
+}
+
+func TestTick_PurgesExpiredSessions(t *testing.T) {
+	store := &fakeStore{}
+
+	NewCleaner(store, time.Minute).Tick(context.Background())
+
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestRun_StopsWhenCancelled(t *testing.T) {
+	store := &fakeStore{err: errors.New("connection refused")}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	NewCleaner(store, time.Minute).Run(ctx)
+
+	assert.Equal(t, 1, store.calls)
+}
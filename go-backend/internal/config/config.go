@@ -1,29 +1,45 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 )
 
+// defaultJWTSecret is the published placeholder for JWT_SECRET
+const defaultJWTSecret = "your_jwt_secret_here_change_in_production"
+
+// minSecretLength is the shortest accepted signing secret, in bytes
+const minSecretLength = 32
+
+// MaxAccessTokenTTL bounds ACCESS_TOKEN_TTL. Access tokens are not checked
+// against revocation, so a revoked sign-in keeps working until they expire.
+const MaxAccessTokenTTL = time.Hour
+
 // Config holds all configuration for the application
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	Auth     AuthConfig
-	App      AppConfig
+	Database   DatabaseConfig
+	Server     ServerConfig
+	Auth       AuthConfig
+	Forms      FormsConfig
+	Workspaces WorkspacesConfig
+	Webhooks   WebhooksConfig
+	Responses  ResponsesConfig
+	App        AppConfig
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	Name     string
-	User     string
-	Password string
-	SSLMode  string
-	URL      string
+	Host        string
+	Port        int
+	Name        string
+	User        string
+	Password    string
+	SSLMode     string
+	URL         string
+	AutoMigrate bool
 }
 
 // ServerConfig holds server configuration
@@ -36,8 +52,39 @@ type ServerConfig struct {
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	JWTSecret     string
-	JWTExpiration time.Duration
+	JWTSecret         string
+	AccessTokenSecret string        // Signs stateless access tokens
+	JWTExpiration     time.Duration // How long a session stays valid without being used
+	AccessTokenTTL    time.Duration // How long a signed access token is valid; at most MaxAccessTokenTTL
+	RefreshTokenTTL   time.Duration // How long a refresh token can be exchanged
+	APIKeyRateLimit   int           // Requests per minute allowed for each API key
+
+	SessionCleanupInterval time.Duration // How often expired sessions are purged
+}
+
+// FormsConfig holds form configuration
+type FormsConfig struct {
+	ScheduleInterval   time.Duration // How often scheduled open/close times are applied
+	PreviewTokenSecret string        // Signs the tokens owners share to preview draft forms
+	PreviewTokenTTL    time.Duration
+}
+
+// WorkspacesConfig holds workspace configuration
+type WorkspacesConfig struct {
+	InvitationTTL time.Duration // How long an invitation to a workspace can be accepted
+}
+
+// WebhooksConfig holds webhook delivery configuration
+type WebhooksConfig struct {
+	AllowPrivateTargets bool // Lets webhooks reach loopback and private addresses, for local development
+}
+
+// ResponsesConfig holds form response configuration
+type ResponsesConfig struct {
+	StartTokenSecret     string
+	StartTokenTTL        time.Duration
+	FastThreshold        time.Duration
+	RespondentHashSecret string // Keys respondent identifier hashes and browser tokens
 }
 
 // AppConfig holds application configuration
@@ -52,12 +99,13 @@ type AppConfig struct {
 func Load() (*Config, error) {
 	cfg := &Config{
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			Name:     getEnv("DB_NAME", "anoq_db"),
-			User:     getEnv("DB_USER", "anoq_user"),
-			Password: getEnv("DB_PASSWORD", "anoq_password"),
-			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+			Host:        getEnv("DB_HOST", "localhost"),
+			Port:        getEnvAsInt("DB_PORT", 5432),
+			Name:        getEnv("DB_NAME", "anoq_db"),
+			User:        getEnv("DB_USER", "anoq_user"),
+			Password:    getEnv("DB_PASSWORD", "anoq_password"),
+			SSLMode:     getEnv("DB_SSL_MODE", "disable"),
+			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", false),
 		},
 		Server: ServerConfig{
 			Port:         getEnvAsInt("SERVER_PORT", 8080),
@@ -66,8 +114,31 @@ func Load() (*Config, error) {
 			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
 		},
 		Auth: AuthConfig{
-			JWTSecret:     getEnv("JWT_SECRET", "your_jwt_secret_here_change_in_production"),
-			JWTExpiration: getEnvAsDuration("JWT_EXPIRATION", 24*time.Hour),
+			JWTSecret:         getEnv("JWT_SECRET", defaultJWTSecret),
+			AccessTokenSecret: getEnv("ACCESS_TOKEN_SECRET", ""),
+			JWTExpiration:     getEnvAsDuration("JWT_EXPIRATION", 24*time.Hour),
+			AccessTokenTTL:    getEnvAsDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:   getEnvAsDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			APIKeyRateLimit:   getEnvAsInt("API_KEY_RATE_LIMIT", 60),
+
+			SessionCleanupInterval: getEnvAsDuration("SESSION_CLEANUP_INTERVAL", time.Hour),
+		},
+		Forms: FormsConfig{
+			ScheduleInterval:   getEnvAsDuration("FORM_SCHEDULE_INTERVAL", 30*time.Second),
+			PreviewTokenSecret: getEnv("PREVIEW_TOKEN_SECRET", ""),
+			PreviewTokenTTL:    getEnvAsDuration("PREVIEW_TOKEN_TTL", 7*24*time.Hour),
+		},
+		Workspaces: WorkspacesConfig{
+			InvitationTTL: getEnvAsDuration("INVITATION_TTL", 7*24*time.Hour),
+		},
+		Webhooks: WebhooksConfig{
+			AllowPrivateTargets: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		Responses: ResponsesConfig{
+			StartTokenSecret:     getEnv("START_TOKEN_SECRET", ""),
+			StartTokenTTL:        getEnvAsDuration("START_TOKEN_TTL", 24*time.Hour),
+			FastThreshold:        getEnvAsDuration("FAST_SUBMISSION_THRESHOLD", 5*time.Second),
+			RespondentHashSecret: getEnv("RESPONDENT_HASH_SECRET", ""),
 		},
 		App: AppConfig{
 			Name:        getEnv("APP_NAME", "AnoQ Backend"),
@@ -77,6 +148,10 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if cfg.Auth.AccessTokenTTL <= 0 || cfg.Auth.AccessTokenTTL > MaxAccessTokenTTL {
+		return nil, fmt.Errorf("ACCESS_TOKEN_TTL must be positive and at most %s", MaxAccessTokenTTL)
+	}
+
 	// Build database URL
 	cfg.Database.URL = fmt.Sprintf(
 		"postgresql://%s:%s@%s:%d/%s?sslmode=%s",
@@ -91,6 +166,34 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// ValidateSecrets rejects signing secrets that are missing, too short or
+// left at the published default, since a guessable secret lets anyone forge
+// tokens. The server refuses to start unless it passes.
+func (c *Config) ValidateSecrets() error {
+	secrets := []struct {
+		env   string
+		value string
+	}{
+		{"ACCESS_TOKEN_SECRET", c.Auth.AccessTokenSecret},
+		{"START_TOKEN_SECRET", c.Responses.StartTokenSecret},
+		{"PREVIEW_TOKEN_SECRET", c.Forms.PreviewTokenSecret},
+		{"RESPONDENT_HASH_SECRET", c.Responses.RespondentHashSecret},
+	}
+
+	var errs []error
+	for _, secret := range secrets {
+		switch {
+		case secret.value == "":
+			errs = append(errs, fmt.Errorf("%s is required", secret.env))
+		case secret.value == defaultJWTSecret:
+			errs = append(errs, fmt.Errorf("%s must not be the default secret", secret.env))
+		case len(secret.value) < minSecretLength:
+			errs = append(errs, fmt.Errorf("%s must be at least %d bytes", secret.env, minSecretLength))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // IsProduction returns true if the environment is production
 func (c *Config) IsProduction() bool {
 	return c.App.Environment == "production"
@@ -119,6 +222,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidateSecrets(t *testing.T) {
+	strong := strings.Repeat("s", minSecretLength)
+
+	tests := []struct {
+		name    string
+		access  string
+		wantErr string
+	}{
+		{"strong", strong, ""},
+		{"missing", "", "ACCESS_TOKEN_SECRET is required"},
+		{"default", defaultJWTSecret, "ACCESS_TOKEN_SECRET must not be the default secret"},
+		{"too short", strong[1:], "ACCESS_TOKEN_SECRET must be at least 32 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Auth:      AuthConfig{AccessTokenSecret: tt.access},
+				Forms:     FormsConfig{PreviewTokenSecret: strong},
+				Responses: ResponsesConfig{StartTokenSecret: strong, RespondentHashSecret: strong},
+			}
+
+			err := cfg.ValidateSecrets()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_ValidateSecrets_NoFallback(t *testing.T) {
+	// A strong JWT secret no longer stands in for the other secrets
+	cfg := &Config{Auth: AuthConfig{JWTSecret: strings.Repeat("s", minSecretLength)}}
+
+	err := cfg.ValidateSecrets()
+	for _, env := range []string{"ACCESS_TOKEN_SECRET", "START_TOKEN_SECRET", "PREVIEW_TOKEN_SECRET", "RESPONDENT_HASH_SECRET"} {
+		assert.ErrorContains(t, err, env+" is required")
+	}
+}
+
+func TestLoad_AccessTokenTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		wantErr bool
+	}{
+		{"default", "", false},
+		{"short", "5m", false},
+		{"at the cap", "1h", false},
+		{"too long", "24h", true},
+		{"zero", "0s", true},
+		{"negative", "-1m", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ACCESS_TOKEN_TTL", tt.ttl)
+
+			cfg, err := Load()
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "ACCESS_TOKEN_TTL must be positive and at most 1h0m0s")
+				return
+			}
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, cfg.Auth.AccessTokenTTL, MaxAccessTokenTTL)
+		})
+	}
+}
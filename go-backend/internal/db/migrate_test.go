@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_sessions.sql":      {Data: []byte("CREATE TABLE sessions ();")},
+		"001_init.sql":              {Data: []byte("CREATE TABLE users ();")},
+		"001_init.down.sql":         {Data: []byte("DROP TABLE users;")},
+		"002_add_sessions.down.sql": {Data: []byte("DROP TABLE sessions;")},
+		"README.md":                 {Data: []byte("not a migration")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE users ();", migrations[0].Up)
+	assert.Equal(t, "DROP TABLE users;", migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "add_sessions", migrations[1].Name)
+}
+
+func TestLoadMigrations_MissingUp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_init.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+
+	_, err := LoadMigrations(fsys)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no up file")
+}
+
+func TestLoadMigrations_InvalidName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"init.sql": {Data: []byte("CREATE TABLE users ();")},
+	}
+
+	_, err := LoadMigrations(fsys)
+	require.Error(t, err)
+}
+
+func newMockMigrator(t *testing.T, migrations []Migration) (*Migrator, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	return &Migrator{
+		db:         &DB{DB: sqlx.NewDb(mockDB, "sqlmock")},
+		migrations: migrations,
+	}, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up_AppliesPendingInOrder(t *testing.T) {
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+		{Version: 2, Name: "add_sessions", Up: "CREATE TABLE sessions ();"},
+		{Version: 3, Name: "add_forms", Up: "CREATE TABLE forms ();"},
+	})
+
+	expectLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations ORDER BY version`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+	for _, m := range []struct {
+		version int64
+		name    string
+		script  string
+	}{
+		{2, "add_sessions", "CREATE TABLE sessions ();"},
+		{3, "add_forms", "CREATE TABLE forms ();"},
+	} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(m.script)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+			WithArgs(m.version, m.name).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_RollsBackFailedMigration(t *testing.T) {
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+	})
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE users ();")).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, 0, applied)
+	assert.Contains(t, err.Error(), "001_init")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_RollsBackLatest(t *testing.T) {
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"},
+		{Version: 2, Name: "add_sessions", Up: "CREATE TABLE sessions ();", Down: "DROP TABLE sessions;"},
+	})
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow(1, time.Now()).
+			AddRow(2, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE sessions;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	rolledBack, err := migrator.Down(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Baseline_RecordsWithoutRunning(t *testing.T) {
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+		{Version: 2, Name: "add_sessions", Up: "CREATE TABLE sessions ();"},
+		{Version: 3, Name: "add_forms", Up: "CREATE TABLE forms ();"},
+	})
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(int64(2), "add_sessions").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	recorded, err := migrator.Baseline(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, recorded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Baseline_UnknownVersion(t *testing.T) {
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+	})
+
+	_, err := migrator.Baseline(context.Background(), 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown migration version 4")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	appliedAt := time.Now()
+	migrator, mock := newMockMigrator(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+		{Version: 2, Name: "add_sessions", Up: "CREATE TABLE sessions ();"},
+	})
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+	expectUnlock(mock)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+	assert.False(t, statuses[1].Applied)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
@@ -0,0 +1,42 @@
+// Package edittoken generates the secret tokens respondents use to edit or
+// delete their own responses. Only the hash of a token is stored.
+package edittoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Header carries the edit token on edit and delete requests
+const Header = "X-Edit-Token"
+
+const tokenSize = 32
+
+// Generate returns a new token along with the hash to store
+func Generate() (token, hash string, err error) {
+	buf := make([]byte, tokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate edit token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, Hash(token), nil
+}
+
+// Hash returns the hex encoded SHA-256 of a token
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether the token hashes to hash
+func Matches(token, hash string) bool {
+	if token == "" || hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(Hash(token)), []byte(hash)) == 1
+}
@@ -0,0 +1,31 @@
+package edittoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	token, hash, err := Generate()
+	require.NoError(t, err)
+
+	assert.Len(t, token, 43)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, Hash(token), hash)
+
+	other, _, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestMatches(t *testing.T) {
+	token, hash, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, Matches(token, hash))
+	assert.False(t, Matches(token+"x", hash))
+	assert.False(t, Matches("", hash))
+	assert.False(t, Matches(token, ""))
+}
@@ -0,0 +1,230 @@
+// Package export writes form responses as CSV, JSONL or XLSX, one row per
+// response and one column per question
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// Format represents an export file format
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatXLSX  Format = "xlsx"
+)
+
+// MultiSelectMode represents how multi-select answers are flattened
+type MultiSelectMode string
+
+const (
+	// MultiSelectJoin puts all selected choices in one cell, joined by the separator
+	MultiSelectJoin MultiSelectMode = "join"
+	// MultiSelectColumns adds one column per choice, marked when the choice was selected
+	MultiSelectColumns MultiSelectMode = "columns"
+)
+
+// Defaults used when options are left empty
+const (
+	DefaultSeparator = "; "
+	SelectedMarker   = "1"
+)
+
+// Options configures an export
+type Options struct {
+	Format      Format
+	MultiSelect MultiSelectMode
+	Separator   string
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// IsValid returns true if the format is supported
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatJSONL || f == FormatXLSX
+}
+
+// IsValid returns true if the multi-select mode is supported
+func (m MultiSelectMode) IsValid() bool {
+	return m == MultiSelectJoin || m == MultiSelectColumns
+}
+
+// rowWriter writes rows of cells in a specific file format
+type rowWriter interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// column produces one cell of a row from a response
+type column struct {
+	header string
+	value  func(response *model.FilledForm, answers map[uuid.UUID]*model.FilledFormQuestion) string
+}
+
+// Exporter writes responses to an underlying writer
+type Exporter struct {
+	columns []column
+	writer  rowWriter
+}
+
+// New creates an exporter for the given questions and writes the header row
+func New(w io.Writer, questions []*model.Question, opts Options) (*Exporter, error) {
+	if opts.Format == "" {
+		opts.Format = FormatCSV
+	}
+	if opts.MultiSelect == "" {
+		opts.MultiSelect = MultiSelectJoin
+	}
+	if opts.Separator == "" {
+		opts.Separator = DefaultSeparator
+	}
+	if !opts.Format.IsValid() {
+		return nil, fmt.Errorf("unsupported export format %q", opts.Format)
+	}
+	if !opts.MultiSelect.IsValid() {
+		return nil, fmt.Errorf("unsupported multi-select mode %q", opts.MultiSelect)
+	}
+
+	columns := buildColumns(questions, opts)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+
+	var writer rowWriter
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		writer = newCSVWriter(w)
+	case FormatJSONL:
+		writer = newJSONLWriter(w, headers)
+	case FormatXLSX:
+		writer, err = newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// JSONL uses the headers as keys rather than writing them as a row
+	if opts.Format != FormatJSONL {
+		if err := writer.WriteRow(headers); err != nil {
+			return nil, fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	return &Exporter{
+		columns: columns,
+		writer:  writer,
+	}, nil
+}
+
+// WriteResponse writes a single response as one row
+func (e *Exporter) WriteResponse(response *model.FilledForm) error {
+	answers := make(map[uuid.UUID]*model.FilledFormQuestion, len(response.Answers))
+	for i := range response.Answers {
+		answers[response.Answers[i].QuestionID] = &response.Answers[i]
+	}
+
+	cells := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		cells[i] = col.value(response, answers)
+	}
+
+	return e.writer.WriteRow(cells)
+}
+
+// Close flushes any buffered output
+func (e *Exporter) Close() error {
+	return e.writer.Close()
+}
+
+// buildColumns returns the response metadata columns followed by one column
+// per question ordered by position
+func buildColumns(questions []*model.Question, opts Options) []column {
+	columns := []column{
+		{header: "Response ID", value: func(r *model.FilledForm, _ map[uuid.UUID]*model.FilledFormQuestion) string {
+			return r.ID.String()
+		}},
+		{header: "Submitted At", value: func(r *model.FilledForm, _ map[uuid.UUID]*model.FilledFormQuestion) string {
+			return r.CreatedAt.UTC().Format(time.RFC3339)
+		}},
+		{header: "Name", value: func(r *model.FilledForm, _ map[uuid.UUID]*model.FilledFormQuestion) string {
+			return stringValue(r.Name)
+		}},
+		{header: "Email", value: func(r *model.FilledForm, _ map[uuid.UUID]*model.FilledFormQuestion) string {
+			return stringValue(r.Email)
+		}},
+	}
+
+	ordered := make([]*model.Question, len(questions))
+	copy(ordered, questions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Position < ordered[j].Position
+	})
+
+	for _, question := range ordered {
+		questionID := question.ID
+
+		if question.HasChoices() && question.AllowMultiple && opts.MultiSelect == MultiSelectColumns {
+			for _, choice := range question.Choices {
+				choice := choice
+				columns = append(columns, column{
+					header: fmt.Sprintf("%s [%s]", question.QuestionText, choice),
+					value: func(_ *model.FilledForm, answers map[uuid.UUID]*model.FilledFormQuestion) string {
+						if answer, ok := answers[questionID]; ok {
+							for _, selected := range answer.SelectedChoices {
+								if selected == choice {
+									return SelectedMarker
+								}
+							}
+						}
+						return ""
+					},
+				})
+			}
+			continue
+		}
+
+		columns = append(columns, column{
+			header: question.QuestionText,
+			value: func(_ *model.FilledForm, answers map[uuid.UUID]*model.FilledFormQuestion) string {
+				answer, ok := answers[questionID]
+				if !ok {
+					return ""
+				}
+				if len(answer.SelectedChoices) > 0 {
+					return strings.Join(answer.SelectedChoices, opts.Separator)
+				}
+				return stringValue(answer.Answer)
+			},
+		})
+	}
+
+	return columns
+}
+
+// stringValue returns the string pointed to, or an empty string for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
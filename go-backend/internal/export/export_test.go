@@ -0,0 +1,165 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// fixture returns questions out of position order and one response answering them
+func fixture() ([]*model.Question, *model.FilledForm) {
+	colors := &model.Question{
+		ID:            uuid.New(),
+		QuestionText:  "Colors",
+		Type:          model.QuestionTypeMultipleChoice,
+		Position:      2,
+		Choices:       model.JSONStringArray{"Red", "Blue", "Green"},
+		AllowMultiple: true,
+	}
+	name := &model.Question{
+		ID:           uuid.New(),
+		QuestionText: "Nickname",
+		Type:         model.QuestionTypeBasic,
+		Position:     1,
+	}
+
+	response := &model.FilledForm{
+		ID:        uuid.New(),
+		Email:     stringPtr("jane@example.com"),
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Answers: []model.FilledFormQuestion{
+			{QuestionID: colors.ID, SelectedChoices: model.JSONStringArray{"Red", "Green"}},
+			{QuestionID: name.ID, Answer: stringPtr("JJ")},
+		},
+	}
+
+	return []*model.Question{colors, name}, response
+}
+
+func export(t *testing.T, opts Options) []byte {
+	questions, response := fixture()
+
+	var buf bytes.Buffer
+	exporter, err := New(&buf, questions, opts)
+	require.NoError(t, err)
+	require.NoError(t, exporter.WriteResponse(response))
+	require.NoError(t, exporter.Close())
+
+	return buf.Bytes()
+}
+
+func TestExport_CSVJoin(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, Options{Format: FormatCSV}))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, []string{"Response ID", "Submitted At", "Name", "Email", "Nickname", "Colors"}, records[0])
+	assert.NoError(t, uuid.Validate(records[1][0]))
+	assert.Equal(t, []string{"2024-03-01T12:00:00Z", "", "jane@example.com", "JJ", "Red; Green"}, records[1][1:])
+}
+
+func TestExport_CSVColumns(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, Options{Format: FormatCSV, MultiSelect: MultiSelectColumns}))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, []string{"Nickname", "Colors [Red]", "Colors [Blue]", "Colors [Green]"}, records[0][4:])
+	assert.Equal(t, []string{"JJ", SelectedMarker, "", SelectedMarker}, records[1][4:])
+}
+
+func TestExport_JSONL(t *testing.T) {
+	output := export(t, Options{Format: FormatJSONL, Separator: "|"})
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 1)
+	assert.True(t, strings.HasPrefix(lines[0], `{"Response ID":`), "columns should keep their order")
+
+	var row map[string]string
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, "JJ", row["Nickname"])
+	assert.Equal(t, "Red|Green", row["Colors"])
+}
+
+func TestExport_CSVEscapesFormulas(t *testing.T) {
+	question := &model.Question{ID: uuid.New(), QuestionText: "=Total", Type: model.QuestionTypeBasic, Position: 1}
+	var buf bytes.Buffer
+	exporter, err := New(&buf, []*model.Question{question}, Options{Format: FormatCSV})
+	require.NoError(t, err)
+
+	for _, answer := range []string{"=HYPERLINK(\"http://evil\")", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "plain"} {
+		require.NoError(t, exporter.WriteResponse(&model.FilledForm{
+			ID:      uuid.New(),
+			Answers: []model.FilledFormQuestion{{QuestionID: question.ID, Answer: stringPtr(answer)}},
+		}))
+	}
+	require.NoError(t, exporter.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	var cells []string
+	for _, record := range records {
+		cells = append(cells, record[4])
+	}
+	assert.Equal(t, []string{"'=Total", "'=HYPERLINK(\"http://evil\")", "'+1", "'-1", "'@SUM(A1)", "'\tcmd", "'\rcmd", "plain"}, cells)
+}
+
+func TestExport_JSONLDuplicateHeaders(t *testing.T) {
+	first := &model.Question{ID: uuid.New(), QuestionText: "Age", Type: model.QuestionTypeBasic, Position: 1}
+	second := &model.Question{ID: uuid.New(), QuestionText: "Age", Type: model.QuestionTypeBasic, Position: 2}
+	email := &model.Question{ID: uuid.New(), QuestionText: "Email", Type: model.QuestionTypeBasic, Position: 3}
+
+	var buf bytes.Buffer
+	exporter, err := New(&buf, []*model.Question{first, second, email}, Options{Format: FormatJSONL})
+	require.NoError(t, err)
+	require.NoError(t, exporter.WriteResponse(&model.FilledForm{
+		ID: uuid.New(),
+		Answers: []model.FilledFormQuestion{
+			{QuestionID: first.ID, Answer: stringPtr("30")},
+			{QuestionID: second.ID, Answer: stringPtr("31")},
+			{QuestionID: email.ID, Answer: stringPtr("a@example.com")},
+		},
+	}))
+	require.NoError(t, exporter.Close())
+
+	var row map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &row))
+	assert.Len(t, row, 7)
+	assert.Equal(t, "30", row["Age"])
+	assert.Equal(t, "31", row["Age (2)"])
+	assert.Equal(t, "", row["Email"])
+	assert.Equal(t, "a@example.com", row["Email (2)"])
+}
+
+func TestExport_XLSX(t *testing.T) {
+	file, err := excelize.OpenReader(bytes.NewReader(export(t, Options{Format: FormatXLSX})))
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows(xlsxSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Nickname", rows[0][4])
+	assert.Equal(t, "Red; Green", rows[1][5])
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, nil, Options{Format: "pdf"})
+	assert.Error(t, err)
+
+	_, err = New(&bytes.Buffer{}, nil, Options{MultiSelect: "nested"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// csvWriter writes rows as comma separated values
+type csvWriter struct {
+	w     *csv.Writer
+	cells []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	c.cells = c.cells[:0]
+	for _, cell := range cells {
+		c.cells = append(c.cells, escapeFormula(cell))
+	}
+	return c.w.Write(c.cells)
+}
+
+// escapeFormula prefixes cells that spreadsheet applications would run as a
+// formula with a quote, so respondents cannot inject formulas into exports
+func escapeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlWriter writes each row as a JSON object keyed by column header, keeping
+// the column order. Repeated headers are numbered so every key is unique.
+type jsonlWriter struct {
+	w       *bufio.Writer
+	headers [][]byte
+}
+
+func newJSONLWriter(w io.Writer, headers []string) *jsonlWriter {
+	encoded := make([][]byte, len(headers))
+	for i, header := range uniqueHeaders(headers) {
+		// Marshalling a string cannot fail
+		encoded[i], _ = json.Marshal(header)
+	}
+
+	return &jsonlWriter{
+		w:       bufio.NewWriter(w),
+		headers: encoded,
+	}
+}
+
+// uniqueHeaders numbers every repeat of a header, "Age", "Age (2)", "Age (3)"
+func uniqueHeaders(headers []string) []string {
+	seen := make(map[string]bool, len(headers))
+	unique := make([]string, len(headers))
+	for i, header := range headers {
+		key := header
+		for n := 2; seen[key]; n++ {
+			key = fmt.Sprintf("%s (%d)", header, n)
+		}
+		seen[key] = true
+		unique[i] = key
+	}
+	return unique
+}
+
+func (j *jsonlWriter) WriteRow(cells []string) error {
+	j.w.WriteByte('{')
+	for i, cell := range cells {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		value, _ := json.Marshal(cell)
+		j.w.Write(j.headers[i])
+		j.w.WriteByte(':')
+		j.w.Write(value)
+	}
+	// bufio.Writer keeps the first write error, so checking the last write is enough
+	_, err := j.w.WriteString("}\n")
+	return err
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
+
+// xlsxWriter writes rows to a single worksheet. The worksheet is streamed to a
+// temporary file and the workbook is written out on Close.
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+const xlsxSheet = "Responses"
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create worksheet: %w", err)
+	}
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create worksheet writer: %w", err)
+	}
+
+	return &xlsxWriter{
+		w:      w,
+		file:   file,
+		stream: stream,
+	}, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(cells))
+	for i, value := range cells {
+		values[i] = value
+	}
+
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to flush worksheet: %w", err)
+	}
+
+	_, err := x.file.WriteTo(x.w)
+	return err
+}
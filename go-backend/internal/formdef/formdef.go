@@ -0,0 +1,179 @@
+// Package formdef reads and writes form definitions as versioned JSON or
+// YAML documents, so forms can be moved between environments or kept in git
+package formdef
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// SchemaVersion is the version of the document schema written by Encode.
+// Decode rejects documents of any other version.
+const SchemaVersion = 1
+
+// MaxDocumentSize bounds the size of documents accepted by Decode
+const MaxDocumentSize = 1 << 20
+
+// Format represents a document file format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// IsValid returns true if the format is supported
+func (f Format) IsValid() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+// FormatFromContentType returns the format of a request body, defaulting to JSON
+func FormatFromContentType(contentType string) Format {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// FormatFromPath returns the format of a file from its extension, defaulting to JSON
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// Document is the portable form of a form: its settings and ordered questions
+// with a schema version. The slug is a default for imports, which may
+// override it.
+// @Description Versioned form definition for import and export
+type Document struct {
+	SchemaVersion int    `json:"schema_version" example:"1"`
+	Slug          string `json:"slug,omitempty" example:"customer-feedback-2023"`
+	model.FormDefinition
+}
+
+// Export captures a form and its questions in a document
+func Export(form *model.Form, questions []*model.Question) *Document {
+	return &Document{
+		SchemaVersion:  SchemaVersion,
+		Slug:           form.Slug,
+		FormDefinition: *model.NewFormDefinition(form, questions),
+	}
+}
+
+// ResolveSlug returns the slug of a form imported from the document, which
+// is the override when given and the document's slug otherwise
+func (d *Document) ResolveSlug(override string) (string, error) {
+	slug := strings.TrimSpace(override)
+	if slug == "" {
+		slug = strings.TrimSpace(d.Slug)
+	}
+	if slug == "" {
+		return "", errors.New("Slug is required")
+	}
+	if len(slug) > 255 {
+		return "", errors.New("Slug must be at most 255 characters")
+	}
+	return slug, nil
+}
+
+// Encode writes the document in the given format
+func Encode(w io.Writer, doc *Document, format Format) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode definition: %w", err)
+	}
+
+	if format != FormatYAML {
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	// JSON is valid YAML, so the JSON document is re-read as a YAML tree to
+	// keep the field names and order, then written in block style
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode definition: %w", err)
+	}
+	resetStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode definition: %w", err)
+	}
+	return encoder.Close()
+}
+
+// resetStyle drops the flow and quoting styles of a YAML tree so it is
+// written in block style, quoting only where needed
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// Decode reads a document in the given format and checks its schema version.
+// Unknown fields are rejected so typos are not silently ignored. The
+// definition itself is validated when a form is created from it.
+func Decode(r io.Reader, format Format) (*Document, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition: %w", err)
+	}
+	if len(data) > MaxDocumentSize {
+		return nil, fmt.Errorf("Definition must be at most %d bytes", MaxDocumentSize)
+	}
+
+	if format == FormatYAML {
+		// The document is converted to JSON so both formats share the
+		// field names and validation of the JSON decoder
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("Invalid YAML: %w", err)
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("Invalid YAML: %w", err)
+		}
+	}
+
+	var doc Document
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("Invalid definition: %w", err)
+	}
+
+	switch doc.SchemaVersion {
+	case SchemaVersion:
+	case 0:
+		return nil, errors.New("schema_version is required")
+	default:
+		return nil, fmt.Errorf("Unsupported schema_version %d, expected %d", doc.SchemaVersion, SchemaVersion)
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,138 @@
+package formdef
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+func sampleForm() (*model.Form, []*model.Question) {
+	maxResponses := 100
+	form := &model.Form{
+		ID:              uuid.New(),
+		Title:           "Team offsite",
+		Description:     "Tell us what works for you",
+		Slug:            "team-offsite",
+		MaxResponses:    &maxResponses,
+		DuplicatePolicy: model.DuplicatePolicyNone,
+		AnonymityLevel:  model.AnonymityIdentified,
+	}
+	attending := &model.Question{ID: uuid.New(), FormID: form.ID, QuestionText: "Attending?", Type: model.QuestionTypeYesNo, Position: 1, Required: true}
+	days := &model.Question{
+		ID: uuid.New(), FormID: form.ID, QuestionText: "Which days?", Type: model.QuestionTypeMultipleChoice, Position: 2,
+		Choices: model.JSONStringArray{"Monday", "yes", "123"}, AllowMultiple: true,
+		DisplayLogic: &model.DisplayLogic{Match: model.LogicMatchAll, Conditions: []model.DisplayCondition{{QuestionID: attending.ID, Operator: model.ConditionEquals, Value: "Yes"}}},
+	}
+	return form, []*model.Question{attending, days}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	form, questions := sampleForm()
+	doc := Export(form, questions)
+
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, doc, format))
+
+			decoded, err := Decode(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, doc, decoded)
+		})
+	}
+}
+
+func TestEncode_YAMLUsesBlockStyle(t *testing.T) {
+	form, questions := sampleForm()
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, Export(form, questions), FormatYAML))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "schema_version: 1\nslug: team-offsite\ntitle: Team offsite\n"), out)
+	assert.Contains(t, out, "    question_text: Which days?\n    type: multiple_choice\n")
+	// Strings that would read as other types stay quoted
+	assert.Contains(t, out, `- "123"`)
+}
+
+func TestDecode_YAMLWrittenByHand(t *testing.T) {
+	input := `
+schema_version: 1
+title: Feedback
+questions:
+  - question_text: How was it?
+    type: rating
+    required: true
+    scale_min: 1
+    scale_max: 5
+  - question_text: Anything else?
+    type: long_text
+`
+	doc, err := Decode(strings.NewReader(input), FormatYAML)
+	require.NoError(t, err)
+
+	form, questions, err := doc.FormDefinition.NewForm(uuid.New(), "", "feedback", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "Feedback", form.Title)
+	require.Len(t, questions, 2)
+	assert.Equal(t, 1, questions[0].Position)
+	assert.Equal(t, 2, questions[1].Position)
+	assert.True(t, questions[0].Required)
+}
+
+func TestDecode_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		errMsg string
+	}{
+		{"missing version", `{"title":"A","questions":[]}`, FormatJSON, "schema_version is required"},
+		{"future version", `{"schema_version":2,"title":"A","questions":[]}`, FormatJSON, "Unsupported schema_version 2"},
+		{"unknown field", `{"schema_version":1,"title":"A","questions":[{"question_txt":"Typo"}]}`, FormatJSON, "unknown field"},
+		{"unknown YAML field", "schema_version: 1\ntitel: A\n", FormatYAML, "unknown field"},
+		{"malformed YAML", "schema_version: [1\n", FormatYAML, "Invalid YAML"},
+		{"malformed JSON", `{"schema_version":`, FormatJSON, "Invalid definition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.input), tt.format)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestDocument_ResolveSlug(t *testing.T) {
+	doc := &Document{Slug: "from-file"}
+
+	slug, err := doc.ResolveSlug("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", slug)
+
+	slug, err = doc.ResolveSlug(" override ")
+	require.NoError(t, err)
+	assert.Equal(t, "override", slug)
+
+	_, err = (&Document{}).ResolveSlug("")
+	assert.EqualError(t, err, "Slug is required")
+}
+
+func TestFormatDetection(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatFromContentType("application/yaml"))
+	assert.Equal(t, FormatYAML, FormatFromContentType("text/yaml; charset=utf-8"))
+	assert.Equal(t, FormatJSON, FormatFromContentType("application/json"))
+	assert.Equal(t, FormatJSON, FormatFromContentType(""))
+
+	assert.Equal(t, FormatYAML, FormatFromPath("forms/offsite.YML"))
+	assert.Equal(t, FormatJSON, FormatFromPath("offsite.json"))
+	assert.Equal(t, FormatJSON, FormatFromPath("-"))
+}
@@ -0,0 +1,46 @@
+package formtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret", "test")
+	formID := uuid.New()
+	at := time.Now().Truncate(time.Millisecond)
+
+	got, err := signer.Verify(signer.Issue(formID, at), formID)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(got))
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret", "test")
+	formID := uuid.New()
+	token := signer.Issue(formID, time.Now())
+
+	tests := []struct {
+		name   string
+		token  string
+		formID uuid.UUID
+	}{
+		{"other form", token, uuid.New()},
+		{"other secret", NewSigner("other", "test").Issue(formID, time.Now()), formID},
+		{"other purpose", NewSigner("secret", "other").Issue(formID, time.Now()), formID},
+		{"tampered", token[:len(token)-2] + "AA", formID},
+		{"short payload", "AAAA." + token[len(token)-43:], formID},
+		{"garbage", "not-a-token", formID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token, tt.formID)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
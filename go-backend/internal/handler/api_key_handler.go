@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/apikey"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// APIKeyHandler handles API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyRepo repository.APIKeyRepo
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyRepo repository.APIKeyRepo) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateAPIKey handles POST /api/user/api-keys
+// @Summary Create an API key
+// @Description Create a key that calls the API on behalf of the caller, limited to its scopes: forms:read, forms:write and responses:read. The key is sent in the X-API-Key header and is returned only in this response.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param key body model.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} object{message=string,api_key=model.APIKey,key=string} "API key created successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body, scope or expiry"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var createReq model.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&createReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	key, prefix, keyHash, err := apikey.Generate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	apiKey, err := model.NewAPIKey(&createReq, userID, prefix, keyHash, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.apiKeyRepo.CreateAPIKey(c.Request.Context(), apiKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	// Only the hash is stored, so this is the one time the key is available
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created successfully",
+		"api_key": apiKey,
+		"key":     key,
+	})
+}
+
+// ListAPIKeys handles GET /api/user/api-keys
+// @Summary List API keys
+// @Description Get the caller's API keys that have not been revoked, newest first. Keys are identified by their prefix; the keys themselves are not returned.
+// @Tags API Keys
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{api_keys=[]model.APIKey} "API keys of the caller"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	keys, err := h.apiKeyRepo.ListAPIKeysByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+		return
+	}
+	if keys == nil {
+		keys = []*model.APIKey{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/user/api-keys/:id
+// @Summary Revoke an API key
+// @Description Revoke one of the caller's API keys. Requests with the key are refused from then on.
+// @Tags API Keys
+// @Produce json
+// @Security Bearer
+// @Param id path string true "API key ID"
+// @Success 200 {object} object{message=string} "API key revoked successfully"
+// @Failure 400 {object} object{error=string} "Invalid API key ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 404 {object} object{error=string} "API key not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.apiKeyRepo.RevokeAPIKey(c.Request.Context(), userID, keyID, time.Now()); err != nil {
+		if err.Error() == "api key not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
@@ -3,37 +3,61 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
+	"github.com/ayan-sh03/anoq/internal/export"
+	"github.com/ayan-sh03/anoq/internal/formdef"
 	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/previewtoken"
 	"github.com/ayan-sh03/anoq/internal/repository"
+	"github.com/ayan-sh03/anoq/internal/starttoken"
+	"github.com/ayan-sh03/anoq/internal/webhook"
 )
 
 // FormHandler handles form-related HTTP requests
 type FormHandler struct {
-	formRepo     repository.FormRepo
-	responseRepo repository.ResponseRepo
+	formRepo      repository.FormRepo
+	responseRepo  repository.ResponseRepo
+	questionRepo  repository.QuestionRepo
+	versionRepo   repository.VersionRepo
+	workspaceRepo repository.WorkspaceRepo
+	startTokens   *starttoken.Signer
+	previews      *previewtoken.Signer
+	webhooks      webhook.Publisher
 }
 
 // NewFormHandler creates a new form handler
-func NewFormHandler(formRepo repository.FormRepo, responseRepo repository.ResponseRepo) *FormHandler {
+func NewFormHandler(formRepo repository.FormRepo, responseRepo repository.ResponseRepo, questionRepo repository.QuestionRepo, versionRepo repository.VersionRepo, workspaceRepo repository.WorkspaceRepo, startTokens *starttoken.Signer, previews *previewtoken.Signer, webhooks webhook.Publisher) *FormHandler {
 	return &FormHandler{
-		formRepo:     formRepo,
-		responseRepo: responseRepo,
+		formRepo:      formRepo,
+		responseRepo:  responseRepo,
+		questionRepo:  questionRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		startTokens:   startTokens,
+		previews:      previews,
+		webhooks:      webhooks,
 	}
 }
 
 // ListForms handles GET /api/form
 // @Summary List user's forms
-// @Description Get a list of forms created by the authenticated user
+// @Description Get a list of forms created by the authenticated user or belonging to their workspaces
 // @Tags Forms
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Success 200 {object} object{forms=[]model.Form} "List of forms"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{forms=[]model.Form,page=model.PageInfo} "One page of forms, newest first"
+// @Failure 400 {object} object{error=string} "Invalid pagination parameters"
 // @Failure 401 {object} object{error=string} "Authentication required"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form [get]
@@ -51,14 +75,24 @@ func (h *FormHandler) ListForms(c *gin.Context) {
 		return
 	}
 
-	forms, err := h.formRepo.ListFormsByUserID(c.Request.Context(), userID)
+	page, err := parsePageRequest(c, "created_at", model.SortDesc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	forms, next, err := h.formRepo.ListFormsByUserID(c.Request.Context(), userID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list forms"})
 		return
 	}
+	if forms == nil {
+		forms = []*model.Form{}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"forms": forms,
+		"page":  model.NewPageInfo(page.Limit, next),
 	})
 }
 
@@ -82,6 +116,18 @@ func (h *FormHandler) GetForm(c *gin.Context) {
 		return
 	}
 
+	// Include the questions with their display logic so clients can render the flow
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	form.Questions = make([]model.Question, len(questions))
+	for i, question := range questions {
+		form.Questions[i] = *question
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"form": form,
 	})
@@ -89,15 +135,16 @@ func (h *FormHandler) GetForm(c *gin.Context) {
 
 // CreateForm handles POST /api/form
 // @Summary Create a new form
-// @Description Create a new form with title, description, and slug
+// @Description Create a new form with title, description, and slug, and optionally its questions. The form and its questions are created in one transaction, so nothing is created if any question is invalid. The form is a draft that respondents cannot see until it is published.
 // @Tags Forms
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param form body model.CreateFormRequest true "Form creation data"
 // @Success 201 {object} object{message=string,form=model.Form} "Form created successfully"
-// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 400 {object} object{error=string} "Invalid request body or question"
 // @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have editor access to this workspace"
 // @Failure 409 {object} object{error=string} "Form with this slug already exists"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form [post]
@@ -116,42 +163,47 @@ func (h *FormHandler) CreateForm(c *gin.Context) {
 	}
 
 	// Parse request body
-	var createReq struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description"`
-		Slug        string `json:"slug" binding:"required"`
-	}
-
+	var createReq model.CreateFormRequest
 	if err := c.ShouldBindJSON(&createReq); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
 	// Create form model
-	form := &model.Form{
-		ID:          uuid.New(),
-		Title:       createReq.Title,
-		Description: createReq.Description,
-		Slug:        createReq.Slug,
-		AuthorID:    userID,
-		Status:      model.FormStatusOpen,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	//debug
-	fmt.Println("Form: ", form)
-
-	// Save form
-	if err := h.formRepo.CreateForm(c.Request.Context(), form); err != nil {
-		if err.Error() == "form with slug "+createReq.Slug+" already exists" {
-			fmt.Println("Form with slug already exists")
+	form := &model.Form{}
+	form.FromCreateRequest(&createReq, userID)
+	for _, validate := range []func() error{form.ValidateSchedule, form.ValidateMaxResponses, form.ValidateDuplicatePolicy, form.ValidateAnonymityLevel, form.ValidateEditWindow} {
+		if err := validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	questions, err := model.NewQuestions(form.ID, createReq.Questions, form.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if form.WorkspaceID != nil {
+		if err := requireWorkspaceRole(c, h.workspaceRepo, *form.WorkspaceID, userID, model.WorkspaceRoleEditor); err != nil {
+			return // Error response already sent
+		}
+	}
+
+	// Save the form and its questions together so a failure leaves nothing behind
+	if err := h.formRepo.CreateFormWithQuestions(c.Request.Context(), form, questions); err != nil {
+		if err.Error() == "form with slug "+form.Slug+" already exists" {
 			c.JSON(http.StatusConflict, gin.H{"error": "Form with this slug already exists"})
 			return
 		}
-		fmt.Println("Error: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create form"})
 		return
+	}
 
+	form.Questions = make([]model.Question, len(questions))
+	for i, question := range questions {
+		form.Questions[i] = *question
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -160,9 +212,157 @@ func (h *FormHandler) CreateForm(c *gin.Context) {
 	})
 }
 
+// DuplicateForm handles POST /api/form/:id/duplicate
+// @Summary Duplicate a form
+// @Description Copy a form's settings and questions, including choices and display logic, into a new form. Responses, webhooks and the schedule are not copied, and the copy is a draft owned by the caller.
+// @Tags Forms
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param form body model.CopyFormRequest false "Title and slug of the copy; a slug is generated when omitted"
+// @Success 201 {object} object{message=string,form=model.Form} "Form duplicated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 409 {object} object{error=string} "Form with this slug already exists"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/duplicate [post]
+func (h *FormHandler) DuplicateForm(c *gin.Context) {
+	// The body is optional
+	var copyReq model.CopyFormRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&copyReq); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	if strings.TrimSpace(copyReq.Slug) == "" {
+		copyReq.Slug = model.CopySlug(form.Slug)
+	}
+	if strings.TrimSpace(copyReq.Title) == "" {
+		copyReq.Title = form.Title + " (copy)"
+	}
+
+	duplicate, err := createFormFromDefinition(c, h.formRepo, model.NewFormDefinition(form, questions), userID, &copyReq)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Form duplicated successfully",
+		"form":    duplicate,
+	})
+}
+
+// ExportFormDefinition handles GET /api/form/:id/definition
+// @Summary Export a form definition
+// @Description Serialize a form's settings and ordered questions, including choices, required flags and display logic, into a versioned JSON or YAML document that POST /api/form/import accepts
+// @Tags Forms
+// @Produce json
+// @Produce application/yaml
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param format query string false "Document format: json (default) or yaml"
+// @Success 200 {object} formdef.Document "Form definition"
+// @Failure 400 {object} object{error=string} "Invalid form ID or format"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/definition [get]
+func (h *FormHandler) ExportFormDefinition(c *gin.Context) {
+	format := formdef.Format(c.DefaultQuery("format", string(formdef.FormatJSON)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format: must be json or yaml"})
+		return
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, form.Slug, format))
+	c.Header("Content-Type", format.ContentType())
+	c.Status(http.StatusOK)
+	if err := formdef.Encode(c.Writer, formdef.Export(form, questions), format); err != nil {
+		log.Error().Err(err).Str("form_id", form.ID.String()).Msg("Failed to write form definition")
+	}
+}
+
+// ImportForm handles POST /api/form/import
+// @Summary Import a form definition
+// @Description Create a form and its questions from a document exported by GET /api/form/{id}/definition. Send YAML with a YAML content type and JSON otherwise. Nothing is created if any part of the definition is invalid. The form is created as a draft.
+// @Tags Forms
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Security Bearer
+// @Param slug query string false "Slug of the new form; defaults to the slug in the document"
+// @Param definition body formdef.Document true "Form definition"
+// @Success 201 {object} object{message=string,form=model.Form} "Form imported successfully"
+// @Failure 400 {object} object{error=string} "Invalid definition"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 409 {object} object{error=string} "Form with this slug already exists"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/import [post]
+func (h *FormHandler) ImportForm(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	doc, err := formdef.Decode(c.Request.Body, formdef.FormatFromContentType(c.ContentType()))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug, err := doc.ResolveSlug(c.Query("slug"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	form, err := createFormFromDefinition(c, h.formRepo, &doc.FormDefinition, userID, &model.CopyFormRequest{Slug: slug})
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Form imported successfully",
+		"form":    form,
+	})
+}
+
 // UpdateForm handles PUT /api/form/:id
 // @Summary Update a form
-// @Description Update an existing form's details
+// @Description Update an existing form's details. When questions are sent they are the complete new list: questions with an id are updated, questions without one are created, questions left out are deleted and all are positioned in the order listed. The form and its questions change in one transaction.
 // @Tags Forms
 // @Accept json
 // @Produce json
@@ -170,67 +370,97 @@ func (h *FormHandler) CreateForm(c *gin.Context) {
 // @Param id path string true "Form ID"
 // @Param form body model.UpdateFormRequest true "Form update data"
 // @Success 200 {object} object{message=string,form=model.Form} "Form updated successfully"
-// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 400 {object} object{error=string} "Invalid request body, form ID or question"
 // @Failure 401 {object} object{error=string} "Authentication required"
-// @Failure 403 {object} object{error=string} "Access denied: you don't own this form"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
 // @Failure 404 {object} object{error=string} "Form not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form/{id} [put]
 func (h *FormHandler) UpdateForm(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+	// Parse request body
+	var updateReq model.UpdateFormRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	// Parse request body
-	var updateReq struct {
-		Title       string           `json:"title"`
-		Description string           `json:"description"`
-		Status      model.FormStatus `json:"status"`
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
 	}
 
-	if err := c.ShouldBindJSON(&updateReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	// A status or schedule change is published like the open and close routes do
+	previousStatus := form.Status
+	if err := form.UpdateFromRequest(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get existing form
-	form, err := h.formRepo.GetFormByID(c.Request.Context(), formID)
-	if err != nil {
-		if err.Error() == "form not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+	// Without a question list only the form's settings change
+	if updateReq.Questions == nil {
+		if err := h.formRepo.UpdateForm(c.Request.Context(), form); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update form"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+		h.publishStatusChange(c, form, previousStatus)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Form updated successfully",
+			"form":    form,
+		})
 		return
 	}
 
-	// Update form fields
-	if updateReq.Title != "" {
-		form.Title = updateReq.Title
+	stored, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
 	}
-	if updateReq.Description != "" {
-		form.Description = updateReq.Description
+
+	changes, err := model.PlanQuestionChanges(form.ID, stored, updateReq.Questions, form.UpdatedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if updateReq.Status != "" {
-		form.Status = updateReq.Status
+
+	// A published form must stay publishable
+	if !form.IsDraft() {
+		if err := form.ValidatePublish(changes.Questions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
-	form.UpdatedAt = time.Now()
 
-	// Save updated form
-	if err := h.formRepo.UpdateForm(c.Request.Context(), form); err != nil {
+	if err := h.formRepo.UpdateFormWithQuestions(c.Request.Context(), form, changes); err != nil {
+		if err.Error() == "form not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update form"})
 		return
 	}
 
+	h.publishStatusChange(c, form, previousStatus)
+
+	form.Questions = make([]model.Question, len(changes.Questions))
+	for i, question := range changes.Questions {
+		form.Questions[i] = *question
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Form updated successfully",
 		"form":    form,
 	})
 }
 
+// publishStatusChange publishes form.opened or form.closed when an update
+// changed the status of the form
+func (h *FormHandler) publishStatusChange(c *gin.Context, form *model.Form, previous model.FormStatus) {
+	if event, changed := model.StatusChangeEvent(previous, form.Status); changed {
+		h.webhooks.Publish(c.Request.Context(), form.ID, event, form.ToResponse())
+	}
+}
+
 // DeleteForm handles DELETE /api/form/:id
 // @Summary Delete a form
 // @Description Delete an existing form
@@ -242,18 +472,21 @@ func (h *FormHandler) UpdateForm(c *gin.Context) {
 // @Success 200 {object} object{message=string} "Form deleted successfully"
 // @Failure 400 {object} object{error=string} "Invalid form ID"
 // @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
 // @Failure 404 {object} object{error=string} "Form not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form/{id} [delete]
 func (h *FormHandler) DeleteForm(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
-		return
+		return // Error response already sent
 	}
 
-	if err := h.formRepo.DeleteForm(c.Request.Context(), formID); err != nil {
+	// Deleting the form removes its webhooks, so they are looked up first
+	err = h.webhooks.PublishOnSuccess(c.Request.Context(), form.ID, model.WebhookEventFormDeleted, form.ToResponse(), func() error {
+		return h.formRepo.DeleteForm(c.Request.Context(), form.ID)
+	})
+	if err != nil {
 		if err.Error() == "form not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
 			return
@@ -267,17 +500,71 @@ func (h *FormHandler) DeleteForm(c *gin.Context) {
 	})
 }
 
-// OpenForm handles POST /api/form/open/:slug
-func (h *FormHandler) OpenForm(c *gin.Context) {
-	slug := c.Param("slug")
+// MoveForm handles PUT /api/form/:id/workspace
+// @Summary Move a form to a workspace
+// @Description Move a form into a workspace, where its members get access by their role, or out of its workspace back to its author alone. The caller needs the editor role in the target workspace.
+// @Tags Forms
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param workspace body model.MoveFormRequest true "Target workspace, or null"
+// @Success 200 {object} object{message=string,form=model.Form} "Form moved successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form or workspace"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/workspace [put]
+func (h *FormHandler) MoveForm(c *gin.Context) {
+	var moveReq model.MoveFormRequest
+	if err := c.ShouldBindJSON(&moveReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
 
-	form, err := h.formRepo.GetFormBySlug(c.Request.Context(), slug)
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	form, err := authorizedForm(c)
 	if err != nil {
+		return // Error response already sent
+	}
+
+	if moveReq.WorkspaceID != nil {
+		if err := requireWorkspaceRole(c, h.workspaceRepo, *moveReq.WorkspaceID, userID, model.WorkspaceRoleEditor); err != nil {
+			return // Error response already sent
+		}
+	}
+
+	form.WorkspaceID = moveReq.WorkspaceID
+	form.UpdatedAt = time.Now()
+	if err := h.formRepo.UpdateForm(c.Request.Context(), form); err != nil {
 		if err.Error() == "form not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move form"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Form moved successfully",
+		"form":    form,
+	})
+}
+
+// OpenForm handles POST /api/form/open/:slug
+func (h *FormHandler) OpenForm(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := form.SetStatus(model.FormStatusOpen); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -286,6 +573,8 @@ func (h *FormHandler) OpenForm(c *gin.Context) {
 		return
 	}
 
+	h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventFormOpened, form.ToResponse())
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Form opened successfully",
 	})
@@ -293,15 +582,13 @@ func (h *FormHandler) OpenForm(c *gin.Context) {
 
 // CloseForm handles POST /api/form/close/:slug
 func (h *FormHandler) CloseForm(c *gin.Context) {
-	slug := c.Param("slug")
-
-	form, err := h.formRepo.GetFormBySlug(c.Request.Context(), slug)
+	form, err := authorizedForm(c)
 	if err != nil {
-		if err.Error() == "form not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+		return // Error response already sent
+	}
+
+	if err := form.SetStatus(model.FormStatusClosed); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -310,85 +597,511 @@ func (h *FormHandler) CloseForm(c *gin.Context) {
 		return
 	}
 
+	h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventFormClosed, form.ToResponse())
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Form closed successfully",
 	})
 }
 
-// GetFormSubmissions handles GET /api/form/submissions/:slug
-func (h *FormHandler) GetFormSubmissions(c *gin.Context) {
-	slug := c.Param("slug")
+// PublishForm handles POST /api/form/:id/publish
+// @Summary Publish a draft form
+// @Description Make a draft form available to respondents. The form needs at least one question, and multiple choice and dropdown questions need choices. The form opens, or follows its schedule when it has one, and the published questions are recorded as a version.
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Success 200 {object} object{message=string,form=model.Form} "Form published successfully"
+// @Failure 400 {object} object{error=string} "Invalid form ID or the form is not ready to publish"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 409 {object} object{error=string} "Form is already published"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/publish [post]
+func (h *FormHandler) PublishForm(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
 
-	form, err := h.formRepo.GetFormBySlug(c.Request.Context(), slug)
+	if !form.IsDraft() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Form is already published"})
+		return
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
 	if err != nil {
-		if err.Error() == "form not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
 		return
 	}
 
-	// Check if user owns this form (for security)
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	if err := form.Publish(questions, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr.(string))
+	if err := h.formRepo.UpdateForm(c.Request.Context(), form); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish form"})
+		return
+	}
+
+	// Responses record the version they answered anyway, so a failure here
+	// only delays the first version until the first submission
+	if _, err := h.versionRepo.EnsureVersion(c.Request.Context(), form.ID, model.NewFormDefinition(form, questions)); err != nil {
+		log.Warn().Err(err).Str("form_id", form.ID.String()).Msg("Failed to record published form version")
+	}
+
+	if form.IsOpen() {
+		h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventFormOpened, form.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Form published successfully",
+		"form":    form,
+	})
+}
+
+// CreatePreviewToken handles POST /api/form/:id/preview-token
+// @Summary Create a preview token
+// @Description Create a token that shows a draft form on GET /api/form/slug/{slug}?preview_token= until it expires, so the owner can preview it or share the preview before publishing
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Success 201 {object} object{preview_token=string,expires_at=string} "Preview token"
+// @Failure 400 {object} object{error=string} "Invalid form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/preview-token [post]
+func (h *FormHandler) CreatePreviewToken(c *gin.Context) {
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return // Error response already sent
+	}
+
+	token, expiresAt := h.previews.Issue(form.ID, time.Now())
+	c.JSON(http.StatusCreated, gin.H{
+		"preview_token": token,
+		"expires_at":    expiresAt,
+	})
+}
+
+// GetFormSubmissions handles GET /api/form/submissions/:slug
+// @Summary List form submissions
+// @Description Get one page of the submissions of a form, filtered by triage state, submission time and answers
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param slug path string true "Form slug"
+// @Param detailed query bool false "Include the answers of every submission"
+// @Param spam query bool false "Only submissions with this spam state"
+// @Param reviewed query bool false "Only submissions with this reviewed state"
+// @Param starred query bool false "Only starred or unstarred submissions"
+// @Param tag query string false "Only submissions with this tag"
+// @Param from query string false "Only submissions made at or after this RFC 3339 time"
+// @Param to query string false "Only submissions made before this RFC 3339 time"
+// @Param answer[question_id] query string false "Only submissions whose answer to the question equals the value or selected it as a choice"
+// @Param contains[question_id] query string false "Only submissions whose answer to the question contains the text, ignoring case"
+// @Param sort query string false "Sort column: created_at (default) or updated_at"
+// @Param order query string false "Sort order: desc (default) or asc"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{form_id=string,submissions=[]model.ResponseListResponse,count=int,page=model.PageInfo} "One page of submissions"
+// @Failure 400 {object} object{error=string} "Invalid filter or pagination parameters"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/submissions/{slug} [get]
+func (h *FormHandler) GetFormSubmissions(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	filter, err := parseResponseFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if form.AuthorID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't own this form"})
+	page, err := parseResponsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Get detailed query parameter to determine if we need full details or just list
 	detailed := c.Query("detailed") == "true"
 
+	var (
+		responses []*model.FilledForm
+		next      *model.Cursor
+	)
 	if detailed {
 		// Get responses with full details including answers
-		responses, err := h.responseRepo.GetResponsesByFormID(c.Request.Context(), form.ID)
-		if err != nil {
+		responses, next, err = h.responseRepo.GetResponsesByFormID(c.Request.Context(), form.ID, filter, page)
+	} else {
+		// Get responses list only (without answers for performance)
+		responses, next, err = h.responseRepo.GetResponsesListByFormID(c.Request.Context(), form.ID, filter, page)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form submissions"})
+		return
+	}
+	if detailed {
+		if err := applyVersions(c.Request.Context(), h.versionRepo, responses...); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form submissions"})
 			return
 		}
+	}
 
-		// Convert to response format
-		submissionDetails := make([]interface{}, len(responses))
-		for i, response := range responses {
-			submissionDetails[i] = response.ToDetailResponse()
+	// Convert to response format
+	submissions := make([]interface{}, len(responses))
+	for i, response := range responses {
+		if detailed {
+			submissions[i] = response.ToDetailResponse()
+		} else {
+			submissions[i] = response.ToResponseList()
 		}
+	}
 
-		c.JSON(http.StatusOK, gin.H{
-			"form_id":     form.ID,
-			"submissions": submissionDetails,
-			"count":       len(submissionDetails),
-		})
-	} else {
-		// Get responses list only (without answers for performance)
-		responses, err := h.responseRepo.GetResponsesListByFormID(c.Request.Context(), form.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"form_id":     form.ID,
+		"submissions": submissions,
+		"count":       len(submissions),
+		"page":        model.NewPageInfo(page.Limit, next),
+	})
+}
+
+// parseResponseFilter reads the submission filter from the query parameters
+func parseResponseFilter(c *gin.Context) (model.ResponseFilter, error) {
+	filter := model.ResponseFilter{Tag: strings.TrimSpace(c.Query("tag"))}
+
+	states := []struct {
+		name  string
+		field **bool
+	}{
+		{"spam", &filter.Spam},
+		{"reviewed", &filter.Reviewed},
+		{"starred", &filter.Starred},
+	}
+	for _, state := range states {
+		value := c.Query(state.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form submissions"})
-			return
+			return model.ResponseFilter{}, fmt.Errorf("%s must be true or false", state.name)
+		}
+		*state.field = &parsed
+	}
+
+	bounds := []struct {
+		name  string
+		field **time.Time
+	}{
+		{"from", &filter.SubmittedFrom},
+		{"to", &filter.SubmittedTo},
+	}
+	for _, bound := range bounds {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return model.ResponseFilter{}, fmt.Errorf("%s must be an RFC 3339 time", bound.name)
+		}
+		*bound.field = &parsed
+	}
+	if filter.SubmittedFrom != nil && filter.SubmittedTo != nil && !filter.SubmittedFrom.Before(*filter.SubmittedTo) {
+		return model.ResponseFilter{}, fmt.Errorf("from must be before to")
+	}
+
+	// answer[<question_id>]=value and contains[<question_id>]=text
+	answerParams := []struct {
+		name  string
+		field func(*model.AnswerFilter) *string
+	}{
+		{"answer", func(f *model.AnswerFilter) *string { return &f.Equals }},
+		{"contains", func(f *model.AnswerFilter) *string { return &f.Contains }},
+	}
+	for _, param := range answerParams {
+		values := c.QueryMap(param.name)
+		ids := make([]string, 0, len(values))
+		for id := range values {
+			ids = append(ids, id)
 		}
+		// Sorted so the generated query is stable
+		sort.Strings(ids)
 
-		// Convert to list response format
-		submissionList := make([]interface{}, len(responses))
-		for i, response := range responses {
-			submissionList[i] = response.ToResponseList()
+		for _, id := range ids {
+			questionID, err := uuid.Parse(id)
+			if err != nil {
+				return model.ResponseFilter{}, fmt.Errorf("%s[%s]: invalid question ID", param.name, id)
+			}
+			if values[id] == "" {
+				continue
+			}
+			answer := model.AnswerFilter{QuestionID: questionID}
+			*param.field(&answer) = values[id]
+			filter.Answers = append(filter.Answers, answer)
 		}
+	}
+	if len(filter.Answers) > model.MaxAnswerFilters {
+		return model.ResponseFilter{}, fmt.Errorf("at most %d answer filters are allowed", model.MaxAnswerFilters)
+	}
 
-		c.JSON(http.StatusOK, gin.H{
-			"form_id":     form.ID,
-			"submissions": submissionList,
-			"count":       len(submissionList),
-		})
+	return filter, nil
+}
+
+// parseResponsePage reads the sort, order, limit and cursor query parameters
+// of a response listing
+func parseResponsePage(c *gin.Context) (model.PageRequest, error) {
+	sortBy := model.ResponseSort(c.DefaultQuery("sort", string(model.ResponseSortCreatedAt)))
+	if !sortBy.IsValid() {
+		return model.PageRequest{}, fmt.Errorf("sort must be created_at or updated_at")
+	}
+
+	order := model.SortOrder(c.DefaultQuery("order", string(model.SortDesc)))
+	if !order.IsValid() {
+		return model.PageRequest{}, fmt.Errorf("order must be asc or desc")
+	}
+
+	return parsePageRequest(c, string(sortBy), order)
+}
+
+// parsePageRequest reads the limit and cursor query parameters of a listing
+// sorted by sortBy in order
+func parsePageRequest(c *gin.Context, sortBy string, order model.SortOrder) (model.PageRequest, error) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return model.PageRequest{}, fmt.Errorf("limit must be a number")
+		}
+		if parsed == 0 {
+			return model.PageRequest{}, fmt.Errorf("limit must be between 1 and %d", model.MaxPageSize)
+		}
+		limit = parsed
+	}
+
+	return model.NewPageRequest(limit, sortBy, order, c.Query("cursor"))
+}
+
+// SearchResponses handles GET /api/form/:id/responses/search
+// @Summary Search form responses
+// @Description Full-text search over the text answers of a form's responses. Matching responses are returned with their answers and highlighted snippets of the matching answers. The filter, sort and pagination parameters of the submissions listing also apply.
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param q query string true "Search query; supports quoted phrases, OR and -excluded words"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{form_id=string,query=string,results=[]model.ResponseDetailResponse,count=int,page=model.PageInfo} "One page of matching responses"
+// @Failure 400 {object} object{error=string} "Invalid form ID, query or parameters"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/responses/search [get]
+func (h *FormHandler) SearchResponses(c *gin.Context) {
+	search := strings.TrimSpace(c.Query("q"))
+	if search == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if len(search) > model.MaxSearchLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be at most %d characters", model.MaxSearchLength)})
+		return
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
 	}
+
+	filter, err := parseResponseFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := parseResponsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses, next, err := h.responseRepo.SearchResponses(c.Request.Context(), form.ID, search, filter, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search responses"})
+		return
+	}
+	if err := applyVersions(c.Request.Context(), h.versionRepo, responses...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search responses"})
+		return
+	}
+
+	results := make([]*model.ResponseDetailResponse, len(responses))
+	for i, response := range responses {
+		results[i] = response.ToDetailResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"form_id": form.ID,
+		"query":   search,
+		"results": results,
+		"count":   len(results),
+		"page":    model.NewPageInfo(page.Limit, next),
+	})
+}
+
+// ExportResponses handles GET /api/form/:id/export
+// @Summary Export form responses
+// @Description Stream every response of a form as CSV, JSONL or XLSX, one row per response and one column per question ordered by position
+// @Tags Forms
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param format query string false "Export format: csv (default), jsonl or xlsx"
+// @Param multi query string false "Multi-select flattening: join (default) or columns"
+// @Param separator query string false "Separator for joined multi-select choices (default \"; \")"
+// @Success 200 {file} file "Exported responses"
+// @Failure 400 {object} object{error=string} "Invalid form ID or export options"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/export [get]
+func (h *FormHandler) ExportResponses(c *gin.Context) {
+	opts := export.Options{
+		Format:      export.Format(c.DefaultQuery("format", string(export.FormatCSV))),
+		MultiSelect: export.MultiSelectMode(c.DefaultQuery("multi", string(export.MultiSelectJoin))),
+		Separator:   c.Query("separator"),
+	}
+	if !opts.Format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export format: must be csv, jsonl or xlsx"})
+		return
+	}
+	if !opts.MultiSelect.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multi-select mode: must be join or columns"})
+		return
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	c.Header("Content-Type", opts.Format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-responses.%s"`, form.Slug, opts.Format))
+	c.Status(http.StatusOK)
+
+	exporter, err := export.New(c.Writer, questions, opts)
+	if err != nil {
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export"})
+		return
+	}
+
+	err = h.responseRepo.StreamResponsesByFormID(c.Request.Context(), form.ID, exporter.WriteResponse)
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err != nil {
+		// Part of the file may already be sent, so the status can't change anymore
+		log.Error().Err(err).Str("form_id", form.ID.String()).Msg("Failed to export responses")
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export responses"})
+			return
+		}
+		c.Abort()
+	}
+}
+
+// GetFormAnalytics handles GET /api/form/:id/analytics
+// @Summary Get per-question analytics for a form
+// @Description Answer and skip rates per question, choice distributions with multi-select co-occurrence, and submissions over time
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param interval query string false "Timeline bucket size: day (default) or hour"
+// @Success 200 {object} object{analytics=model.FormAnalytics} "Form analytics"
+// @Failure 400 {object} object{error=string} "Invalid form ID or interval"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/analytics [get]
+func (h *FormHandler) GetFormAnalytics(c *gin.Context) {
+	interval := model.AnalyticsInterval(c.DefaultQuery("interval", string(model.AnalyticsIntervalDay)))
+	if !interval.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interval: must be day or hour"})
+		return
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	analytics, err := h.responseRepo.GetFormAnalytics(c.Request.Context(), form.ID, interval)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form analytics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"analytics": analytics,
+	})
+}
+
+// GetFormSubmissionStats handles GET /api/form/:id/stats
+// @Summary Get submission statistics for a form
+// @Description Submission counts and average, median and p90 completion times, with the number of suspiciously fast submissions
+// @Tags Forms
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Success 200 {object} object{stats=model.FormSubmissionStats} "Submission statistics"
+// @Failure 400 {object} object{error=string} "Invalid form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/stats [get]
+func (h *FormHandler) GetFormSubmissionStats(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	stats, err := h.responseRepo.GetFormSubmissionStats(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get submission stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
 }
 
 // GetDashboard handles GET /api/dashboard
@@ -406,19 +1119,13 @@ func (h *FormHandler) GetDashboard(c *gin.Context) {
 		return
 	}
 
-	// Get recent forms
-	forms, err := h.formRepo.ListFormsByUserID(c.Request.Context(), userID)
+	// Get the 5 most recent forms
+	recentForms, _, err := h.formRepo.ListFormsByUserID(c.Request.Context(), userID, model.PageRequest{Limit: 5, Sort: "created_at", Order: model.SortDesc})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dashboard data"})
 		return
 	}
 
-	// Limit to recent 5 forms
-	recentForms := forms
-	if len(forms) > 5 {
-		recentForms = forms[:5]
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"recent_forms": recentForms,
 	})
@@ -450,12 +1157,13 @@ func (h *FormHandler) GetStats(c *gin.Context) {
 
 // GetFormBySlug handles GET /api/form/slug/{slug}
 // @Summary Get form by slug
-// @Description Get a form by its slug identifier (public endpoint)
+// @Description Get a form and its questions, including their display logic, by its slug identifier (public endpoint). The form reports its scheduled opens_at and closes_at times and, when it has a response limit, its remaining_responses. Draft forms are only returned with a preview token from POST /api/form/{id}/preview-token.
 // @Tags Forms
 // @Accept json
 // @Produce json
 // @Param slug path string true "Form slug"
-// @Success 200 {object} object{form=model.Form} "Form details"
+// @Param preview_token query string false "Token to preview a draft form"
+// @Success 200 {object} object{form=model.Form,start_token=string,anonymity=model.AnonymityDeclaration} "Form details, a token to send back with the response and what is stored about respondents"
 // @Failure 404 {object} object{error=string} "Form not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form/slug/{slug} [get]
@@ -472,7 +1180,45 @@ func (h *FormHandler) GetFormBySlug(c *gin.Context) {
 		return
 	}
 
+	// Drafts do not exist for respondents, so an invalid preview token gets
+	// the same response as an unknown slug
+	if form.IsDraft() {
+		token := c.Query("preview_token")
+		if token == "" || h.previews.Verify(token, form.ID, time.Now()) != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+			return
+		}
+	}
+
+	// Report the status of scheduled times the scheduler has not applied yet
+	form.Status = form.StatusAt(time.Now())
+
+	if form.MaxResponses != nil {
+		count, err := h.responseRepo.CountResponsesByFormID(c.Request.Context(), form.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+			return
+		}
+		form.SetRemainingResponses(count)
+	}
+
+	// Include the questions with their display logic so clients can render the flow
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	form.Questions = make([]model.Question, len(questions))
+	for i, question := range questions {
+		form.Questions[i] = *question
+	}
+
+	// The start token is sent back on submit to measure the completion time.
+	// The anonymity declaration tells respondents what is kept about them.
 	c.JSON(http.StatusOK, gin.H{
-		"form": form,
+		"form":        form,
+		"start_token": h.startTokens.Issue(form.ID, time.Now()),
+		"anonymity":   form.AnonymityDeclaration(),
 	})
 }
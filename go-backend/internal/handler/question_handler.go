@@ -14,14 +14,12 @@ import (
 // QuestionHandler handles question-related HTTP requests
 type QuestionHandler struct {
 	questionRepo *repository.QuestionRepository
-	formRepo     *repository.FormRepository
 }
 
 // NewQuestionHandler creates a new question handler
-func NewQuestionHandler(questionRepo *repository.QuestionRepository, formRepo *repository.FormRepository) *QuestionHandler {
+func NewQuestionHandler(questionRepo *repository.QuestionRepository) *QuestionHandler {
 	return &QuestionHandler{
 		questionRepo: questionRepo,
-		formRepo:     formRepo,
 	}
 }
 
@@ -37,20 +35,13 @@ func NewQuestionHandler(questionRepo *repository.QuestionRepository, formRepo *r
 // @Success 201 {object} object{message=string,question=model.QuestionResponse} "Question created successfully"
 // @Failure 400 {object} object{error=string} "Invalid request body or form ID"
 // @Failure 401 {object} object{error=string} "Authentication required"
-// @Failure 403 {object} object{error=string} "Access denied: you don't own this form"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
 // @Failure 404 {object} object{error=string} "Form not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/form/{id}/questions [post]
 func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
-		return
-	}
-
-	// Verify form exists and user owns it
-	if err := h.verifyFormOwnership(c, formID); err != nil {
 		return // Error response already sent
 	}
 
@@ -61,24 +52,21 @@ func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
 		return
 	}
 
-	// Validate question type
-	if createReq.Type != model.QuestionTypeBasic && createReq.Type != model.QuestionTypeMultipleChoice {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question type"})
+	// Create question model
+	question := &model.Question{}
+	question.FromCreateRequest(&createReq, form.ID)
+
+	// Validate question type and type-specific settings
+	if err := question.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Validate multiple choice questions
-	if createReq.Type == model.QuestionTypeMultipleChoice {
-		if len(createReq.Choices) < 2 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Multiple choice questions must have at least 2 choices"})
-			return
-		}
+	// Validate display logic against the rest of the form
+	if err := h.validateDisplayLogic(c, form.ID, question); err != nil {
+		return // Error response already sent
 	}
 
-	// Create question model
-	question := &model.Question{}
-	question.FromCreateRequest(&createReq, formID)
-
 	// Save question
 	if err := h.questionRepo.CreateQuestion(c.Request.Context(), question); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create question"})
@@ -102,7 +90,7 @@ func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
 // @Success 200 {object} object{question=model.QuestionResponse} "Question details"
 // @Failure 400 {object} object{error=string} "Invalid question ID"
 // @Failure 401 {object} object{error=string} "Authentication required"
-// @Failure 403 {object} object{error=string} "Access denied: you don't own this form"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
 // @Failure 404 {object} object{error=string} "Question not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/questions/{id} [get]
@@ -124,11 +112,6 @@ func (h *QuestionHandler) GetQuestion(c *gin.Context) {
 		return
 	}
 
-	// Verify user owns the form (optional, for security)
-	if err := h.verifyFormOwnership(c, question.FormID); err != nil {
-		return // Error response already sent
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"question": question.ToResponse(),
 	})
@@ -136,19 +119,12 @@ func (h *QuestionHandler) GetQuestion(c *gin.Context) {
 
 // GetFormQuestions handles GET /api/form/:id/questions
 func (h *QuestionHandler) GetFormQuestions(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
-		return
-	}
-
-	// Verify form exists and user owns it
-	if err := h.verifyFormOwnership(c, formID); err != nil {
 		return // Error response already sent
 	}
 
-	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), formID)
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
 		return
@@ -186,11 +162,6 @@ func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
 		return
 	}
 
-	// Verify user owns the form
-	if err := h.verifyFormOwnership(c, question.FormID); err != nil {
-		return // Error response already sent
-	}
-
 	// Parse request body
 	var updateReq model.UpdateQuestionRequest
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
@@ -198,24 +169,19 @@ func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
 		return
 	}
 
-	// Validate question type if provided
-	if updateReq.Type != nil {
-		if *updateReq.Type != model.QuestionTypeBasic && *updateReq.Type != model.QuestionTypeMultipleChoice {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question type"})
-			return
-		}
-	}
+	// Update question
+	question.UpdateFromRequest(&updateReq)
 
-	// Validate multiple choice questions
-	if updateReq.Type != nil && *updateReq.Type == model.QuestionTypeMultipleChoice {
-		if len(updateReq.Choices) < 2 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Multiple choice questions must have at least 2 choices"})
-			return
-		}
+	// Validate the updated question
+	if err := question.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Update question
-	question.UpdateFromRequest(&updateReq)
+	// Validate display logic against the rest of the form
+	if err := h.validateDisplayLogic(c, question.FormID, question); err != nil {
+		return // Error response already sent
+	}
 
 	// Save updated question
 	if err := h.questionRepo.UpdateQuestion(c.Request.Context(), question); err != nil {
@@ -249,9 +215,17 @@ func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
 		return
 	}
 
-	// Verify user owns the form
-	if err := h.verifyFormOwnership(c, question.FormID); err != nil {
-		return // Error response already sent
+	// Refuse to delete a question that other questions' display logic depends on
+	formQuestions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), question.FormID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+	if dependents := model.DependentQuestions(formQuestions, questionID); len(dependents) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Question is used by the display logic of question: " + dependents[0].QuestionText,
+		})
+		return
 	}
 
 	// Delete question
@@ -267,15 +241,8 @@ func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
 
 // CreateMultipleQuestions handles POST /api/form/:id/questions/batch
 func (h *QuestionHandler) CreateMultipleQuestions(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
-		return
-	}
-
-	// Verify form exists and user owns it
-	if err := h.verifyFormOwnership(c, formID); err != nil {
 		return // Error response already sent
 	}
 
@@ -296,30 +263,25 @@ func (h *QuestionHandler) CreateMultipleQuestions(c *gin.Context) {
 	// Validate and create question models
 	questions := make([]*model.Question, len(batchReq.Questions))
 	for i, createReq := range batchReq.Questions {
-		// Validate question type
-		if createReq.Type != model.QuestionTypeBasic && createReq.Type != model.QuestionTypeMultipleChoice {
+		// Create question model
+		question := &model.Question{}
+		question.FromCreateRequest(&createReq, form.ID)
+
+		// Validate question type and type-specific settings
+		if err := question.Validate(); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid question type at index " + strconv.Itoa(i),
+				"error": err.Error() + " (question at index " + strconv.Itoa(i) + ")",
 			})
 			return
 		}
-
-		// Validate multiple choice questions
-		if createReq.Type == model.QuestionTypeMultipleChoice {
-			if len(createReq.Choices) < 2 {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Multiple choice question at index " + strconv.Itoa(i) + " must have at least 2 choices",
-				})
-				return
-			}
-		}
-
-		// Create question model
-		question := &model.Question{}
-		question.FromCreateRequest(&createReq, formID)
 		questions[i] = question
 	}
 
+	// Validate display logic against the rest of the form
+	if err := h.validateDisplayLogic(c, form.ID, questions...); err != nil {
+		return // Error response already sent
+	}
+
 	// Save questions in batch
 	if err := h.questionRepo.CreateQuestionsInBatch(c.Request.Context(), questions); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create questions"})
@@ -341,15 +303,8 @@ func (h *QuestionHandler) CreateMultipleQuestions(c *gin.Context) {
 
 // ReorderQuestions handles PUT /api/form/:id/questions/reorder
 func (h *QuestionHandler) ReorderQuestions(c *gin.Context) {
-	formIDStr := c.Param("id")
-	formID, err := uuid.Parse(formIDStr)
+	form, err := authorizedForm(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
-		return
-	}
-
-	// Verify form exists and user owns it
-	if err := h.verifyFormOwnership(c, formID); err != nil {
 		return // Error response already sent
 	}
 
@@ -365,7 +320,8 @@ func (h *QuestionHandler) ReorderQuestions(c *gin.Context) {
 		return
 	}
 
-	// Update each question's position
+	// Load and reposition each question
+	reordered := make([]*model.Question, 0, len(reorderReq.QuestionOrders))
 	for _, order := range reorderReq.QuestionOrders {
 		question, err := h.questionRepo.GetQuestionByID(c.Request.Context(), order.ID)
 		if err != nil {
@@ -376,18 +332,27 @@ func (h *QuestionHandler) ReorderQuestions(c *gin.Context) {
 		}
 
 		// Verify question belongs to the form
-		if question.FormID != formID {
+		if question.FormID != form.ID {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Question does not belong to this form: " + order.ID.String(),
 			})
 			return
 		}
 
-		// Update position
 		question.Position = order.Position
+		reordered = append(reordered, question)
+	}
+
+	// Conditions must still refer to earlier questions after reordering
+	if err := h.validateDisplayLogic(c, form.ID, reordered...); err != nil {
+		return // Error response already sent
+	}
+
+	// Update each question's position
+	for _, question := range reordered {
 		if err := h.questionRepo.UpdateQuestion(c.Request.Context(), question); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to update question position: " + order.ID.String(),
+				"error": "Failed to update question position: " + question.ID.String(),
 			})
 			return
 		}
@@ -398,35 +363,31 @@ func (h *QuestionHandler) ReorderQuestions(c *gin.Context) {
 	})
 }
 
-// verifyFormOwnership checks if the authenticated user owns the form
-func (h *QuestionHandler) verifyFormOwnership(c *gin.Context, formID uuid.UUID) error {
-	// Get user ID from context
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return gin.Error{Err: nil}
-	}
-
-	userID, err := uuid.Parse(userIDStr.(string))
+// validateDisplayLogic checks the display logic of the form's questions once
+// the changed questions are applied on top of the stored ones
+func (h *QuestionHandler) validateDisplayLogic(c *gin.Context, formID uuid.UUID, changed ...*model.Question) error {
+	stored, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), formID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
 		return err
 	}
 
-	// Check if form exists and user owns it
-	form, err := h.formRepo.GetFormByID(c.Request.Context(), formID)
-	if err != nil {
-		if err.Error() == "form not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
-			return err
+	changedByID := make(map[uuid.UUID]*model.Question, len(changed))
+	for _, question := range changed {
+		changedByID[question.ID] = question
+	}
+
+	questions := make([]*model.Question, 0, len(stored)+len(changed))
+	for _, question := range stored {
+		if _, ok := changedByID[question.ID]; !ok {
+			questions = append(questions, question)
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
-		return err
 	}
+	questions = append(questions, changed...)
 
-	if form.AuthorID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't own this form"})
-		return gin.Error{Err: nil}
+	if err := model.ValidateDisplayLogic(questions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return err
 	}
 
 	return nil
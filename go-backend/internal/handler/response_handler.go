@@ -1,13 +1,21 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
+	"github.com/ayan-sh03/anoq/internal/edittoken"
 	"github.com/ayan-sh03/anoq/internal/model"
 	"github.com/ayan-sh03/anoq/internal/repository"
+	"github.com/ayan-sh03/anoq/internal/respondent"
+	"github.com/ayan-sh03/anoq/internal/starttoken"
+	"github.com/ayan-sh03/anoq/internal/webhook"
 )
 
 // ResponseHandler handles response-related HTTP requests
@@ -15,17 +23,40 @@ type ResponseHandler struct {
 	responseRepo *repository.ResponseRepository
 	formRepo     *repository.FormRepository
 	questionRepo *repository.QuestionRepository
+	versionRepo  *repository.VersionRepository
+
+	startTokens   *starttoken.Signer
+	fastThreshold time.Duration
+	webhooks      webhook.Publisher
+
+	identities    *respondent.Identifier
+	secureCookies bool
 }
 
-// NewResponseHandler creates a new response handler
-func NewResponseHandler(responseRepo *repository.ResponseRepository, formRepo *repository.FormRepository, questionRepo *repository.QuestionRepository) *ResponseHandler {
+// NewResponseHandler creates a new response handler; submissions completed in
+// less than fastThreshold are flagged as suspiciously fast. Browser tokens are
+// set as Secure cookies when secureCookies is true.
+func NewResponseHandler(responseRepo *repository.ResponseRepository, formRepo *repository.FormRepository, questionRepo *repository.QuestionRepository, versionRepo *repository.VersionRepository, startTokens *starttoken.Signer, fastThreshold time.Duration, webhooks webhook.Publisher, identities *respondent.Identifier, secureCookies bool) *ResponseHandler {
 	return &ResponseHandler{
-		responseRepo: responseRepo,
-		formRepo:     formRepo,
-		questionRepo: questionRepo,
+		responseRepo:  responseRepo,
+		formRepo:      formRepo,
+		questionRepo:  questionRepo,
+		versionRepo:   versionRepo,
+		startTokens:   startTokens,
+		fastThreshold: fastThreshold,
+		webhooks:      webhooks,
+		identities:    identities,
+		secureCookies: secureCookies,
 	}
 }
 
+// duplicateMessages are the errors returned for duplicate submissions
+var duplicateMessages = map[model.DuplicatePolicy]string{
+	model.DuplicatePolicyEmail:   "A response was already submitted with this email",
+	model.DuplicatePolicyIP:      "A response was already submitted from this network",
+	model.DuplicatePolicyBrowser: "A response was already submitted from this browser",
+}
+
 // SubmitResponse handles POST /api/response
 // @Summary Submit a form response
 // @Description Submit answers to a form (public endpoint)
@@ -33,9 +64,10 @@ func NewResponseHandler(responseRepo *repository.ResponseRepository, formRepo *r
 // @Accept json
 // @Produce json
 // @Param response body model.CreateResponseRequest true "Form response data"
-// @Success 201 {object} object{message=string,response_id=string} "Response submitted successfully"
+// @Success 201 {object} object{message=string,response_id=string,edit_token=string,edit_expires_at=string} "Response submitted successfully; the edit token is only returned for forms with an edit window"
 // @Failure 400 {object} object{error=string} "Invalid request body or form not accepting responses"
 // @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 409 {object} object{error=string} "Form has reached its response limit or a response was already submitted"
 // @Failure 429 {object} object{error=string} "Rate limit exceeded"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/response [post]
@@ -58,84 +90,33 @@ func (h *ResponseHandler) SubmitResponse(c *gin.Context) {
 		return
 	}
 
-	if form.Status != model.FormStatusOpen {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting responses"})
+	// Drafts are hidden from respondents
+	if form.IsDraft() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
 		return
 	}
 
-	// Validate that all questions exist and belong to this form
-	if len(submitReq.Answers) > 0 {
-		// Get all questions for this form to validate against
-		formQuestions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), submitReq.FormID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate questions"})
-			return
-		}
-
-		// Create maps for validation
-		validQuestionIDs := make(map[uuid.UUID]bool)
-		questionsMap := make(map[uuid.UUID]*model.Question)
-		for _, question := range formQuestions {
-			validQuestionIDs[question.ID] = true
-			questionsMap[question.ID] = question
-		}
+	// Scheduled times apply even before the scheduler updates the status
+	if form.StatusAt(time.Now()) != model.FormStatusOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting responses"})
+		return
+	}
 
-		// Validate each answer
-		for _, answerReq := range submitReq.Answers {
-			// Check if question exists and belongs to form
-			if !validQuestionIDs[answerReq.QuestionID] {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Invalid question ID: " + answerReq.QuestionID.String(),
-				})
-				return
-			}
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate questions"})
+		return
+	}
 
-			question := questionsMap[answerReq.QuestionID]
-
-			// Validate multiple choice answers
-			if question.IsMultipleChoice() && len(answerReq.SelectedChoices) > 0 {
-				// Check if multiple selection is allowed
-				if !question.AllowMultiple && len(answerReq.SelectedChoices) > 1 {
-					c.JSON(http.StatusBadRequest, gin.H{
-						"error": "Multiple selections not allowed for question: " + question.QuestionText,
-					})
-					return
-				}
-
-				// Validate that all selected choices are valid options
-				validChoices := make(map[string]bool)
-				for _, choice := range question.Choices {
-					validChoices[choice] = true
-				}
-
-				for _, selectedChoice := range answerReq.SelectedChoices {
-					if !validChoices[selectedChoice] {
-						c.JSON(http.StatusBadRequest, gin.H{
-							"error": "Invalid choice '" + selectedChoice + "' for question: " + question.QuestionText,
-						})
-						return
-					}
-				}
-			}
+	answers, ok := h.validateAnswers(c, questions, submitReq.Answers)
+	if !ok {
+		return
+	}
+	submitReq.Answers = answers
 
-			// Validate required questions have answers
-			if question.Required {
-				hasAnswer := false
-				if answerReq.Answer != nil && *answerReq.Answer != "" {
-					hasAnswer = true
-				}
-				if len(answerReq.SelectedChoices) > 0 {
-					hasAnswer = true
-				}
-
-				if !hasAnswer {
-					c.JSON(http.StatusBadRequest, gin.H{
-						"error": "Answer required for question: " + question.QuestionText,
-					})
-					return
-				}
-			}
-		}
+	version, ok := h.recordVersion(c, form, questions)
+	if !ok {
+		return
 	}
 
 	// Get client IP
@@ -143,17 +124,168 @@ func (h *ResponseHandler) SubmitResponse(c *gin.Context) {
 
 	// Create response model
 	response := &model.FilledForm{}
-	response.FromCreateRequest(&submitReq, userIP)
+	response.FromCreateRequest(&submitReq, userIP, form.AnonymityLevel, func(kind respondent.Kind, value string) string {
+		return h.identities.Hash(form.ID, kind, value)
+	})
+	response.FormVersion = &version.Version
+
+	// Measure the completion time when the client sent back its start token
+	if submitReq.StartToken != nil {
+		startedAt, err := h.startTokens.Verify(*submitReq.StartToken, form.ID, response.CreatedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start token"})
+			return
+		}
+		response.SetCompletionTime(startedAt, h.fastThreshold)
+	}
+
+	if !h.identifyRespondent(c, form, response) {
+		return
+	}
+
+	// Forms with an edit window give the respondent a secret edit token
+	var editToken string
+	if form.AllowsEdits() {
+		token, hash, err := edittoken.Generate()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit response"})
+			return
+		}
+		editToken = token
+		response.EditTokenHash = &hash
+	}
 
 	// Save response with individual question answers
-	if err := h.responseRepo.CreateResponse(c.Request.Context(), response, submitReq.Answers); err != nil {
+	formClosed, err := h.responseRepo.CreateResponse(c.Request.Context(), response, submitReq.Answers)
+	if err != nil {
+		if err.Error() == "form has reached its response limit" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Form has reached its response limit"})
+			return
+		}
+		if err.Error() == "form not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+			return
+		}
+		if err.Error() == "duplicate response" {
+			c.JSON(http.StatusConflict, gin.H{"error": duplicateMessages[form.DuplicatePolicy]})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit response"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	// Respondent IPs are not shared with webhook receivers
+	created := response.ToDetailResponse()
+	created.UserIP = nil
+	h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventResponseCreated, created)
+
+	// The submission that reaches the response limit closes the form
+	if formClosed {
+		form.Status = model.FormStatusClosed
+		h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventFormClosed, form.ToResponse())
+	}
+
+	result := gin.H{
 		"message":     "Response submitted successfully",
 		"response_id": response.ID,
+	}
+	if editToken != "" {
+		result["edit_token"] = editToken
+		result["edit_expires_at"] = form.EditDeadline(response.CreatedAt)
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// validateAnswers checks the answers against the questions of the form and
+// returns those of questions shown to the respondent. It writes the error
+// response and returns false when an answer is invalid or a required answer
+// is missing.
+func (h *ResponseHandler) validateAnswers(c *gin.Context, formQuestions []*model.Question, answers []model.CreateAnswerRequest) ([]model.CreateAnswerRequest, bool) {
+	shownAnswers, err := model.ValidateAnswers(formQuestions, answers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return shownAnswers, true
+}
+
+// recordVersion returns the version of the form the answers were validated
+// against, recording it when the form changed since its latest version. It
+// writes the error response and returns false on failure.
+func (h *ResponseHandler) recordVersion(c *gin.Context, form *model.Form, questions []*model.Question) (*model.FormVersion, bool) {
+	version, err := h.versionRepo.EnsureVersion(c.Request.Context(), form.ID, model.NewFormDefinition(form, questions))
+	if err != nil {
+		log.Error().Err(err).Str("form_id", form.ID.String()).Msg("Failed to record form version")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit response"})
+		return nil, false
+	}
+	return version, true
+}
+
+// identifyRespondent sets the hashed identifier the duplicate policy of the
+// form compares. It writes the error response and returns false when the
+// respondent cannot be identified.
+func (h *ResponseHandler) identifyRespondent(c *gin.Context, form *model.Form, response *model.FilledForm) bool {
+	switch form.DuplicatePolicy {
+	case model.DuplicatePolicyEmail:
+		// Pseudonymous responses already carry the hash
+		if response.EmailHash == nil {
+			if response.Email == nil || strings.TrimSpace(*response.Email) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required to respond to this form"})
+				return false
+			}
+			hash := h.identities.Hash(form.ID, respondent.KindEmail, *response.Email)
+			response.EmailHash = &hash
+		}
+
+	case model.DuplicatePolicyIP:
+		// Only the hash of the address is kept
+		if response.IPHash == nil && response.UserIP != nil {
+			hash := h.identities.Hash(form.ID, respondent.KindIP, *response.UserIP)
+			response.IPHash = &hash
+		}
+		response.UserIP = nil
+		if response.IPHash == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Could not determine the client address"})
+			return false
+		}
+
+	case model.DuplicatePolicyBrowser:
+		token, _ := c.Cookie(respondent.BrowserCookie)
+		browserID, ok := h.identities.VerifyBrowserToken(token)
+		if !ok {
+			newToken, err := h.identities.IssueBrowserToken()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit response"})
+				return false
+			}
+			browserID, _ = h.identities.VerifyBrowserToken(newToken)
+			h.setBrowserCookie(c, newToken)
+		}
+		hash := h.identities.Hash(form.ID, respondent.KindBrowser, browserID)
+		response.BrowserHash = &hash
+	}
+
+	return true
+}
+
+// setBrowserCookie stores the browser token for a year. Forms embedded on
+// other sites need SameSite=None, which browsers only accept on secure cookies.
+func (h *ResponseHandler) setBrowserCookie(c *gin.Context, token string) {
+	sameSite := http.SameSiteLaxMode
+	if h.secureCookies {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     respondent.BrowserCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+		Secure:   h.secureCookies,
+		HttpOnly: true,
+		SameSite: sameSite,
 	})
 }
 
@@ -168,6 +300,7 @@ func (h *ResponseHandler) SubmitResponse(c *gin.Context) {
 // @Success 200 {object} object{response=model.ResponseDetailResponse} "Response details"
 // @Failure 400 {object} object{error=string} "Invalid response ID"
 // @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
 // @Failure 404 {object} object{error=string} "Response not found"
 // @Failure 500 {object} object{error=string} "Internal server error"
 // @Router /api/response/{id} [get]
@@ -189,7 +322,365 @@ func (h *ResponseHandler) GetResponse(c *gin.Context) {
 		return
 	}
 
+	// Answers are shown against the questions the respondent saw
+	if err := applyVersions(c.Request.Context(), h.versionRepo, response); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get response"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"response": response,
 	})
 }
+
+// UpdateResponse handles PUT /api/response/:id
+// @Summary Edit a response
+// @Description Replace the answers of a response with the edit token returned when it was submitted (public endpoint). The answers are validated again and the form must still be open and within its edit window.
+// @Tags Responses
+// @Accept json
+// @Produce json
+// @Param id path string true "Response ID"
+// @Param X-Edit-Token header string true "Edit token returned when the response was submitted"
+// @Param response body model.UpdateResponseRequest true "Complete set of answers"
+// @Success 200 {object} object{message=string,response_id=string} "Response updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form not accepting responses"
+// @Failure 401 {object} object{error=string} "Edit token required"
+// @Failure 403 {object} object{error=string} "Invalid edit token or edit window expired"
+// @Failure 404 {object} object{error=string} "Response not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/response/{id} [put]
+func (h *ResponseHandler) UpdateResponse(c *gin.Context) {
+	var updateReq model.UpdateResponseRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	response, form, ok := h.authorizeEdit(c)
+	if !ok {
+		return
+	}
+
+	// The email the duplicate policy compares cannot change
+	if form.DuplicatePolicy == model.DuplicatePolicyEmail && updateReq.Email != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email cannot be changed for this form"})
+		return
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate questions"})
+		return
+	}
+
+	// Required answers cannot be dropped by the edit
+	shownAnswers, ok := h.validateAnswers(c, questions, updateReq.AnswerRequests())
+	if !ok {
+		return
+	}
+
+	// The edited answers were validated against the current version
+	version, ok := h.recordVersion(c, form, questions)
+	if !ok {
+		return
+	}
+
+	// Answers to the same question keep their ID
+	answerIDs := make(map[uuid.UUID]uuid.UUID, len(response.Answers))
+	for _, answer := range response.Answers {
+		answerIDs[answer.QuestionID] = answer.ID
+	}
+	answers := make([]model.UpdateAnswerRequest, len(shownAnswers))
+	for i, answerReq := range shownAnswers {
+		answers[i] = model.UpdateAnswerRequest{
+			QuestionID:      answerReq.QuestionID,
+			Answer:          answerReq.Answer,
+			SelectedChoices: answerReq.SelectedChoices,
+		}
+		if id, exists := answerIDs[answerReq.QuestionID]; exists {
+			answers[i].ID = &id
+			delete(answerIDs, answerReq.QuestionID)
+		}
+	}
+
+	response.UpdateFromRequest(&updateReq)
+	response.ApplyAnonymity(form.AnonymityLevel)
+	response.FormVersion = &version.Version
+
+	if err := h.responseRepo.UpdateResponse(c.Request.Context(), response, answers); err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update response"})
+		return
+	}
+
+	// Respondent IPs are not shared with webhook receivers
+	updated := response.ToDetailResponse()
+	updated.UserIP = nil
+	h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventResponseUpdated, updated)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Response updated successfully",
+		"response_id": response.ID,
+	})
+}
+
+// DeleteResponse handles DELETE /api/response/:id
+// @Summary Delete a response
+// @Description Delete a response with the edit token returned when it was submitted (public endpoint), while the form is within its edit window
+// @Tags Responses
+// @Accept json
+// @Produce json
+// @Param id path string true "Response ID"
+// @Param X-Edit-Token header string true "Edit token returned when the response was submitted"
+// @Success 200 {object} object{message=string} "Response deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid response ID or form not accepting responses"
+// @Failure 401 {object} object{error=string} "Edit token required"
+// @Failure 403 {object} object{error=string} "Invalid edit token or edit window expired"
+// @Failure 404 {object} object{error=string} "Response not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/response/{id} [delete]
+func (h *ResponseHandler) DeleteResponse(c *gin.Context) {
+	response, form, ok := h.authorizeEdit(c)
+	if !ok {
+		return
+	}
+
+	if err := h.responseRepo.DeleteResponse(c.Request.Context(), response.ID); err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete response"})
+		return
+	}
+
+	h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventResponseDeleted, gin.H{"id": response.ID})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Response deleted successfully",
+	})
+}
+
+// authorizeEdit loads the response of the request and its form, and checks
+// the edit token and edit window. It writes the error response and returns
+// false when the respondent may not change the response.
+func (h *ResponseHandler) authorizeEdit(c *gin.Context) (*model.FilledForm, *model.Form, bool) {
+	responseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+		return nil, nil, false
+	}
+
+	token := c.GetHeader(edittoken.Header)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Edit token required"})
+		return nil, nil, false
+	}
+
+	response, err := h.responseRepo.GetResponseByID(c.Request.Context(), responseID)
+	if err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return nil, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get response"})
+		return nil, nil, false
+	}
+
+	if response.EditTokenHash == nil || !edittoken.Matches(token, *response.EditTokenHash) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid edit token"})
+		return nil, nil, false
+	}
+
+	form, err := h.formRepo.GetFormByID(c.Request.Context(), response.FormID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get form"})
+		return nil, nil, false
+	}
+
+	now := time.Now()
+	if !form.AllowsEdits() || now.After(form.EditDeadline(response.CreatedAt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The edit window for this response has expired"})
+		return nil, nil, false
+	}
+	if form.StatusAt(now) != model.FormStatusOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting responses"})
+		return nil, nil, false
+	}
+
+	return response, form, true
+}
+
+// TriageResponse handles PATCH /api/form/:id/responses/:responseId
+// @Summary Triage a response
+// @Description Mark a response of a form you own as spam, reviewed or starred and set its tags
+// @Tags Responses
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param responseId path string true "Response ID"
+// @Param triage body model.TriageResponseRequest true "Triage state"
+// @Success 200 {object} object{message=string,response=model.ResponseListResponse} "Response updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Response not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/responses/{responseId} [patch]
+func (h *ResponseHandler) TriageResponse(c *gin.Context) {
+	var triageReq model.TriageResponseRequest
+	if err := c.ShouldBindJSON(&triageReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	response, err := h.loadFormResponse(c)
+	if err != nil {
+		return
+	}
+
+	if err := response.ApplyTriage(&triageReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.responseRepo.UpdateResponseTriage(c.Request.Context(), response); err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update response"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Response updated successfully",
+		"response": response.ToResponseList(),
+	})
+}
+
+// DeleteFormResponse handles DELETE /api/form/:id/responses/:responseId
+// @Summary Delete a response as the form owner
+// @Description Delete a response of a form you own
+// @Tags Responses
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param responseId path string true "Response ID"
+// @Success 200 {object} object{message=string} "Response deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Response not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/responses/{responseId} [delete]
+func (h *ResponseHandler) DeleteFormResponse(c *gin.Context) {
+	response, err := h.loadFormResponse(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.responseRepo.DeleteResponse(c.Request.Context(), response.ID); err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete response"})
+		return
+	}
+
+	h.webhooks.Publish(c.Request.Context(), response.FormID, model.WebhookEventResponseDeleted, gin.H{"id": response.ID})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Response deleted successfully",
+	})
+}
+
+// maxBulkDelete bounds how many responses a bulk delete request can name
+const maxBulkDelete = 500
+
+// BulkDeleteResponses handles POST /api/form/:id/responses/delete
+// @Summary Delete several responses
+// @Description Delete responses of a form you own; IDs of responses that do not belong to the form are ignored
+// @Tags Responses
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param request body model.BulkDeleteResponsesRequest true "Responses to delete"
+// @Success 200 {object} object{message=string,deleted=[]string,count=int} "Responses deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/responses/delete [post]
+func (h *ResponseHandler) BulkDeleteResponses(c *gin.Context) {
+	var deleteReq model.BulkDeleteResponsesRequest
+	if err := c.ShouldBindJSON(&deleteReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(deleteReq.ResponseIDs) == 0 || len(deleteReq.ResponseIDs) > maxBulkDelete {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("response_ids must list between 1 and %d responses", maxBulkDelete)})
+		return
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return
+	}
+
+	deleted, err := h.responseRepo.DeleteResponses(c.Request.Context(), form.ID, deleteReq.ResponseIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete responses"})
+		return
+	}
+
+	for _, id := range deleted {
+		h.webhooks.Publish(c.Request.Context(), form.ID, model.WebhookEventResponseDeleted, gin.H{"id": id})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Responses deleted successfully",
+		"deleted": deleted,
+		"count":   len(deleted),
+	})
+}
+
+// loadFormResponse loads the response in the :responseId path parameter,
+// checking it belongs to the form authorized for the route
+func (h *ResponseHandler) loadFormResponse(c *gin.Context) (*model.FilledForm, error) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return nil, err
+	}
+
+	responseID, err := uuid.Parse(c.Param("responseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+		return nil, err
+	}
+
+	response, err := h.responseRepo.GetResponseByID(c.Request.Context(), responseID)
+	if err != nil {
+		if err.Error() == "response not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+			return nil, err
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get response"})
+		return nil, err
+	}
+
+	if response.FormID != form.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Response not found"})
+		return nil, fmt.Errorf("response not found")
+	}
+
+	return response, nil
+}
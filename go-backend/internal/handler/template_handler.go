@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/authz"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// TemplateHandler handles form template HTTP requests
+type TemplateHandler struct {
+	templateRepo repository.TemplateRepo
+	formRepo     repository.FormRepo
+	questionRepo repository.QuestionRepo
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(templateRepo repository.TemplateRepo, formRepo repository.FormRepo, questionRepo repository.QuestionRepo) *TemplateHandler {
+	return &TemplateHandler{
+		templateRepo: templateRepo,
+		formRepo:     formRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+// PublishTemplate handles POST /api/form/:id/template
+// @Summary Publish a form as a template
+// @Description Save a snapshot of a form's settings and questions as a private or public template. Later changes to the form do not change the template.
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param template body model.CreateTemplateRequest true "Template data"
+// @Success 201 {object} object{message=string,template=model.TemplateResponse} "Template published successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/template [post]
+func (h *TemplateHandler) PublishTemplate(c *gin.Context) {
+	var createReq model.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&createReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	questions, err := h.questionRepo.GetQuestionsByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+
+	template := &model.FormTemplate{}
+	template.FromCreateRequest(&createReq, userID, form.ID, model.NewFormDefinition(form, questions))
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.templateRepo.CreateTemplate(c.Request.Context(), template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Template published successfully",
+		"template": template.ToResponse(true),
+	})
+}
+
+// ListTemplates handles GET /api/templates
+// @Summary List templates
+// @Description Get one page of templates, newest first: the user's own templates, every public template, or both
+// @Tags Templates
+// @Produce json
+// @Security Bearer
+// @Param scope query string false "all (default), mine or public"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{templates=[]model.TemplateResponse,page=model.PageInfo} "One page of templates"
+// @Failure 400 {object} object{error=string} "Invalid scope or pagination parameters"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	scope := model.TemplateScope(c.DefaultQuery("scope", string(model.TemplateScopeAll)))
+	if !scope.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope must be all, mine or public"})
+		return
+	}
+
+	page, err := parsePageRequest(c, "created_at", model.SortDesc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	templates, next, err := h.templateRepo.ListTemplates(c.Request.Context(), userID, scope, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list templates"})
+		return
+	}
+
+	templateResponses := make([]*model.TemplateResponse, len(templates))
+	for i, template := range templates {
+		templateResponses[i] = template.ToResponse(false)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templateResponses,
+		"page":      model.NewPageInfo(page.Limit, next),
+	})
+}
+
+// GetTemplate handles GET /api/templates/:id
+// @Summary Get a template
+// @Description Get a template with its form definition. Private templates are only visible to their author.
+// @Tags Templates
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Success 200 {object} object{template=model.TemplateResponse} "Template details"
+// @Failure 400 {object} object{error=string} "Invalid template ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 404 {object} object{error=string} "Template not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, _, err := h.loadTemplate(c, false)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": template.ToResponse(true),
+	})
+}
+
+// UpdateTemplate handles PUT /api/templates/:id
+// @Summary Update a template
+// @Description Rename a template or change its visibility; its form definition cannot be changed
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Param template body model.UpdateTemplateRequest true "Template update data"
+// @Success 200 {object} object{message=string,template=model.TemplateResponse} "Template updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or template ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't own this template"
+// @Failure 404 {object} object{error=string} "Template not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	template, _, err := h.loadTemplate(c, true)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	var updateReq model.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	template.UpdateFromRequest(&updateReq)
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.templateRepo.UpdateTemplate(c.Request.Context(), template); err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Template updated successfully",
+		"template": template.ToResponse(true),
+	})
+}
+
+// DeleteTemplate handles DELETE /api/templates/:id
+// @Summary Delete a template
+// @Description Delete a template; forms created from it are kept
+// @Tags Templates
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Success 200 {object} object{message=string} "Template deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid template ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't own this template"
+// @Failure 404 {object} object{error=string} "Template not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	template, _, err := h.loadTemplate(c, true)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.templateRepo.DeleteTemplate(c.Request.Context(), template.ID); err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+	})
+}
+
+// CreateFormFromTemplate handles POST /api/templates/:id/forms
+// @Summary Create a form from a template
+// @Description Create a draft form owned by the user with the settings and questions of a template
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Param form body model.CopyFormRequest true "Title and slug of the new form; the slug is required"
+// @Success 201 {object} object{message=string,form=model.Form} "Form created successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body, template ID or template definition"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 404 {object} object{error=string} "Template not found"
+// @Failure 409 {object} object{error=string} "Form with this slug already exists"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/templates/{id}/forms [post]
+func (h *TemplateHandler) CreateFormFromTemplate(c *gin.Context) {
+	template, userID, err := h.loadTemplate(c, false)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	var copyReq model.CopyFormRequest
+	if err := c.ShouldBindJSON(&copyReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if strings.TrimSpace(copyReq.Slug) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug is required"})
+		return
+	}
+
+	form, err := createFormFromDefinition(c, h.formRepo, &template.Definition, userID, &copyReq)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Form created successfully",
+		"form":    form,
+	})
+}
+
+// loadTemplate gets the template of the :id parameter for the authenticated
+// user. Private templates of other users are reported as not found; forWrite
+// additionally requires the user to be the author.
+func (h *TemplateHandler) loadTemplate(c *gin.Context, forWrite bool) (*model.FormTemplate, uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return nil, uuid.Nil, err
+	}
+
+	template, err := h.templateRepo.GetTemplateByID(c.Request.Context(), templateID)
+	if err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return nil, uuid.Nil, err
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get template"})
+		return nil, uuid.Nil, err
+	}
+
+	if !template.VisibleTo(userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return nil, uuid.Nil, gin.Error{Err: nil}
+	}
+	if forWrite && template.AuthorID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't own this template"})
+		return nil, uuid.Nil, gin.Error{Err: nil}
+	}
+
+	return template, userID, nil
+}
+
+// currentUserID returns the ID of the authenticated user
+func currentUserID(c *gin.Context) (uuid.UUID, error) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, gin.Error{Err: nil}
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, err
+	}
+
+	return userID, nil
+}
+
+// authorizedForm returns the form the authz middleware checked the caller's
+// role on for this route
+func authorizedForm(c *gin.Context) (*model.Form, error) {
+	form, ok := authz.FormFrom(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Form was not authorized"})
+		return nil, fmt.Errorf("route has no authorized form")
+	}
+	return form, nil
+}
+
+// createFormFromDefinition creates a form of userID with its questions from a
+// definition in one transaction
+func createFormFromDefinition(c *gin.Context, formRepo repository.FormRepo, definition *model.FormDefinition, userID uuid.UUID, copyReq *model.CopyFormRequest) (*model.Form, error) {
+	slug := strings.TrimSpace(copyReq.Slug)
+	form, questions, err := definition.NewForm(userID, strings.TrimSpace(copyReq.Title), slug, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, err
+	}
+
+	if err := formRepo.CreateFormWithQuestions(c.Request.Context(), form, questions); err != nil {
+		if err.Error() == "form with slug "+slug+" already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Form with this slug already exists"})
+			return nil, err
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create form"})
+		return nil, err
+	}
+
+	form.Questions = make([]model.Question, len(questions))
+	for i, question := range questions {
+		form.Questions[i] = *question
+	}
+
+	return form, nil
+}
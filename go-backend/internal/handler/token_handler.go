@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ayan-sh03/anoq/internal/accesstoken"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/refreshtoken"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// TokenHandler handles access and refresh token HTTP requests
+type TokenHandler struct {
+	userRepo         repository.UserRepo
+	refreshTokenRepo repository.RefreshTokenRepo
+	accessTokens     *accesstoken.Signer
+	refreshTokenTTL  time.Duration
+}
+
+// NewTokenHandler creates a new token handler
+func NewTokenHandler(userRepo repository.UserRepo, refreshTokenRepo repository.RefreshTokenRepo, accessTokens *accesstoken.Signer, refreshTokenTTL time.Duration) *TokenHandler {
+	return &TokenHandler{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		accessTokens:     accessTokens,
+		refreshTokenTTL:  refreshTokenTTL,
+	}
+}
+
+// IssueToken handles POST /api/auth/token
+// @Summary Sign in with tokens
+// @Description Exchange email and password for a short-lived signed access token and a refresh token. The access token is sent as a Bearer token and is checked without a database lookup; the refresh token gets the next pair from /api/auth/refresh.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credentials body object{email=string,password=string} true "User login credentials"
+// @Success 200 {object} model.TokenResponse "Tokens issued"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 401 {object} object{error=string} "Invalid email or password"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/auth/token [post]
+func (h *TokenHandler) IssueToken(c *gin.Context) {
+	var req struct {
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+
+	if !h.userRepo.CheckPassword(req.Password, user.PasswordHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+
+	// Each sign-in starts a new family of refresh tokens
+	now := time.Now()
+	token, hash, err := refreshtoken.Generate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue tokens"})
+		return
+	}
+
+	refresh := model.NewRefreshToken(user.ID, uuid.New(), hash, now, h.refreshTokenTTL)
+	if err := h.refreshTokenRepo.CreateRefreshToken(c.Request.Context(), refresh); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.tokenResponse(refresh, token, now))
+}
+
+// RefreshToken handles POST /api/auth/refresh
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and the next refresh token. Each refresh token can be used once; presenting a used one again revokes every refresh token from the same sign-in. Access tokens are verified by their signature alone, so those already issued stay valid until they expire; revocation only stops new ones being issued.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} model.TokenResponse "Tokens issued"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 401 {object} object{error=string} "Invalid, expired, revoked or reused refresh token"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/auth/refresh [post]
+func (h *TokenHandler) RefreshToken(c *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	current, err := h.refreshTokenRepo.GetRefreshTokenByHash(c.Request.Context(), refreshtoken.Hash(req.RefreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh tokens"})
+		return
+	}
+
+	now := time.Now()
+	if current.RevokedAt != nil {
+		// Its family was revoked, so whoever holds it may have stolen it
+		log.Warn().
+			Str("user_id", current.UserID.String()).
+			Str("family_id", current.FamilyID.String()).
+			Msg("Revoked refresh token presented")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has been revoked"})
+		return
+	}
+	if current.UsedAt != nil {
+		h.revokeReusedFamily(c, current, now)
+		return
+	}
+	if current.IsExpired(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has expired"})
+		return
+	}
+
+	token, hash, err := refreshtoken.Generate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh tokens"})
+		return
+	}
+
+	next := model.NewRefreshToken(current.UserID, current.FamilyID, hash, now, h.refreshTokenTTL)
+	if err := h.refreshTokenRepo.RotateRefreshToken(c.Request.Context(), current, next, now); err != nil {
+		if err.Error() == "refresh token already used" {
+			// Another request exchanged the token first
+			h.revokeReusedFamily(c, current, now)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.tokenResponse(next, token, now))
+}
+
+// RevokeToken handles POST /api/auth/revoke
+// @Summary Revoke a refresh token
+// @Description Revoke a refresh token along with every other refresh token from the same sign-in. Access tokens already issued stay valid until they expire. Unknown tokens are ignored.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} object{message=string} "Token revoked successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/auth/revoke [post]
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	token, err := h.refreshTokenRepo.GetRefreshTokenByHash(c.Request.Context(), refreshtoken.Hash(req.RefreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			// Telling callers which tokens exist would only help guess them
+			c.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+
+	if err := h.refreshTokenRepo.RevokeRefreshTokenFamily(c.Request.Context(), token.FamilyID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Token revoked successfully",
+	})
+}
+
+// revokeReusedFamily revokes the family of a refresh token presented after it
+// was used. Either the client or whoever copied its token is replaying it, so
+// neither can be trusted with the family any longer.
+func (h *TokenHandler) revokeReusedFamily(c *gin.Context, token *model.RefreshToken, now time.Time) {
+	log.Warn().
+		Str("user_id", token.UserID.String()).
+		Str("family_id", token.FamilyID.String()).
+		Msg("Refresh token reused; revoking its family")
+
+	if err := h.refreshTokenRepo.RevokeRefreshTokenFamily(c.Request.Context(), token.FamilyID, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh tokens"})
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected; sign in again"})
+}
+
+// tokenResponse pairs a new access token with the refresh token it was
+// issued alongside
+func (h *TokenHandler) tokenResponse(refresh *model.RefreshToken, token string, now time.Time) *model.TokenResponse {
+	accessToken, expiresAt := h.accessTokens.Issue(refresh.UserID, refresh.FamilyID, now)
+
+	return &model.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(h.accessTokens.TTL().Seconds()),
+		ExpiresAt:    expiresAt,
+		RefreshToken: token,
+	}
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayan-sh03/anoq/internal/accesstoken"
+	"github.com/ayan-sh03/anoq/internal/handler"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/refreshtoken"
+	"github.com/ayan-sh03/anoq/internal/repository/mocks"
+)
+
+func newRefreshContext(t *testing.T, refreshToken string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	jsonBody, _ := json.Marshal(model.RefreshTokenRequest{RefreshToken: refreshToken})
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	return c, w
+}
+
+func TestTokenHandler_RefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := accesstoken.NewSigner("secret", 15*time.Minute)
+
+	t.Run("Rotates the token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRefreshRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+		tokenHandler := handler.NewTokenHandler(mocks.NewMockUserRepo(ctrl), mockRefreshRepo, signer, time.Hour)
+
+		current := model.NewRefreshToken(uuid.New(), uuid.New(), refreshtoken.Hash("old"), time.Now(), time.Hour)
+		mockRefreshRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), refreshtoken.Hash("old")).Return(current, nil)
+		mockRefreshRepo.EXPECT().
+			RotateRefreshToken(gomock.Any(), current, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *model.RefreshToken, next *model.RefreshToken, _ time.Time) error {
+				assert.Equal(t, current.FamilyID, next.FamilyID)
+				assert.NotEqual(t, current.TokenHash, next.TokenHash)
+				return nil
+			})
+
+		c, w := newRefreshContext(t, "old")
+		tokenHandler.RefreshToken(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp model.TokenResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Bearer", resp.TokenType)
+		assert.NotEqual(t, "old", resp.RefreshToken)
+
+		claims, err := signer.Verify(resp.AccessToken, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, current.UserID.String(), claims.Subject)
+	})
+
+	t.Run("Reuse revokes the family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRefreshRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+		tokenHandler := handler.NewTokenHandler(mocks.NewMockUserRepo(ctrl), mockRefreshRepo, signer, time.Hour)
+
+		usedAt := time.Now().Add(-time.Minute)
+		current := model.NewRefreshToken(uuid.New(), uuid.New(), refreshtoken.Hash("old"), time.Now(), time.Hour)
+		current.UsedAt = &usedAt
+		mockRefreshRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), refreshtoken.Hash("old")).Return(current, nil)
+		mockRefreshRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), current.FamilyID, gomock.Any()).Return(nil)
+
+		c, w := newRefreshContext(t, "old")
+		tokenHandler.RefreshToken(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "reuse detected")
+	})
+
+	t.Run("Concurrent reuse revokes the family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRefreshRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+		tokenHandler := handler.NewTokenHandler(mocks.NewMockUserRepo(ctrl), mockRefreshRepo, signer, time.Hour)
+
+		current := model.NewRefreshToken(uuid.New(), uuid.New(), refreshtoken.Hash("old"), time.Now(), time.Hour)
+		mockRefreshRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), refreshtoken.Hash("old")).Return(current, nil)
+		mockRefreshRepo.EXPECT().
+			RotateRefreshToken(gomock.Any(), current, gomock.Any(), gomock.Any()).
+			Return(errors.New("refresh token already used"))
+		mockRefreshRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), current.FamilyID, gomock.Any()).Return(nil)
+
+		c, w := newRefreshContext(t, "old")
+		tokenHandler.RefreshToken(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRefreshRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+		tokenHandler := handler.NewTokenHandler(mocks.NewMockUserRepo(ctrl), mockRefreshRepo, signer, time.Hour)
+
+		revokedAt := time.Now()
+		current := model.NewRefreshToken(uuid.New(), uuid.New(), refreshtoken.Hash("old"), time.Now(), time.Hour)
+		current.RevokedAt = &revokedAt
+		mockRefreshRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), refreshtoken.Hash("old")).Return(current, nil)
+
+		c, w := newRefreshContext(t, "old")
+		tokenHandler.RefreshToken(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "revoked")
+	})
+}
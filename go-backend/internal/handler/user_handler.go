@@ -13,13 +13,18 @@ import (
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userRepo repository.UserRepo
+	userRepo         repository.UserRepo
+	refreshTokenRepo repository.RefreshTokenRepo
+	sessionTTL       time.Duration
 }
 
-// NewUserHandler creates a new user handler
-func NewUserHandler(userRepo repository.UserRepo) *UserHandler {
+// NewUserHandler creates a new user handler whose sessions expire once
+// unused for sessionTTL
+func NewUserHandler(userRepo repository.UserRepo, refreshTokenRepo repository.RefreshTokenRepo, sessionTTL time.Duration) *UserHandler {
 	return &UserHandler{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionTTL:       sessionTTL,
 	}
 }
 
@@ -80,7 +85,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	// Create session
-	session, err := h.userRepo.CreateSession(c.Request.Context(), user.ID)
+	session, err := h.userRepo.CreateSession(c.Request.Context(), user.ID, sessionClient(c), h.sessionTTL)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
@@ -130,7 +135,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}
 
 	// Create session
-	session, err := h.userRepo.CreateSession(c.Request.Context(), user.ID)
+	session, err := h.userRepo.CreateSession(c.Request.Context(), user.ID, sessionClient(c), h.sessionTTL)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
@@ -285,3 +290,127 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		"user":    user,
 	})
 }
+
+// ListSessions handles GET /api/user/sessions
+// @Summary List sessions
+// @Description Get the caller's signed-in sessions, most recently used first, with the device, user agent and IP address that signed in. The session making the request is marked current.
+// @Tags User
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{sessions=[]model.UserSession} "Sessions of the caller"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/sessions [get]
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	sessions, err := h.userRepo.ListSessionsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+	if sessions == nil {
+		sessions = []*model.UserSession{}
+	}
+
+	if current, ok := currentSession(c); ok {
+		for _, session := range sessions {
+			session.Current = session.ID == current.ID
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// DeleteSession handles DELETE /api/user/sessions/:id
+// @Summary Sign out a session
+// @Description Sign out one of the caller's sessions. Requests with its token are refused from then on.
+// @Tags User
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Session ID"
+// @Success 200 {object} object{message=string} "Session signed out successfully"
+// @Failure 400 {object} object{error=string} "Invalid session ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 404 {object} object{error=string} "Session not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/sessions/{id} [delete]
+func (h *UserHandler) DeleteSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.userRepo.DeleteUserSession(c.Request.Context(), userID, sessionID); err != nil {
+		if err.Error() == "session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign out session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session signed out successfully",
+	})
+}
+
+// LogoutEverywhere handles DELETE /api/user/sessions
+// @Summary Log out everywhere
+// @Description Sign out every session of the caller, including this one, and revoke their refresh tokens. Access tokens already issued stay valid until they expire.
+// @Tags User
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{message=string} "Logged out everywhere"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/user/sessions [delete]
+func (h *UserHandler) LogoutEverywhere(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.userRepo.DeleteUserSessions(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out everywhere"})
+		return
+	}
+
+	if err := h.refreshTokenRepo.RevokeUserRefreshTokens(c.Request.Context(), userID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out everywhere"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out everywhere",
+	})
+}
+
+// sessionClient describes the client of the request for a new session
+func sessionClient(c *gin.Context) model.SessionClient {
+	return model.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// currentSession returns the session that authenticated the request, if any
+func currentSession(c *gin.Context) (*model.UserSession, bool) {
+	sessionVal, exists := c.Get("session")
+	if !exists {
+		return nil, false
+	}
+	session, ok := sessionVal.(*model.UserSession)
+	return session, ok
+}
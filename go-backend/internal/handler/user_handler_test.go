@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -27,7 +28,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -64,10 +65,10 @@ func TestUserHandler_Register(t *testing.T) {
 			CreateUser(gomock.Any(), gomock.Any()).
 			Return(nil)
 
-		// Fix: CreateSession takes (context.Context, uuid.UUID) and returns (*model.UserSession, error)
+		// Fix: CreateSession takes (context.Context, uuid.UUID, model.SessionClient, time.Duration) and returns (*model.UserSession, error)
 		userID := uuid.New()
 		mockUserRepo.EXPECT().
-			CreateSession(gomock.Any(), gomock.Any()).
+			CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), 24*time.Hour).
 			Return(&model.UserSession{
 				ID:     uuid.New(),
 				UserID: userID,
@@ -91,7 +92,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -115,7 +116,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -143,7 +144,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -174,7 +175,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -210,7 +211,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -250,7 +251,7 @@ func TestUserHandler_Register(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -281,7 +282,7 @@ func TestUserHandler_Register(t *testing.T) {
 			Return(nil)
 
 		mockUserRepo.EXPECT().
-			CreateSession(gomock.Any(), gomock.Any()).
+			CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), 24*time.Hour).
 			Return(nil, errors.New("session error"))
 
 		userHandler.Register(c)
@@ -300,7 +301,7 @@ func TestUserHandler_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -332,7 +333,7 @@ func TestUserHandler_Login(t *testing.T) {
 			Return(true)
 
 		mockUserRepo.EXPECT().
-			CreateSession(gomock.Any(), mockUser.ID).
+			CreateSession(gomock.Any(), mockUser.ID, gomock.Any(), 24*time.Hour).
 			Return(&model.UserSession{Token: "session-token"}, nil)
 
 		userHandler.Login(c)
@@ -349,7 +350,7 @@ func TestUserHandler_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -366,7 +367,7 @@ func TestUserHandler_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -395,7 +396,7 @@ func TestUserHandler_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -434,7 +435,7 @@ func TestUserHandler_Login(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -465,7 +466,7 @@ func TestUserHandler_Login(t *testing.T) {
 			Return(true)
 
 		mockUserRepo.EXPECT().
-			CreateSession(gomock.Any(), mockUser.ID).
+			CreateSession(gomock.Any(), mockUser.ID, gomock.Any(), 24*time.Hour).
 			Return(nil, errors.New("session error"))
 
 		userHandler.Login(c)
@@ -482,7 +483,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -510,7 +511,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -528,7 +529,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -548,7 +549,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -570,7 +571,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -607,7 +608,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("user_id", userID.String())
@@ -632,7 +633,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		// No user_id in context
@@ -650,7 +651,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("user_id", userID.String())
@@ -669,7 +670,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("user_id", userID.String())
@@ -691,7 +692,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("user_id", userID.String())
@@ -724,7 +725,7 @@ func TestUserHandler_Logout(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -749,7 +750,7 @@ func TestUserHandler_Logout(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		// No session in context
@@ -767,7 +768,7 @@ func TestUserHandler_Logout(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepo(ctrl)
-		userHandler := handler.NewUserHandler(mockUserRepo)
+		userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -784,3 +785,65 @@ func TestUserHandler_Logout(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 }
+
+func TestUserHandler_ListSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	userHandler := handler.NewUserHandler(mockUserRepo, mocks.NewMockRefreshTokenRepo(ctrl), 24*time.Hour)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	userID := uuid.New()
+	current := &model.UserSession{ID: uuid.New(), UserID: userID, Token: "current-token"}
+	other := &model.UserSession{ID: uuid.New(), UserID: userID, Token: "other-token", Device: "Firefox on Linux"}
+	c.Set("user_id", userID.String())
+	c.Set("session", current)
+	req, _ := http.NewRequest(http.MethodGet, "/api/user/sessions", nil)
+	c.Request = req
+
+	mockUserRepo.EXPECT().
+		ListSessionsByUserID(gomock.Any(), userID).
+		Return([]*model.UserSession{other, {ID: current.ID, UserID: userID, Token: "current-token"}}, nil)
+
+	userHandler.ListSessions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "token")
+
+	var responseBody struct {
+		Sessions []model.UserSession `json:"sessions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &responseBody))
+	assert.Len(t, responseBody.Sessions, 2)
+	assert.False(t, responseBody.Sessions[0].Current)
+	assert.True(t, responseBody.Sessions[1].Current)
+}
+
+func TestUserHandler_LogoutEverywhere(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	mockRefreshRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+	userHandler := handler.NewUserHandler(mockUserRepo, mockRefreshRepo, 24*time.Hour)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	userID := uuid.New()
+	c.Set("user_id", userID.String())
+	req, _ := http.NewRequest(http.MethodDelete, "/api/user/sessions", nil)
+	c.Request = req
+
+	mockUserRepo.EXPECT().DeleteUserSessions(gomock.Any(), userID).Return(nil)
+	mockRefreshRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userID, gomock.Any()).Return(nil)
+
+	userHandler.LogoutEverywhere(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
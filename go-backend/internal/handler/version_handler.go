@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// VersionHandler handles form version HTTP requests
+type VersionHandler struct {
+	versionRepo repository.VersionRepo
+}
+
+// NewVersionHandler creates a new version handler
+func NewVersionHandler(versionRepo repository.VersionRepo) *VersionHandler {
+	return &VersionHandler{
+		versionRepo: versionRepo,
+	}
+}
+
+// ListVersions handles GET /api/form/:id/versions
+// @Summary List form versions
+// @Description List the versions of a form, newest first. A version is recorded when a response is submitted after the form's settings or questions changed.
+// @Tags Versions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Success 200 {object} object{versions=[]model.FormVersionSummary} "Versions of the form"
+// @Failure 400 {object} object{error=string} "Invalid form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/versions [get]
+func (h *VersionHandler) ListVersions(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	versions, err := h.versionRepo.ListVersions(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+	})
+}
+
+// GetVersion handles GET /api/form/:id/versions/:version
+// @Summary Get a form version
+// @Description Get the settings and questions of a form as respondents saw them in a version
+// @Tags Versions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} object{version=model.FormVersion} "Form version"
+// @Failure 400 {object} object{error=string} "Invalid form ID or version"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form or version not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/versions/{version} [get]
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	version, ok := h.loadVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": version,
+	})
+}
+
+// DiffVersions handles GET /api/form/:id/versions/:version/diff
+// @Summary Compare form versions
+// @Description List the settings and questions added, removed or changed between an earlier version and this one. Questions are matched by ID.
+// @Tags Versions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param version path int true "Version number"
+// @Param from query int false "Version to compare against (default the previous version)"
+// @Success 200 {object} object{diff=model.VersionDiff} "Changes between the versions"
+// @Failure 400 {object} object{error=string} "Invalid form ID or version"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form or version not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/versions/{version}/diff [get]
+func (h *VersionHandler) DiffVersions(c *gin.Context) {
+	to, ok := h.loadVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	fromParam := c.Query("from")
+	if fromParam == "" {
+		fromParam = strconv.Itoa(to.Version - 1)
+	}
+	fromNumber, err := strconv.Atoi(fromParam)
+	if err != nil || fromNumber < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version to compare against"})
+		return
+	}
+
+	from, err := h.versionRepo.GetVersion(c.Request.Context(), to.FormID, fromNumber)
+	if err != nil {
+		if err.Error() == "version not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Version %d not found", fromNumber)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get version"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": model.DiffVersions(from, to),
+	})
+}
+
+// loadVersion loads a version of the form authorized for the route. It writes
+// the error response and returns false on failure.
+func (h *VersionHandler) loadVersion(c *gin.Context, versionParam string) (*model.FormVersion, bool) {
+	number, err := strconv.Atoi(versionParam)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return nil, false
+	}
+
+	form, err := authorizedForm(c)
+	if err != nil {
+		return nil, false
+	}
+
+	version, err := h.versionRepo.GetVersion(c.Request.Context(), form.ID, number)
+	if err != nil {
+		if err.Error() == "version not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get version"})
+		return nil, false
+	}
+
+	return version, true
+}
+
+// applyVersions shows the answers of each response against the questions of
+// the version it answered. Responses from before versioning keep the
+// current questions.
+func applyVersions(ctx context.Context, versionRepo repository.VersionRepo, responses ...*model.FilledForm) error {
+	versions := make(map[int]*model.FormVersion)
+	for _, response := range responses {
+		if response.FormVersion == nil {
+			continue
+		}
+
+		version, loaded := versions[*response.FormVersion]
+		if !loaded {
+			var err error
+			version, err = versionRepo.GetVersion(ctx, response.FormID, *response.FormVersion)
+			if err != nil {
+				return err
+			}
+			versions[*response.FormVersion] = version
+		}
+
+		response.ApplyVersion(version)
+	}
+
+	return nil
+}
@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+	"github.com/ayan-sh03/anoq/internal/webhook"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// WebhookHandler handles webhook-related HTTP requests
+type WebhookHandler struct {
+	webhookRepo repository.WebhookRepo
+	dispatcher  *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookRepo repository.WebhookRepo, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// CreateWebhook handles POST /api/form/:id/webhooks
+// @Summary Create a webhook for a form
+// @Description Subscribe a URL to events of a form. Deliveries are signed with the secret in the X-Anoq-Signature-256 header; the secret is only returned here.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Param webhook body model.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} object{message=string,webhook=model.WebhookResponse} "Webhook created successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	var createReq model.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&createReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var secret string
+	if createReq.Secret != nil {
+		secret = *createReq.Secret
+	} else {
+		secret, err = webhook.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+			return
+		}
+	}
+
+	hook := &model.Webhook{}
+	hook.FromCreateRequest(&createReq, form.ID, secret)
+	if err := hook.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.dispatcher.CheckTarget(hook.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook URL must not point to a private or internal address"})
+		return
+	}
+
+	if err := h.webhookRepo.CreateWebhook(c.Request.Context(), hook); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	resp := hook.ToResponse()
+	resp.Secret = hook.Secret
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"webhook": resp,
+	})
+}
+
+// ListWebhooks handles GET /api/form/:id/webhooks
+// @Summary List webhooks of a form
+// @Description Get all webhooks subscribed to events of a form
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Form ID"
+// @Success 200 {object} object{webhooks=[]model.WebhookResponse} "List of webhooks"
+// @Failure 400 {object} object{error=string} "Invalid form ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Form not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/form/{id}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	form, err := authorizedForm(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	hooks, err := h.webhookRepo.ListWebhooksByFormID(c.Request.Context(), form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get webhooks"})
+		return
+	}
+
+	webhookResponses := make([]*model.WebhookResponse, len(hooks))
+	for i, hook := range hooks {
+		webhookResponses[i] = hook.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhookResponses,
+	})
+}
+
+// GetWebhook handles GET /api/webhooks/:id
+// @Summary Get a webhook by ID
+// @Description Get details of a specific webhook
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} object{webhook=model.WebhookResponse} "Webhook details"
+// @Failure 400 {object} object{error=string} "Invalid webhook ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	hook, err := h.loadWebhook(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": hook.ToResponse(),
+	})
+}
+
+// UpdateWebhook handles PUT /api/webhooks/:id
+// @Summary Update a webhook
+// @Description Change the URL, secret, events or active state of a webhook
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param webhook body model.UpdateWebhookRequest true "Updated webhook data"
+// @Success 200 {object} object{message=string,webhook=model.WebhookResponse} "Webhook updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or webhook ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	hook, err := h.loadWebhook(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	var updateReq model.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	hook.UpdateFromRequest(&updateReq)
+	if err := hook.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.dispatcher.CheckTarget(hook.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook URL must not point to a private or internal address"})
+		return
+	}
+
+	if err := h.webhookRepo.UpdateWebhook(c.Request.Context(), hook); err != nil {
+		if err.Error() == "webhook not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated successfully",
+		"webhook": hook.ToResponse(),
+	})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+// @Summary Delete a webhook
+// @Description Delete a webhook and cancel its pending deliveries. Past deliveries stay in the log.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} object{message=string} "Webhook deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid webhook ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	hook, err := h.loadWebhook(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.webhookRepo.DeleteWebhook(c.Request.Context(), hook.ID); err != nil {
+		if err.Error() == "webhook not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListDeliveries handles GET /api/webhooks/:id/deliveries
+// @Summary List deliveries of a webhook
+// @Description Get the most recent delivery attempts of a webhook, newest first
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries (default 50, max 200)"
+// @Success 200 {object} object{deliveries=[]model.WebhookDelivery} "List of deliveries"
+// @Failure 400 {object} object{error=string} "Invalid webhook ID or limit"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	hook, err := h.loadWebhook(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	limit := defaultDeliveriesLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxDeliveriesLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	deliveries, err := h.webhookRepo.ListDeliveriesByWebhookID(c.Request.Context(), hook.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+	})
+}
+
+// RedeliverDelivery handles POST /api/webhooks/:id/deliveries/:deliveryId/redeliver
+// @Summary Redeliver a webhook delivery
+// @Description Queue a new delivery with the same payload and signature as an earlier one
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} object{message=string,delivery=model.WebhookDelivery} "Redelivery queued"
+// @Failure 400 {object} object{error=string} "Invalid webhook or delivery ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have access to this form"
+// @Failure 404 {object} object{error=string} "Webhook or delivery not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	hook, err := h.loadWebhook(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	original, err := h.webhookRepo.GetDeliveryByID(c.Request.Context(), deliveryID)
+	if err != nil {
+		if err.Error() == "delivery not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get delivery"})
+		return
+	}
+
+	if original.WebhookID == nil || *original.WebhookID != hook.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	delivery, err := h.dispatcher.Redeliver(c.Request.Context(), original)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeliver"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Redelivery queued",
+		"delivery": delivery,
+	})
+}
+
+// loadWebhook loads the webhook in the :id path parameter
+func (h *WebhookHandler) loadWebhook(c *gin.Context) (*model.Webhook, error) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return nil, err
+	}
+
+	hook, err := h.webhookRepo.GetWebhookByID(c.Request.Context(), webhookID)
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return nil, err
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get webhook"})
+		return nil, err
+	}
+
+	return hook, nil
+}
@@ -0,0 +1,671 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/authz"
+	"github.com/ayan-sh03/anoq/internal/invitetoken"
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/repository"
+)
+
+// WorkspaceHandler handles workspace, membership and invitation HTTP requests
+type WorkspaceHandler struct {
+	workspaceRepo repository.WorkspaceRepo
+	formRepo      repository.FormRepo
+	userRepo      repository.UserRepo
+	invitationTTL time.Duration
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(workspaceRepo repository.WorkspaceRepo, formRepo repository.FormRepo, userRepo repository.UserRepo, invitationTTL time.Duration) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceRepo: workspaceRepo,
+		formRepo:      formRepo,
+		userRepo:      userRepo,
+		invitationTTL: invitationTTL,
+	}
+}
+
+// CreateWorkspace handles POST /api/workspaces
+// @Summary Create a workspace
+// @Description Create a workspace whose members share its forms. The caller becomes its first admin.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param workspace body model.CreateWorkspaceRequest true "Workspace data"
+// @Success 201 {object} object{message=string,workspace=model.Workspace} "Workspace created successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var createReq model.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&createReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	workspace := model.NewWorkspace(&createReq, userID, time.Now())
+	if err := workspace.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.workspaceRepo.CreateWorkspace(c.Request.Context(), workspace, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create workspace"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Workspace created successfully",
+		"workspace": workspace,
+	})
+}
+
+// ListWorkspaces handles GET /api/workspaces
+// @Summary List the caller's workspaces
+// @Description Get the workspaces the caller is a member of, with their role in each, by name
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{workspaces=[]model.Workspace} "Workspaces of the caller"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces [get]
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	workspaces, err := h.workspaceRepo.ListWorkspacesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list workspaces"})
+		return
+	}
+	if workspaces == nil {
+		workspaces = []*model.Workspace{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspaces": workspaces,
+	})
+}
+
+// GetWorkspace handles GET /api/workspaces/:id
+// @Summary Get a workspace
+// @Description Get a workspace with the caller's role in it
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} object{workspace=model.Workspace} "Workspace"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have viewer access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Router /api/workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspace": workspace,
+	})
+}
+
+// UpdateWorkspace handles PUT /api/workspaces/:id
+// @Summary Rename a workspace
+// @Description Change the name of a workspace. Only admins may rename it.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param workspace body model.UpdateWorkspaceRequest true "New name"
+// @Success 200 {object} object{message=string,workspace=model.Workspace} "Workspace updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body or workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id} [put]
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	var updateReq model.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	workspace.Name = strings.TrimSpace(updateReq.Name)
+	workspace.UpdatedAt = time.Now()
+	if err := workspace.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.workspaceRepo.UpdateWorkspace(c.Request.Context(), workspace); err != nil {
+		if err.Error() == "workspace not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Workspace updated successfully",
+		"workspace": workspace,
+	})
+}
+
+// DeleteWorkspace handles DELETE /api/workspaces/:id
+// @Summary Delete a workspace
+// @Description Delete a workspace with its memberships and invitations. Its forms are kept and belong to their authors alone again. Only admins may delete it.
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} object{message=string} "Workspace deleted successfully"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.workspaceRepo.DeleteWorkspace(c.Request.Context(), workspace.ID); err != nil {
+		if err.Error() == "workspace not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Workspace deleted successfully",
+	})
+}
+
+// ListWorkspaceForms handles GET /api/workspaces/:id/forms
+// @Summary List a workspace's forms
+// @Description Get the forms that belong to a workspace, newest first
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{forms=[]model.Form,page=model.PageInfo} "One page of forms, newest first"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID or pagination parameters"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have viewer access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/forms [get]
+func (h *WorkspaceHandler) ListWorkspaceForms(c *gin.Context) {
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	page, err := parsePageRequest(c, "created_at", model.SortDesc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	forms, next, err := h.formRepo.ListFormsByWorkspaceID(c.Request.Context(), workspace.ID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list forms"})
+		return
+	}
+	if forms == nil {
+		forms = []*model.Form{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"forms": forms,
+		"page":  model.NewPageInfo(page.Limit, next),
+	})
+}
+
+// ListMembers handles GET /api/workspaces/:id/members
+// @Summary List a workspace's members
+// @Description Get the members of a workspace with their email addresses and roles, in the order they joined
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} object{members=[]model.WorkspaceMember} "Members of the workspace"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have viewer access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	members, err := h.workspaceRepo.ListMembers(c.Request.Context(), workspace.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list members"})
+		return
+	}
+	if members == nil {
+		members = []*model.WorkspaceMember{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+	})
+}
+
+// UpdateMember handles PUT /api/workspaces/:id/members/:userId
+// @Summary Change a member's role
+// @Description Change the role of a member of a workspace. A workspace always keeps at least one admin.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param userId path string true "User ID of the member"
+// @Param member body model.UpdateMemberRequest true "New role"
+// @Success 200 {object} object{message=string} "Member updated successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body, role or ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace or member not found"
+// @Failure 409 {object} object{error=string} "A workspace needs at least one admin"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/members/{userId} [put]
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	var updateReq model.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !updateReq.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be admin, editor, analyst or viewer"})
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	err = h.workspaceRepo.UpdateMemberRole(c.Request.Context(), workspace.ID, memberID, updateReq.Role, time.Now())
+	if err != nil {
+		respondMemberChangeError(c, err, "Failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member updated successfully",
+	})
+}
+
+// RemoveMember handles DELETE /api/workspaces/:id/members/:userId
+// @Summary Remove a member
+// @Description Remove a member from a workspace. A workspace always keeps at least one admin.
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param userId path string true "User ID of the member"
+// @Success 200 {object} object{message=string} "Member removed successfully"
+// @Failure 400 {object} object{error=string} "Invalid workspace or user ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace or member not found"
+// @Failure 409 {object} object{error=string} "A workspace needs at least one admin"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/members/{userId} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.workspaceRepo.RemoveMember(c.Request.Context(), workspace.ID, memberID); err != nil {
+		respondMemberChangeError(c, err, "Failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member removed successfully",
+	})
+}
+
+// LeaveWorkspace handles POST /api/workspaces/:id/leave
+// @Summary Leave a workspace
+// @Description Remove the caller from a workspace. The last admin cannot leave; they can delete the workspace instead.
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} object{message=string} "Left workspace successfully"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have viewer access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 409 {object} object{error=string} "A workspace needs at least one admin"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/leave [post]
+func (h *WorkspaceHandler) LeaveWorkspace(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.workspaceRepo.RemoveMember(c.Request.Context(), workspace.ID, userID); err != nil {
+		respondMemberChangeError(c, err, "Failed to leave workspace")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Left workspace successfully",
+	})
+}
+
+// CreateInvitation handles POST /api/workspaces/:id/invitations
+// @Summary Invite someone to a workspace
+// @Description Invite an email address to join a workspace with a role. The invitation token is returned once and is accepted by the user signed in with that address before the invitation expires.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param invitation body model.CreateInvitationRequest true "Invitation data"
+// @Success 201 {object} object{message=string,invitation=model.WorkspaceInvitation,invite_token=string} "Invitation created successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body, role or workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) CreateInvitation(c *gin.Context) {
+	var createReq model.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&createReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	token, tokenHash, err := invitetoken.Generate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+
+	invitation, err := model.NewWorkspaceInvitation(workspace.ID, &createReq, tokenHash, userID, time.Now(), h.invitationTTL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.workspaceRepo.CreateInvitation(c.Request.Context(), invitation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+
+	// Only the hash is stored, so this is the one time the token is available
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Invitation created successfully",
+		"invitation":   invitation,
+		"invite_token": token,
+	})
+}
+
+// ListInvitations handles GET /api/workspaces/:id/invitations
+// @Summary List pending invitations
+// @Description Get the invitations to a workspace that have not been accepted, newest first
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} object{invitations=[]model.WorkspaceInvitation} "Pending invitations"
+// @Failure 400 {object} object{error=string} "Invalid workspace ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) ListInvitations(c *gin.Context) {
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	invitations, err := h.workspaceRepo.ListPendingInvitations(c.Request.Context(), workspace.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list invitations"})
+		return
+	}
+	if invitations == nil {
+		invitations = []*model.WorkspaceInvitation{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+	})
+}
+
+// DeleteInvitation handles DELETE /api/workspaces/:id/invitations/:invitationId
+// @Summary Revoke an invitation
+// @Description Revoke a pending invitation so its token can no longer be accepted
+// @Tags Workspaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Workspace ID"
+// @Param invitationId path string true "Invitation ID"
+// @Success 200 {object} object{message=string} "Invitation revoked successfully"
+// @Failure 400 {object} object{error=string} "Invalid workspace or invitation ID"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "Access denied: you don't have admin access to this workspace"
+// @Failure 404 {object} object{error=string} "Workspace or invitation not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/workspaces/{id}/invitations/{invitationId} [delete]
+func (h *WorkspaceHandler) DeleteInvitation(c *gin.Context) {
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	workspace, err := authorizedWorkspace(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	if err := h.workspaceRepo.DeleteInvitation(c.Request.Context(), workspace.ID, invitationID); err != nil {
+		if err.Error() == "invitation not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// AcceptInvitation handles POST /api/invitations/accept
+// @Summary Accept an invitation
+// @Description Join a workspace with the role of an invitation. The caller must be signed in with the email address the invitation was sent to.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param invitation body model.AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} object{message=string,member=model.WorkspaceMember} "Invitation accepted successfully"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 401 {object} object{error=string} "Authentication required"
+// @Failure 403 {object} object{error=string} "This invitation was sent to a different email address"
+// @Failure 404 {object} object{error=string} "Invitation not found"
+// @Failure 409 {object} object{error=string} "Invitation already accepted, or the caller is already a member"
+// @Failure 410 {object} object{error=string} "Invitation has expired"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /api/invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	var acceptReq model.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&acceptReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		return // Error response already sent
+	}
+
+	invitation, err := h.workspaceRepo.GetInvitationByTokenHash(c.Request.Context(), invitetoken.Hash(acceptReq.Token))
+	if err != nil {
+		if err.Error() == "invitation not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invitation"})
+		return
+	}
+
+	now := time.Now()
+	if invitation.AcceptedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Invitation has already been accepted"})
+		return
+	}
+	if invitation.IsExpired(now) {
+		c.JSON(http.StatusGone, gin.H{"error": "Invitation has expired"})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if !invitation.IsFor(user.Email) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invitation was sent to a different email address"})
+		return
+	}
+
+	member, err := h.workspaceRepo.AcceptInvitation(c.Request.Context(), invitation, userID, now)
+	if err != nil {
+		switch err.Error() {
+		case "invitation already accepted":
+			c.JSON(http.StatusConflict, gin.H{"error": "Invitation has already been accepted"})
+		case "already a member":
+			c.JSON(http.StatusConflict, gin.H{"error": "You are already a member of this workspace"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"member":  member,
+	})
+}
+
+// authorizedWorkspace returns the workspace the authz middleware checked the
+// caller's role in for this route
+func authorizedWorkspace(c *gin.Context) (*model.Workspace, error) {
+	workspace, ok := authz.WorkspaceFrom(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Workspace was not authorized"})
+		return nil, fmt.Errorf("route has no authorized workspace")
+	}
+	return workspace, nil
+}
+
+// requireWorkspaceRole checks the caller has at least role in a workspace,
+// for requests that name a workspace in their body
+func requireWorkspaceRole(c *gin.Context, workspaceRepo repository.WorkspaceRepo, workspaceID, userID uuid.UUID, role model.WorkspaceRole) error {
+	member, err := workspaceRepo.GetMember(c.Request.Context(), workspaceID, userID)
+	if err != nil && err.Error() != "member not found" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workspace"})
+		return err
+	}
+
+	if member == nil || !member.Role.Allows(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied: you don't have " + string(role) + " access to this workspace"})
+		return fmt.Errorf("not a %s of workspace %s", role, workspaceID)
+	}
+
+	return nil
+}
+
+// respondMemberChangeError writes the response for a failed change to a
+// workspace's members
+func respondMemberChangeError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "workspace not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+	case "member not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+	case "workspace needs an admin":
+		c.JSON(http.StatusConflict, gin.H{"error": "A workspace needs at least one admin"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,31 @@
+// Package invitetoken generates the secret tokens that invite people to a
+// workspace. Only the hash of a token is stored.
+package invitetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const tokenSize = 32
+
+// Generate returns a new token along with the hash to store
+func Generate() (token, hash string, err error) {
+	buf := make([]byte, tokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, Hash(token), nil
+}
+
+// Hash returns the hex encoded SHA-256 of a token, by which its invitation
+// is looked up
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
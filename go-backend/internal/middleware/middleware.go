@@ -10,7 +10,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
-	"github.com/ayan-sh03/anoq/internal/config"
+	"github.com/ayan-sh03/anoq/internal/accesstoken"
+	"github.com/ayan-sh03/anoq/internal/apikey"
+	"github.com/ayan-sh03/anoq/internal/authz"
+	"github.com/ayan-sh03/anoq/internal/model"
 	"github.com/ayan-sh03/anoq/internal/repository"
 )
 
@@ -38,6 +41,7 @@ type RateLimitConfig struct {
 var (
 	generalLimiter  *RateLimiter
 	formLimiter     *RateLimiter
+	apiKeyLimiter   *RateLimiter
 	rateLimiterOnce sync.Once
 )
 
@@ -55,6 +59,11 @@ func initRateLimiters() {
 			WindowDuration:    time.Minute,     // per minute
 			CleanupInterval:   5 * time.Minute, // cleanup every 5 minutes
 		})
+
+		apiKeyLimiter = NewRateLimiter(RateLimitConfig{
+			WindowDuration:  time.Minute,     // per minute, limit set per request
+			CleanupInterval: 5 * time.Minute, // cleanup every 5 minutes
+		})
 	})
 }
 
@@ -255,46 +264,47 @@ func FormRateLimit() gin.HandlerFunc {
 	}
 }
 
-// APIKeyRateLimit provides rate limiting based on API keys (for future use)
-func APIKeyRateLimit(requestsPerMinute int) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			c.Next()
-			return
-		}
-
-		// Initialize rate limiters
-		initRateLimiters()
-
-		clientID := "api:" + apiKey
-		config := RateLimitConfig{
-			RequestsPerWindow: requestsPerMinute,
-			WindowDuration:    time.Minute,
-		}
+// allowAPIKey applies the per-minute rate limit of an API key. Keys are
+// counted by ID once they are validated, so made-up keys cannot spread
+// requests over many buckets.
+func allowAPIKey(c *gin.Context, key *model.APIKey, requestsPerMinute int) bool {
+	initRateLimiters()
 
-		if !generalLimiter.Allow(clientID, config) {
-			log.Warn().
-				Str("api_key", apiKey).
-				Str("path", c.Request.URL.Path).
-				Msg("API key rate limit exceeded")
+	clientID := "api:" + key.ID.String()
+	config := RateLimitConfig{
+		RequestsPerWindow: requestsPerMinute,
+		WindowDuration:    time.Minute,
+	}
 
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":               "API rate limit exceeded",
-				"message":             "API key rate limit exceeded. Please try again later.",
-				"retry_after_seconds": 60,
-			})
-			c.Abort()
-			return
-		}
+	if !apiKeyLimiter.Allow(clientID, config) {
+		log.Warn().
+			Str("api_key_id", key.ID.String()).
+			Str("path", c.Request.URL.Path).
+			Msg("API key rate limit exceeded")
 
-		c.Next()
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":               "API rate limit exceeded",
+			"message":             "API key rate limit exceeded. Please try again later.",
+			"retry_after_seconds": 60,
+		})
+		return false
 	}
+
+	return true
 }
 
-// Auth provides simple session-based authentication middleware
-func Auth(cfg *config.Config, userRepo *repository.UserRepository) gin.HandlerFunc {
-	return func(c *gin.Context) {
+// Authenticate identifies the caller from an API key in the X-API-Key header,
+// or else from the access or session token in the Authorization header or
+// session_token cookie. Access tokens are verified by their signature alone;
+// sessions expire once unused for sessionTTL. It sets user_id in the
+// context, along with access_token or user and session or api_key. It writes
+// the error response and returns false when the caller is not signed in.
+func Authenticate(userRepo *repository.UserRepository, apiKeyRepo *repository.APIKeyRepository, accessTokens *accesstoken.Signer, sessionTTL time.Duration, apiKeyRateLimit int) func(c *gin.Context) bool {
+	return func(c *gin.Context) bool {
+		if key := c.GetHeader(apikey.Header); key != "" {
+			return authenticateAPIKey(c, userRepo, apiKeyRepo, key, apiKeyRateLimit)
+		}
+
 		// Extract session token from Authorization header or Cookie
 		var token string
 
@@ -316,8 +326,11 @@ func Auth(cfg *config.Config, userRepo *repository.UserRepository) gin.HandlerFu
 
 		if token == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication token"})
-			c.Abort()
-			return
+			return false
+		}
+
+		if accesstoken.IsToken(token) {
+			return authenticateAccessToken(c, accessTokens, token)
 		}
 
 		// Get session from database
@@ -330,8 +343,7 @@ func Auth(cfg *config.Config, userRepo *repository.UserRepository) gin.HandlerFu
 			}
 			log.Debug().Err(err).Str("token", tokenPreview).Msg("Invalid session token")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
-			c.Abort()
-			return
+			return false
 		}
 
 		// Get user from database
@@ -339,8 +351,12 @@ func Auth(cfg *config.Config, userRepo *repository.UserRepository) gin.HandlerFu
 		if err != nil {
 			log.Error().Err(err).Str("user_id", session.UserID.String()).Msg("Failed to get user")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			c.Abort()
-			return
+			return false
+		}
+
+		// Push the expiry back so only idle sessions expire
+		if err := userRepo.TouchSession(c.Request.Context(), session.ID, time.Now(), sessionTTL); err != nil {
+			log.Warn().Err(err).Str("session_id", session.ID.String()).Msg("Failed to record session use")
 		}
 
 		// Set user information in context for handlers to use
@@ -348,6 +364,67 @@ func Auth(cfg *config.Config, userRepo *repository.UserRepository) gin.HandlerFu
 		c.Set("user", user)
 		c.Set("session", session)
 
-		c.Next()
+		return true
+	}
+}
+
+// authenticateAccessToken identifies the caller from a signed access token
+// without a database lookup, so a revoked sign-in is still let through until
+// its access token expires
+func authenticateAccessToken(c *gin.Context, accessTokens *accesstoken.Signer, token string) bool {
+	claims, err := accessTokens.Verify(token, time.Now())
+	if err != nil {
+		if err == accesstoken.ErrExpired {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Access token has expired"})
+			return false
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid access token"})
+		return false
+	}
+
+	c.Set("user_id", claims.Subject)
+	c.Set("access_token", claims)
+
+	return true
+}
+
+// authenticateAPIKey identifies the caller as the user who created an API key
+func authenticateAPIKey(c *gin.Context, userRepo *repository.UserRepository, apiKeyRepo *repository.APIKeyRepository, key string, requestsPerMinute int) bool {
+	apiKey, err := apiKeyRepo.GetAPIKeyByHash(c.Request.Context(), apikey.Hash(key))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return false
+		}
+		log.Error().Err(err).Msg("Failed to get API key")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check API key"})
+		return false
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key has expired or been revoked"})
+		return false
+	}
+
+	if !allowAPIKey(c, apiKey, requestsPerMinute) {
+		return false
 	}
+
+	user, err := userRepo.GetUserByID(c.Request.Context(), apiKey.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", apiKey.UserID.String()).Msg("Failed to get user")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return false
+	}
+
+	if err := apiKeyRepo.TouchAPIKey(c.Request.Context(), apiKey.ID, now); err != nil {
+		log.Warn().Err(err).Str("api_key_id", apiKey.ID.String()).Msg("Failed to record API key use")
+	}
+
+	c.Set("user_id", user.ID.String())
+	c.Set("user", user)
+	c.Set(authz.APIKeyKey, apiKey)
+
+	return true
 }
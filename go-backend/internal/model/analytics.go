@@ -0,0 +1,74 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsInterval represents the bucket size of the submissions time series
+type AnalyticsInterval string
+
+const (
+	AnalyticsIntervalHour AnalyticsInterval = "hour"
+	AnalyticsIntervalDay  AnalyticsInterval = "day"
+)
+
+// IsValid returns true if the interval is supported
+func (i AnalyticsInterval) IsValid() bool {
+	return i == AnalyticsIntervalHour || i == AnalyticsIntervalDay
+}
+
+// FormAnalytics represents aggregated results of a form
+// @Description Per-question aggregates and submissions over time for a form
+type FormAnalytics struct {
+	FormID           uuid.UUID           `json:"form_id"`
+	TotalSubmissions int                 `json:"total_submissions" example:"120"`
+	Interval         AnalyticsInterval   `json:"interval" example:"day"` // Bucket size of the timeline
+	Questions        []QuestionAnalytics `json:"questions"`
+	Timeline         []SubmissionBucket  `json:"timeline"`
+}
+
+// QuestionAnalytics represents the aggregated answers of a single question
+// @Description Answer and skip rates of a question, plus choice distributions for choice questions
+type QuestionAnalytics struct {
+	QuestionID   uuid.UUID         `json:"question_id"`
+	QuestionText string            `json:"question_text"`
+	Type         QuestionType      `json:"type"`
+	Position     int               `json:"position"`
+	Answered     int               `json:"answered" example:"96"`    // Submissions with a non-empty answer
+	Skipped      int               `json:"skipped" example:"24"`     // Submissions without an answer
+	AnswerRate   float64           `json:"answer_rate" example:"80"` // Percentage of submissions that answered
+	SkipRate     float64           `json:"skip_rate" example:"20"`   // Percentage of submissions that skipped
+	Choices      []ChoiceCount     `json:"choices,omitempty"`        // Distribution for multiple_choice and dropdown questions
+	CoOccurrence []ChoicePairCount `json:"co_occurrence,omitempty"`  // How often two choices were selected together, for multi-select questions
+}
+
+// ChoiceCount represents how often a choice was selected
+type ChoiceCount struct {
+	Choice     string  `json:"choice" example:"Red"`
+	Count      int     `json:"count" example:"42"`
+	Percentage float64 `json:"percentage" example:"43.75"` // Share of the question's answers that selected the choice
+}
+
+// ChoicePairCount represents how often two choices were selected together
+type ChoicePairCount struct {
+	First  string `json:"first" example:"Red"`
+	Second string `json:"second" example:"Blue"`
+	Count  int    `json:"count" example:"12"`
+}
+
+// SubmissionBucket represents the number of submissions in one interval
+type SubmissionBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count" example:"7"`
+}
+
+// Percentage returns part as a percentage of total, rounded to two decimals
+func Percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
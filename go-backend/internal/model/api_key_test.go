@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	expires := now.Add(time.Hour)
+
+	key, err := NewAPIKey(&CreateAPIKeyRequest{
+		Name:      " Nightly export ",
+		Scopes:    []APIKeyScope{APIKeyScopeResponsesRead, APIKeyScopeFormsRead, APIKeyScopeResponsesRead},
+		ExpiresAt: &expires,
+	}, userID, "anoq_abcdefg", "hash", now)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, key.ID)
+	assert.Equal(t, userID, key.UserID)
+	assert.Equal(t, "Nightly export", key.Name)
+	assert.Equal(t, JSONStringArray{"responses:read", "forms:read"}, key.Scopes)
+	assert.True(t, key.HasScope(APIKeyScopeFormsRead))
+	assert.False(t, key.HasScope(APIKeyScopeFormsWrite))
+	assert.True(t, key.IsActive(now))
+	assert.False(t, key.IsActive(expires))
+}
+
+func TestNewAPIKey_Invalid(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		req    CreateAPIKeyRequest
+		errMsg string
+	}{
+		{"no name", CreateAPIKeyRequest{Name: " ", Scopes: []APIKeyScope{APIKeyScopeFormsRead}}, "API key name is required"},
+		{"no scopes", CreateAPIKeyRequest{Name: "CI"}, "API key needs at least one scope"},
+		{"unknown scope", CreateAPIKeyRequest{Name: "CI", Scopes: []APIKeyScope{"forms:delete"}}, "Invalid API key scope 'forms:delete'"},
+		{"expired", CreateAPIKeyRequest{Name: "CI", Scopes: []APIKeyScope{APIKeyScopeFormsRead}, ExpiresAt: &past}, "API key expiry must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPIKey(&tt.req, uuid.New(), "anoq_abcdefg", "hash", now)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestAPIKey_IsActive_Revoked(t *testing.T) {
+	now := time.Now()
+	key := &APIKey{RevokedAt: &now}
+	assert.False(t, key.IsActive(now))
+}
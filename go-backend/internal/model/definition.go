@@ -0,0 +1,184 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FormDefinition is the portable structure of a form: its settings and
+// questions, without its identity, schedule or responses. Forms are
+// duplicated and instantiated from templates through it.
+// @Description Settings and questions a form can be recreated from
+type FormDefinition struct {
+	Title       string `json:"title" example:"Weekly check-in"`
+	Description string `json:"description" example:"How did this week go?"`
+
+	MaxResponses           *int            `json:"max_responses,omitempty" example:"100"`
+	DuplicatePolicy        DuplicatePolicy `json:"duplicate_policy,omitempty" example:"email"`
+	DuplicateWindowSeconds *int            `json:"duplicate_window_seconds,omitempty" example:"86400"`
+	AnonymityLevel         AnonymityLevel  `json:"anonymity_level,omitempty" example:"identified"`
+	EditWindowSeconds      *int            `json:"edit_window_seconds,omitempty" example:"3600"`
+
+	Questions []QuestionDefinition `json:"questions"`
+}
+
+// QuestionDefinition is a question of a FormDefinition. Its ID only
+// identifies the question within the definition, for display logic to refer to.
+type QuestionDefinition struct {
+	ID uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440003"`
+	CreateQuestionRequest
+}
+
+// NewFormDefinition captures the settings and questions of a form
+func NewFormDefinition(form *Form, questions []*Question) *FormDefinition {
+	def := &FormDefinition{
+		Title:       form.Title,
+		Description: form.Description,
+
+		MaxResponses:           form.MaxResponses,
+		DuplicatePolicy:        form.DuplicatePolicy,
+		DuplicateWindowSeconds: form.DuplicateWindowSeconds,
+		AnonymityLevel:         form.AnonymityLevel,
+		EditWindowSeconds:      form.EditWindowSeconds,
+
+		Questions: make([]QuestionDefinition, len(questions)),
+	}
+
+	for i, q := range questions {
+		def.Questions[i] = QuestionDefinition{
+			ID: q.ID,
+			CreateQuestionRequest: CreateQuestionRequest{
+				QuestionText:  q.QuestionText,
+				Type:          q.Type,
+				Position:      q.Position,
+				Required:      q.Required,
+				Choices:       []string(q.Choices),
+				AllowMultiple: q.AllowMultiple,
+				ScaleMin:      q.ScaleMin,
+				ScaleMax:      q.ScaleMax,
+				MinLabel:      q.MinLabel,
+				MaxLabel:      q.MaxLabel,
+				MinValue:      q.MinValue,
+				MaxValue:      q.MaxValue,
+				IntegerOnly:   q.IntegerOnly,
+				MaxLength:     q.MaxLength,
+				DisplayLogic:  q.DisplayLogic,
+			},
+		}
+	}
+
+	return def
+}
+
+// NewForm creates a form owned by authorID and its questions from the
+// definition. Everything gets a fresh ID and display logic is rewritten to
+// refer to the new questions. An empty title keeps the definition's title.
+// The form is created as a draft.
+func (d *FormDefinition) NewForm(authorID uuid.UUID, title, slug string, now time.Time) (*Form, []*Question, error) {
+	if title == "" {
+		title = d.Title
+	}
+	if strings.TrimSpace(title) == "" {
+		return nil, nil, errors.New("Title is required")
+	}
+
+	form := &Form{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: d.Description,
+		Slug:        slug,
+		AuthorID:    authorID,
+		Status:      FormStatusDraft,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	form.SetMaxResponses(d.MaxResponses)
+	form.SetDuplicatePolicy(d.DuplicatePolicy, d.DuplicateWindowSeconds)
+	form.SetAnonymityLevel(d.AnonymityLevel)
+	form.SetEditWindow(d.EditWindowSeconds)
+
+	for _, validate := range []func() error{form.ValidateMaxResponses, form.ValidateDuplicatePolicy, form.ValidateAnonymityLevel, form.ValidateEditWindow} {
+		if err := validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	questions := make([]*Question, len(d.Questions))
+	newIDs := make(map[uuid.UUID]uuid.UUID, len(d.Questions))
+	for i := range d.Questions {
+		def := &d.Questions[i]
+
+		question := &Question{}
+		question.FromCreateRequest(&def.CreateQuestionRequest, form.ID)
+		question.CreatedAt = now
+		if question.Position == 0 {
+			question.Position = i + 1
+		}
+		if err := question.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("Question '%s': %w", def.QuestionText, err)
+		}
+
+		if def.ID != uuid.Nil {
+			if _, exists := newIDs[def.ID]; exists {
+				return nil, nil, fmt.Errorf("Duplicate question ID %s", def.ID)
+			}
+			newIDs[def.ID] = question.ID
+		}
+		questions[i] = question
+	}
+
+	for _, question := range questions {
+		if question.DisplayLogic == nil {
+			continue
+		}
+
+		// Copy the logic so the definition keeps its own question IDs
+		logic := *question.DisplayLogic
+		logic.Conditions = make([]DisplayCondition, len(question.DisplayLogic.Conditions))
+		for i, condition := range question.DisplayLogic.Conditions {
+			newID, ok := newIDs[condition.QuestionID]
+			if !ok {
+				return nil, nil, fmt.Errorf("Condition refers to unknown question %s", condition.QuestionID)
+			}
+			condition.QuestionID = newID
+			logic.Conditions[i] = condition
+		}
+		question.DisplayLogic = &logic
+	}
+
+	if err := ValidateDisplayLogic(questions); err != nil {
+		return nil, nil, err
+	}
+
+	return form, questions, nil
+}
+
+// Value implements the driver.Valuer interface
+func (d FormDefinition) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface
+func (d *FormDefinition) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan FormDefinition from non-[]byte")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// CopySlug returns a new slug for a copy of the form with the given slug
+func CopySlug(slug string) string {
+	suffix := "-copy-" + uuid.NewString()[:8]
+	if len(slug)+len(suffix) > 255 {
+		slug = slug[:255-len(suffix)]
+	}
+	return slug + suffix
+}
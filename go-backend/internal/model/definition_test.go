@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDefinition_NewFormRemapsDisplayLogic(t *testing.T) {
+	source := &Form{ID: uuid.New(), Title: "Weekly", DuplicatePolicy: DuplicatePolicyEmail, AnonymityLevel: AnonymityIdentified}
+	first := &Question{ID: uuid.New(), FormID: source.ID, QuestionText: "Attending?", Type: QuestionTypeYesNo, Position: 1}
+	second := &Question{
+		ID: uuid.New(), FormID: source.ID, QuestionText: "Why not?", Type: QuestionTypeBasic, Position: 2,
+		DisplayLogic: &DisplayLogic{Match: LogicMatchAll, Conditions: []DisplayCondition{{QuestionID: first.ID, Operator: ConditionEquals, Value: "No"}}},
+	}
+	def := NewFormDefinition(source, []*Question{first, second})
+
+	authorID := uuid.New()
+	form, questions, err := def.NewForm(authorID, "", "weekly-2", time.Now())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, source.ID, form.ID)
+	assert.Equal(t, "Weekly", form.Title)
+	assert.Equal(t, authorID, form.AuthorID)
+	assert.Equal(t, DuplicatePolicyEmail, form.DuplicatePolicy)
+	require.Len(t, questions, 2)
+	assert.NotEqual(t, first.ID, questions[0].ID)
+	assert.Equal(t, form.ID, questions[1].FormID)
+	assert.Equal(t, questions[0].ID, questions[1].DisplayLogic.Conditions[0].QuestionID)
+
+	// The definition keeps referring to its own question IDs
+	assert.Equal(t, first.ID, def.Questions[1].DisplayLogic.Conditions[0].QuestionID)
+}
+
+func TestFormDefinition_NewFormRejectsInvalidDefinitions(t *testing.T) {
+	unknown := &FormDefinition{Title: "Broken", Questions: []QuestionDefinition{{
+		ID: uuid.New(),
+		CreateQuestionRequest: CreateQuestionRequest{
+			QuestionText: "Why?", Type: QuestionTypeBasic, Position: 1,
+			DisplayLogic: &DisplayLogic{Match: LogicMatchAll, Conditions: []DisplayCondition{{QuestionID: uuid.New(), Operator: ConditionIsAnswered}}},
+		},
+	}}}
+	_, _, err := unknown.NewForm(uuid.New(), "", "broken", time.Now())
+	assert.ErrorContains(t, err, "unknown question")
+
+	noChoices := &FormDefinition{Title: "Broken", Questions: []QuestionDefinition{{
+		CreateQuestionRequest: CreateQuestionRequest{QuestionText: "Pick", Type: QuestionTypeMultipleChoice},
+	}}}
+	_, _, err = noChoices.NewForm(uuid.New(), "", "broken", time.Now())
+	assert.Error(t, err)
+
+	_, _, err = (&FormDefinition{}).NewForm(uuid.New(), "", "untitled", time.Now())
+	assert.EqualError(t, err, "Title is required")
+}
+
+func TestCopySlug(t *testing.T) {
+	slug := CopySlug("weekly")
+	assert.Regexp(t, `^weekly-copy-[0-9a-f]{8}$`, slug)
+	assert.Len(t, CopySlug(string(make([]byte, 300))), 255)
+}
+
+func TestFormTemplate_VisibilityAndValidation(t *testing.T) {
+	authorID := uuid.New()
+	template := &FormTemplate{}
+	template.FromCreateRequest(&CreateTemplateRequest{Name: "  Check-in  "}, authorID, uuid.New(), &FormDefinition{})
+
+	assert.Equal(t, "Check-in", template.Name)
+	assert.Equal(t, TemplateVisibilityPrivate, template.Visibility)
+	assert.NoError(t, template.Validate())
+	assert.True(t, template.VisibleTo(authorID))
+	assert.False(t, template.VisibleTo(uuid.New()))
+
+	public := TemplateVisibilityPublic
+	template.UpdateFromRequest(&UpdateTemplateRequest{Visibility: &public})
+	assert.True(t, template.VisibleTo(uuid.New()))
+
+	invalid := TemplateVisibility("friends")
+	template.UpdateFromRequest(&UpdateTemplateRequest{Visibility: &invalid})
+	assert.Error(t, template.Validate())
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,40 +13,140 @@ import (
 type FormStatus string
 
 const (
+	FormStatusDraft  FormStatus = "draft" // Not yet published, only the owner can see it
 	FormStatusOpen   FormStatus = "open"
 	FormStatusClosed FormStatus = "closed"
 )
 
+// IsValid returns true if the status is known
+func (s FormStatus) IsValid() bool {
+	switch s {
+	case FormStatusDraft, FormStatusOpen, FormStatusClosed:
+		return true
+	}
+	return false
+}
+
+// DuplicatePolicy represents how a form prevents duplicate submissions
+type DuplicatePolicy string
+
+const (
+	DuplicatePolicyNone    DuplicatePolicy = "none"    // Any number of responses per respondent
+	DuplicatePolicyEmail   DuplicatePolicy = "email"   // One response per email address
+	DuplicatePolicyIP      DuplicatePolicy = "ip"      // One response per IP address
+	DuplicatePolicyBrowser DuplicatePolicy = "browser" // One response per browser, tracked with a signed cookie
+)
+
+// IsValid returns true if the policy is known
+func (p DuplicatePolicy) IsValid() bool {
+	switch p {
+	case DuplicatePolicyNone, DuplicatePolicyEmail, DuplicatePolicyIP, DuplicatePolicyBrowser:
+		return true
+	}
+	return false
+}
+
+// AnonymityLevel represents which respondent identifiers a form keeps
+type AnonymityLevel string
+
+const (
+	AnonymityIdentified   AnonymityLevel = "identified"   // IP address, name and email are stored
+	AnonymityPseudonymous AnonymityLevel = "pseudonymous" // Only salted hashes of the email and IP address are stored
+	AnonymityAnonymous    AnonymityLevel = "anonymous"    // No IP address, name or email is stored
+)
+
+// IsValid returns true if the level is known
+func (l AnonymityLevel) IsValid() bool {
+	switch l {
+	case AnonymityIdentified, AnonymityPseudonymous, AnonymityAnonymous:
+		return true
+	}
+	return false
+}
+
+// AnonymityDeclaration tells respondents what a form stores about them
+type AnonymityDeclaration struct {
+	Level             AnonymityLevel `json:"level" example:"pseudonymous"`
+	StoresName        bool           `json:"stores_name"`
+	StoresEmail       bool           `json:"stores_email"`
+	StoresIP          bool           `json:"stores_ip"`
+	HashedIdentifiers []string       `json:"hashed_identifiers"` // Identifiers kept only as salted hashes
+	Statement         string         `json:"statement" example:"Your IP address, name and email are not stored. Only salted hashes of your email and IP address are kept to detect duplicate responses."`
+}
+
 // Form represents a form in the system
 // @Description Form structure containing all form details
 type Form struct {
 	ID          uuid.UUID  `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440002"`                           // Form unique identifier
 	Title       string     `json:"title" db:"title" validate:"required,min=1,max=255" example:"Customer Feedback Form"` // Form title
 	AuthorID    uuid.UUID  `json:"author_id" db:"author_id" example:"550e8400-e29b-41d4-a716-446655440000"`             // Form creator's user ID
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" db:"workspace_id"`                                            // Workspace sharing the form; only the author has access when omitted
 	Description string     `json:"description" db:"description" example:"A form to collect customer feedback"`          // Form description
 	Slug        string     `json:"slug" db:"slug" validate:"required,min=1,max=255" example:"customer-feedback-2023"`   // URL-friendly form identifier
-	Status      FormStatus `json:"status" db:"status" example:"open"`                                                   // Form status (open/closed)
+	Status      FormStatus `json:"status" db:"status" example:"open"`                                                   // Form status (draft/open/closed)
 	CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`                           // Form creation timestamp
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`                           // Last modification timestamp
 	Questions   []Question `json:"questions,omitempty"`                                                                 // List of questions in the form
 	Author      *User      `json:"author,omitempty"`                                                                    // Form author details
+
+	OpensAt           *time.Time `json:"opens_at,omitempty" db:"opens_at" example:"2023-01-02T09:00:00Z"`   // Form opens automatically at this time
+	ClosesAt          *time.Time `json:"closes_at,omitempty" db:"closes_at" example:"2023-01-09T17:00:00Z"` // Form closes automatically at this time
+	ScheduleAppliedAt *time.Time `json:"-" db:"schedule_applied_at"`                                        // Last time the schedule set the status
+
+	MaxResponses       *int `json:"max_responses,omitempty" db:"max_responses" example:"100"` // Form closes once it has this many responses
+	RemainingResponses *int `json:"remaining_responses,omitempty" example:"42"`               // Responses left before the limit, when there is one
+
+	DuplicatePolicy        DuplicatePolicy `json:"duplicate_policy" db:"duplicate_policy" example:"email"`                           // How duplicate submissions are prevented
+	DuplicateWindowSeconds *int            `json:"duplicate_window_seconds,omitempty" db:"duplicate_window_seconds" example:"86400"` // Period a respondent is blocked for, forever when omitted
+
+	AnonymityLevel AnonymityLevel `json:"anonymity_level" db:"anonymity_level" example:"anonymous"` // Which respondent identifiers are stored
+
+	EditWindowSeconds *int `json:"edit_window_seconds,omitempty" db:"edit_window_seconds" example:"3600"` // Respondents can edit their response for this long, edits are disabled when omitted
+}
+
+// FormSchedule represents the window in which a form accepts responses
+type FormSchedule struct {
+	OpensAt  *time.Time `json:"opens_at,omitempty" example:"2023-01-02T09:00:00Z"`  // Omit to leave the form open until it closes
+	ClosesAt *time.Time `json:"closes_at,omitempty" example:"2023-01-09T17:00:00Z"` // Omit to leave the form open indefinitely
 }
 
 // CreateFormRequest represents the request payload for creating a form
 // @Description Request payload for creating a new form
 type CreateFormRequest struct {
-	Title       string                  `json:"title" validate:"required,min=1,max=255" example:"Customer Feedback Form"` // Form title (required)
-	Description string                  `json:"description" example:"A form to collect customer feedback"`                // Form description
-	Slug        string                  `json:"slug" validate:"required,min=1,max=255" example:"customer-feedback-2023"`  // URL-friendly identifier (required)
-	Questions   []CreateQuestionRequest `json:"questions,omitempty"`                                                      // Optional list of questions to create with the form
+	Title       string                  `json:"title" binding:"required" validate:"required,min=1,max=255" example:"Customer Feedback Form"` // Form title (required)
+	Description string                  `json:"description" example:"A form to collect customer feedback"`                                   // Form description
+	Slug        string                  `json:"slug" binding:"required" validate:"required,min=1,max=255" example:"customer-feedback-2023"`  // URL-friendly identifier (required)
+	Questions   []CreateQuestionRequest `json:"questions,omitempty"`                                                                         // Optional questions to create with the form, positioned in the order listed
+	WorkspaceID *uuid.UUID              `json:"workspace_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440030"`                       // Optional workspace to create the form in; requires the editor role there
+
+	OpensAt  *time.Time `json:"opens_at,omitempty" example:"2023-01-02T09:00:00Z"`  // Optional time to open the form at
+	ClosesAt *time.Time `json:"closes_at,omitempty" example:"2023-01-09T17:00:00Z"` // Optional time to close the form at
+
+	MaxResponses *int `json:"max_responses,omitempty" example:"100"` // Optional response limit
+
+	DuplicatePolicy        DuplicatePolicy `json:"duplicate_policy,omitempty" example:"email"`         // Defaults to none
+	DuplicateWindowSeconds *int            `json:"duplicate_window_seconds,omitempty" example:"86400"` // Optional period a respondent is blocked for
+
+	AnonymityLevel AnonymityLevel `json:"anonymity_level,omitempty" example:"anonymous"` // Defaults to identified
+
+	EditWindowSeconds *int `json:"edit_window_seconds,omitempty" example:"3600"` // Optional period respondents can edit their response for
 }
 
 // UpdateFormRequest represents the request payload for updating a form
 type UpdateFormRequest struct {
-	Title       *string                 `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
-	Description *string                 `json:"description,omitempty"`
-	Status      *FormStatus             `json:"status,omitempty"`
-	Questions   []UpdateQuestionRequest `json:"questions,omitempty"`
+	Title        *string                 `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description  *string                 `json:"description,omitempty"`
+	Status       *FormStatus             `json:"status,omitempty"`
+	Questions    []UpdateQuestionRequest `json:"questions,omitempty"`     // Complete new question list when present; questions left out are deleted
+	Schedule     *FormSchedule           `json:"schedule,omitempty"`      // Replaces both schedule times when present
+	MaxResponses *int                    `json:"max_responses,omitempty"` // New response limit, 0 removes it
+
+	DuplicatePolicy        *DuplicatePolicy `json:"duplicate_policy,omitempty"`
+	DuplicateWindowSeconds *int             `json:"duplicate_window_seconds,omitempty"` // New window, 0 removes it
+
+	AnonymityLevel *AnonymityLevel `json:"anonymity_level,omitempty"` // Applies to responses submitted after the change
+
+	EditWindowSeconds *int `json:"edit_window_seconds,omitempty"` // New edit window, 0 disables edits
 }
 
 // FormResponse represents the response payload for form data
@@ -51,6 +154,7 @@ type FormResponse struct {
 	ID          uuid.UUID          `json:"id"`
 	Title       string             `json:"title"`
 	AuthorID    uuid.UUID          `json:"author_id"`
+	WorkspaceID *uuid.UUID         `json:"workspace_id,omitempty"`
 	Description string             `json:"description"`
 	Slug        string             `json:"slug"`
 	Status      FormStatus         `json:"status"`
@@ -58,6 +162,18 @@ type FormResponse struct {
 	UpdatedAt   time.Time          `json:"updated_at"`
 	Questions   []QuestionResponse `json:"questions,omitempty"`
 	Author      *UserResponse      `json:"author,omitempty"`
+	OpensAt     *time.Time         `json:"opens_at,omitempty"`
+	ClosesAt    *time.Time         `json:"closes_at,omitempty"`
+
+	MaxResponses       *int `json:"max_responses,omitempty"`
+	RemainingResponses *int `json:"remaining_responses,omitempty"`
+
+	DuplicatePolicy        DuplicatePolicy `json:"duplicate_policy"`
+	DuplicateWindowSeconds *int            `json:"duplicate_window_seconds,omitempty"`
+
+	AnonymityLevel AnonymityLevel `json:"anonymity_level"`
+
+	EditWindowSeconds *int `json:"edit_window_seconds,omitempty"`
 }
 
 // FormListResponse represents the response payload for form list
@@ -87,11 +203,24 @@ func (f *Form) ToResponse() *FormResponse {
 		ID:          f.ID,
 		Title:       f.Title,
 		AuthorID:    f.AuthorID,
+		WorkspaceID: f.WorkspaceID,
 		Description: f.Description,
 		Slug:        f.Slug,
 		Status:      f.Status,
 		CreatedAt:   f.CreatedAt,
 		UpdatedAt:   f.UpdatedAt,
+		OpensAt:     f.OpensAt,
+		ClosesAt:    f.ClosesAt,
+
+		MaxResponses:       f.MaxResponses,
+		RemainingResponses: f.RemainingResponses,
+
+		DuplicatePolicy:        f.DuplicatePolicy,
+		DuplicateWindowSeconds: f.DuplicateWindowSeconds,
+
+		AnonymityLevel: f.AnonymityLevel,
+
+		EditWindowSeconds: f.EditWindowSeconds,
 	}
 
 	// Convert questions
@@ -115,25 +244,80 @@ func (f *Form) FromCreateRequest(req *CreateFormRequest, authorID uuid.UUID) {
 	f.ID = uuid.New()
 	f.Title = req.Title
 	f.AuthorID = authorID
+	f.WorkspaceID = req.WorkspaceID
 	f.Description = req.Description
 	f.Slug = req.Slug
-	f.Status = FormStatusOpen
+	f.Status = FormStatusDraft
 	f.CreatedAt = time.Now()
 	f.UpdatedAt = time.Now()
+	f.SetSchedule(req.OpensAt, req.ClosesAt, f.CreatedAt)
+	f.SetMaxResponses(req.MaxResponses)
+	f.SetDuplicatePolicy(req.DuplicatePolicy, req.DuplicateWindowSeconds)
+	f.SetAnonymityLevel(req.AnonymityLevel)
+	f.SetEditWindow(req.EditWindowSeconds)
 }
 
-// UpdateFromRequest updates a Form from UpdateFormRequest
-func (f *Form) UpdateFromRequest(req *UpdateFormRequest) {
+// UpdateFromRequest applies the changes of an UpdateFormRequest and
+// validates the resulting settings. Status changes go through SetStatus.
+func (f *Form) UpdateFromRequest(req *UpdateFormRequest) error {
 	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return errors.New("Title cannot be empty")
+		}
 		f.Title = *req.Title
 	}
 	if req.Description != nil {
 		f.Description = *req.Description
 	}
+	f.UpdatedAt = time.Now()
+	if req.Schedule != nil {
+		f.SetSchedule(req.Schedule.OpensAt, req.Schedule.ClosesAt, f.UpdatedAt)
+		if err := f.ValidateSchedule(); err != nil {
+			return err
+		}
+	}
+	// A limit of 0 removes it
+	if req.MaxResponses != nil {
+		f.SetMaxResponses(req.MaxResponses)
+		if err := f.ValidateMaxResponses(); err != nil {
+			return err
+		}
+	}
+	// A window of 0 removes it
+	if req.DuplicatePolicy != nil || req.DuplicateWindowSeconds != nil {
+		policy := f.DuplicatePolicy
+		if req.DuplicatePolicy != nil {
+			policy = *req.DuplicatePolicy
+		}
+		window := f.DuplicateWindowSeconds
+		if req.DuplicateWindowSeconds != nil {
+			window = req.DuplicateWindowSeconds
+		}
+		f.SetDuplicatePolicy(policy, window)
+		if err := f.ValidateDuplicatePolicy(); err != nil {
+			return err
+		}
+	}
+	if req.AnonymityLevel != nil {
+		f.SetAnonymityLevel(*req.AnonymityLevel)
+	}
+	// The duplicate policy may need identifiers the level discards
+	if err := f.ValidateAnonymityLevel(); err != nil {
+		return err
+	}
+	// An edit window of 0 disables edits
+	if req.EditWindowSeconds != nil {
+		f.SetEditWindow(req.EditWindowSeconds)
+		if err := f.ValidateEditWindow(); err != nil {
+			return err
+		}
+	}
 	if req.Status != nil {
-		f.Status = *req.Status
+		if err := f.SetStatus(*req.Status); err != nil {
+			return err
+		}
 	}
-	f.UpdatedAt = time.Now()
+	return nil
 }
 
 // IsOpen returns true if the form is open for submissions
@@ -145,3 +329,257 @@ func (f *Form) IsOpen() bool {
 func (f *Form) IsClosed() bool {
 	return f.Status == FormStatusClosed
 }
+
+// IsDraft returns true if the form has not been published
+func (f *Form) IsDraft() bool {
+	return f.Status == FormStatusDraft
+}
+
+// SetStatus opens or closes a published form. Drafts are opened by
+// publishing them, and published forms cannot return to draft.
+func (f *Form) SetStatus(status FormStatus) error {
+	if !status.IsValid() {
+		return errors.New("status must be open or closed")
+	}
+	if status == FormStatusDraft {
+		return errors.New("A published form cannot return to draft")
+	}
+	if f.IsDraft() {
+		return errors.New("Publish the form before opening or closing it")
+	}
+	f.Status = status
+	return nil
+}
+
+// ValidatePublish checks the form's questions are ready for respondents: the
+// form needs at least one question and every question must be valid, which
+// requires multiple choice and dropdown questions to have choices
+func (f *Form) ValidatePublish(questions []*Question) error {
+	if len(questions) == 0 {
+		return errors.New("Form must have at least one question")
+	}
+	for _, question := range questions {
+		if err := question.Validate(); err != nil {
+			return fmt.Errorf("Question '%s': %w", question.QuestionText, err)
+		}
+	}
+	return nil
+}
+
+// Publish validates a draft and opens it, or applies its schedule when it
+// has one
+func (f *Form) Publish(questions []*Question, now time.Time) error {
+	if !f.IsDraft() {
+		return errors.New("Form is already published")
+	}
+	if err := f.ValidatePublish(questions); err != nil {
+		return err
+	}
+
+	f.Status = FormStatusOpen
+	f.UpdatedAt = now
+	f.SetSchedule(f.OpensAt, f.ClosesAt, now)
+	return nil
+}
+
+// ValidateSchedule checks the form closes after it opens
+func (f *Form) ValidateSchedule() error {
+	if f.OpensAt != nil && f.ClosesAt != nil && !f.ClosesAt.After(*f.OpensAt) {
+		return errors.New("closes_at must be after opens_at")
+	}
+	return nil
+}
+
+// SetSchedule replaces the schedule times and sets the status they imply at
+// now: closed before opens_at, open until closes_at and closed after it.
+// Without a schedule the status is left unchanged, and drafts stay drafts
+// until they are published.
+func (f *Form) SetSchedule(opensAt, closesAt *time.Time, now time.Time) {
+	f.OpensAt = opensAt
+	f.ClosesAt = closesAt
+	if (opensAt == nil && closesAt == nil) || f.IsDraft() {
+		return
+	}
+
+	switch {
+	case closesAt != nil && !now.Before(*closesAt):
+		f.Status = FormStatusClosed
+	case opensAt != nil && now.Before(*opensAt):
+		f.Status = FormStatusClosed
+	case opensAt != nil:
+		f.Status = FormStatusOpen
+	}
+	f.ScheduleAppliedAt = &now
+}
+
+// StatusAt returns the status of the form at now, taking into account schedule
+// times that have passed but were not applied to the stored status yet
+func (f *Form) StatusAt(now time.Time) FormStatus {
+	if f.IsDraft() {
+		return FormStatusDraft
+	}
+
+	pending := func(t *time.Time) bool {
+		return t != nil && !now.Before(*t) && (f.ScheduleAppliedAt == nil || f.ScheduleAppliedAt.Before(*t))
+	}
+
+	if pending(f.ClosesAt) {
+		return FormStatusClosed
+	}
+	if pending(f.OpensAt) {
+		return FormStatusOpen
+	}
+	return f.Status
+}
+
+// SetMaxResponses sets the response limit; nil or 0 removes it
+func (f *Form) SetMaxResponses(maxResponses *int) {
+	if maxResponses == nil || *maxResponses == 0 {
+		f.MaxResponses = nil
+		return
+	}
+	limit := *maxResponses
+	f.MaxResponses = &limit
+}
+
+// ValidateMaxResponses checks the response limit is positive
+func (f *Form) ValidateMaxResponses() error {
+	if f.MaxResponses != nil && *f.MaxResponses < 1 {
+		return errors.New("max_responses must be a positive number")
+	}
+	return nil
+}
+
+// SetRemainingResponses computes the responses left before the limit from
+// the number of responses received
+func (f *Form) SetRemainingResponses(count int) {
+	if f.MaxResponses == nil {
+		f.RemainingResponses = nil
+		return
+	}
+	remaining := *f.MaxResponses - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	f.RemainingResponses = &remaining
+}
+
+// SetDuplicatePolicy sets how duplicate submissions are prevented; an empty
+// policy means none and a nil or 0 window means forever
+func (f *Form) SetDuplicatePolicy(policy DuplicatePolicy, windowSeconds *int) {
+	if policy == "" {
+		policy = DuplicatePolicyNone
+	}
+	f.DuplicatePolicy = policy
+
+	if windowSeconds == nil || *windowSeconds == 0 {
+		f.DuplicateWindowSeconds = nil
+		return
+	}
+	window := *windowSeconds
+	f.DuplicateWindowSeconds = &window
+}
+
+// ValidateDuplicatePolicy checks the duplicate policy and its window
+func (f *Form) ValidateDuplicatePolicy() error {
+	if !f.DuplicatePolicy.IsValid() {
+		return errors.New("duplicate_policy must be one of none, email, ip or browser")
+	}
+	if f.DuplicateWindowSeconds != nil && *f.DuplicateWindowSeconds < 1 {
+		return errors.New("duplicate_window_seconds must be a positive number")
+	}
+	return nil
+}
+
+// SetAnonymityLevel sets which respondent identifiers the form keeps; an
+// empty level means identified
+func (f *Form) SetAnonymityLevel(level AnonymityLevel) {
+	if level == "" {
+		level = AnonymityIdentified
+	}
+	f.AnonymityLevel = level
+}
+
+// ValidateAnonymityLevel checks the anonymity level and that the duplicate
+// policy does not need identifiers the level discards
+func (f *Form) ValidateAnonymityLevel() error {
+	if !f.AnonymityLevel.IsValid() {
+		return errors.New("anonymity_level must be one of identified, pseudonymous or anonymous")
+	}
+	if f.AnonymityLevel == AnonymityAnonymous &&
+		(f.DuplicatePolicy == DuplicatePolicyEmail || f.DuplicatePolicy == DuplicatePolicyIP) {
+		return errors.New("anonymous forms only support the none and browser duplicate policies")
+	}
+	return nil
+}
+
+// SetEditWindow sets how long respondents can edit their response; nil or 0
+// disables edits
+func (f *Form) SetEditWindow(windowSeconds *int) {
+	if windowSeconds == nil || *windowSeconds == 0 {
+		f.EditWindowSeconds = nil
+		return
+	}
+	window := *windowSeconds
+	f.EditWindowSeconds = &window
+}
+
+// ValidateEditWindow checks the edit window is positive
+func (f *Form) ValidateEditWindow() error {
+	if f.EditWindowSeconds != nil && *f.EditWindowSeconds < 1 {
+		return errors.New("edit_window_seconds must be a positive number")
+	}
+	return nil
+}
+
+// AllowsEdits returns true if respondents can edit their responses
+func (f *Form) AllowsEdits() bool {
+	return f.EditWindowSeconds != nil
+}
+
+// EditDeadline returns when the edit window of a response submitted at
+// submittedAt expires
+func (f *Form) EditDeadline(submittedAt time.Time) time.Time {
+	if f.EditWindowSeconds == nil {
+		return submittedAt
+	}
+	return submittedAt.Add(time.Duration(*f.EditWindowSeconds) * time.Second)
+}
+
+// AnonymityDeclaration describes what the form stores about respondents
+func (f *Form) AnonymityDeclaration() AnonymityDeclaration {
+	declaration := AnonymityDeclaration{
+		Level:             f.AnonymityLevel,
+		HashedIdentifiers: []string{},
+	}
+
+	switch f.AnonymityLevel {
+	case AnonymityAnonymous:
+		declaration.Statement = "Your IP address, name and email are not stored."
+	case AnonymityPseudonymous:
+		declaration.HashedIdentifiers = append(declaration.HashedIdentifiers, "email", "ip")
+		declaration.Statement = "Your IP address, name and email are not stored. Only salted hashes of your email and IP address are kept to detect duplicate responses."
+	default:
+		declaration.Level = AnonymityIdentified
+		declaration.StoresName = true
+		declaration.StoresEmail = true
+		declaration.StoresIP = f.DuplicatePolicy != DuplicatePolicyIP
+		switch f.DuplicatePolicy {
+		case DuplicatePolicyEmail:
+			declaration.HashedIdentifiers = append(declaration.HashedIdentifiers, "email")
+		case DuplicatePolicyIP:
+			declaration.HashedIdentifiers = append(declaration.HashedIdentifiers, "ip")
+		}
+		if declaration.StoresIP {
+			declaration.Statement = "Your IP address and, when you provide them, your name and email are stored with your response."
+		} else {
+			declaration.Statement = "Your name and email, when you provide them, are stored with your response. Your IP address is only kept as a salted hash."
+		}
+	}
+
+	if f.DuplicatePolicy == DuplicatePolicyBrowser {
+		declaration.HashedIdentifiers = append(declaration.HashedIdentifiers, "browser")
+	}
+
+	return declaration
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestForm_ToResponse(t *testing.T) {
@@ -86,7 +87,7 @@ func TestForm_FromCreateRequest(t *testing.T) {
 	assert.Equal(t, authorID, form.AuthorID)
 	assert.Equal(t, req.Description, form.Description)
 	assert.Equal(t, req.Slug, form.Slug)
-	assert.Equal(t, FormStatusOpen, form.Status)
+	assert.Equal(t, FormStatusDraft, form.Status)
 	assert.WithinDuration(t, time.Now(), form.CreatedAt, time.Second)
 	assert.WithinDuration(t, time.Now(), form.UpdatedAt, time.Second)
 	assert.Empty(t, form.Questions) // Not set in this method
@@ -95,12 +96,13 @@ func TestForm_FromCreateRequest(t *testing.T) {
 func TestForm_UpdateFromRequest(t *testing.T) {
 	now := time.Now().Add(-time.Hour)
 	form := &Form{
-		ID:          uuid.New(),
-		Title:       "Old Title",
-		Description: "Old description",
-		Status:      FormStatusOpen,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:             uuid.New(),
+		Title:          "Old Title",
+		Description:    "Old description",
+		Status:         FormStatusOpen,
+		AnonymityLevel: AnonymityIdentified,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	newStatus := FormStatusClosed
@@ -110,7 +112,7 @@ func TestForm_UpdateFromRequest(t *testing.T) {
 		Status:      &newStatus,
 	}
 
-	form.UpdateFromRequest(req)
+	require.NoError(t, form.UpdateFromRequest(req))
 
 	assert.Equal(t, "New Title", form.Title)
 	assert.Equal(t, "New description", form.Description)
@@ -123,19 +125,20 @@ func TestForm_UpdateFromRequest(t *testing.T) {
 func TestForm_UpdateFromRequest_Partial(t *testing.T) {
 	now := time.Now().Add(-time.Hour)
 	form := &Form{
-		ID:          uuid.New(),
-		Title:       "Old Title",
-		Description: "Old description",
-		Status:      FormStatusOpen,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:             uuid.New(),
+		Title:          "Old Title",
+		Description:    "Old description",
+		Status:         FormStatusOpen,
+		AnonymityLevel: AnonymityIdentified,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	req := &UpdateFormRequest{
 		Description: stringPtr("New description"),
 	}
 
-	form.UpdateFromRequest(req)
+	require.NoError(t, form.UpdateFromRequest(req))
 
 	assert.Equal(t, "Old Title", form.Title) // Unchanged
 	assert.Equal(t, "New description", form.Description)
@@ -147,20 +150,62 @@ func TestForm_UpdateFromRequest_Partial(t *testing.T) {
 func TestForm_UpdateFromRequest_AllNil(t *testing.T) {
 	now := time.Now().Add(-time.Hour)
 	form := &Form{
-		ID:        uuid.New(),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             uuid.New(),
+		AnonymityLevel: AnonymityIdentified,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	req := &UpdateFormRequest{}
 
 	oldUpdatedAt := form.UpdatedAt
-	form.UpdateFromRequest(req)
+	require.NoError(t, form.UpdateFromRequest(req))
 
 	assert.Equal(t, now, form.CreatedAt)
 	assert.True(t, form.UpdatedAt.After(oldUpdatedAt))
 }
 
+func TestForm_UpdateFromRequest_Invalid(t *testing.T) {
+	negative := -1
+	unknownPolicy := DuplicatePolicy("sometimes")
+	anonymous := AnonymityAnonymous
+	emailPolicy := DuplicatePolicyEmail
+	draft := FormStatusDraft
+	open := FormStatusOpen
+	closed := FormStatusClosed
+
+	tests := []struct {
+		name    string
+		status  FormStatus
+		req     *UpdateFormRequest
+		wantErr string
+	}{
+		{"empty title", FormStatusOpen, &UpdateFormRequest{Title: stringPtr("  ")}, "Title cannot be empty"},
+		{"negative max responses", FormStatusOpen, &UpdateFormRequest{MaxResponses: &negative}, "max_responses must be a positive number"},
+		{"unknown duplicate policy", FormStatusOpen, &UpdateFormRequest{DuplicatePolicy: &unknownPolicy}, "duplicate_policy must be one of none, email, ip or browser"},
+		{"anonymous with email policy", FormStatusOpen, &UpdateFormRequest{AnonymityLevel: &anonymous, DuplicatePolicy: &emailPolicy}, "anonymous forms only support the none and browser duplicate policies"},
+		{"negative edit window", FormStatusOpen, &UpdateFormRequest{EditWindowSeconds: &negative}, "edit_window_seconds must be a positive number"},
+		{"back to draft", FormStatusOpen, &UpdateFormRequest{Status: &draft}, "A published form cannot return to draft"},
+		{"open a draft", FormStatusDraft, &UpdateFormRequest{Status: &open}, "Publish the form before opening or closing it"},
+		{"close", FormStatusOpen, &UpdateFormRequest{Status: &closed}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := &Form{ID: uuid.New(), Title: "Title", Status: tt.status, AnonymityLevel: AnonymityIdentified}
+
+			err := form.UpdateFromRequest(tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, *tt.req.Status, form.Status)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Equal(t, tt.status, form.Status)
+		})
+	}
+}
+
 func TestForm_IsOpen(t *testing.T) {
 	formOpen := &Form{Status: FormStatusOpen}
 	formClosed := &Form{Status: FormStatusClosed}
@@ -176,3 +221,259 @@ func TestForm_IsClosed(t *testing.T) {
 	assert.False(t, formOpen.IsClosed())
 	assert.True(t, formClosed.IsClosed())
 }
+
+func TestForm_SetSchedule(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		status   FormStatus
+		opensAt  *time.Time
+		closesAt *time.Time
+		want     FormStatus
+	}{
+		{"no schedule keeps status", FormStatusClosed, nil, nil, FormStatusClosed},
+		{"opens later", FormStatusOpen, &future, nil, FormStatusClosed},
+		{"opened already", FormStatusClosed, &past, &future, FormStatusOpen},
+		{"closed already", FormStatusOpen, &past, &past, FormStatusClosed},
+		{"closes later keeps status", FormStatusClosed, nil, &future, FormStatusClosed},
+		{"draft stays draft", FormStatusDraft, &past, &future, FormStatusDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := &Form{Status: tt.status}
+			form.SetSchedule(tt.opensAt, tt.closesAt, now)
+
+			assert.Equal(t, tt.want, form.Status)
+			assert.Equal(t, tt.opensAt, form.OpensAt)
+			assert.Equal(t, tt.closesAt, form.ClosesAt)
+		})
+	}
+}
+
+func TestForm_ValidateSchedule(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	assert.NoError(t, (&Form{OpensAt: &now}).ValidateSchedule())
+	assert.NoError(t, (&Form{OpensAt: &now, ClosesAt: &later}).ValidateSchedule())
+	assert.Error(t, (&Form{OpensAt: &later, ClosesAt: &now}).ValidateSchedule())
+	assert.Error(t, (&Form{OpensAt: &now, ClosesAt: &now}).ValidateSchedule())
+}
+
+func TestForm_StatusAt(t *testing.T) {
+	start := time.Now()
+	opensAt := start.Add(time.Hour)
+	closesAt := start.Add(2 * time.Hour)
+
+	form := &Form{Status: FormStatusOpen}
+	form.SetSchedule(&opensAt, &closesAt, start)
+
+	// Before the scheduler applies the times
+	assert.Equal(t, FormStatusClosed, form.StatusAt(start.Add(30*time.Minute)))
+	assert.Equal(t, FormStatusOpen, form.StatusAt(opensAt))
+	assert.Equal(t, FormStatusClosed, form.StatusAt(closesAt))
+
+	// A manual change after the scheduled time was applied sticks
+	applied := opensAt.Add(time.Minute)
+	form.ScheduleAppliedAt = &applied
+	form.Status = FormStatusClosed
+	assert.Equal(t, FormStatusClosed, form.StatusAt(opensAt.Add(30*time.Minute)))
+	assert.Equal(t, FormStatusClosed, form.StatusAt(closesAt.Add(time.Minute)))
+}
+
+func TestForm_MaxResponses(t *testing.T) {
+	limit := 10
+	form := &Form{}
+
+	form.SetMaxResponses(&limit)
+	assert.NoError(t, form.ValidateMaxResponses())
+	limit = 20 // The form keeps its own copy
+	assert.Equal(t, 10, *form.MaxResponses)
+
+	form.SetRemainingResponses(7)
+	assert.Equal(t, 3, *form.RemainingResponses)
+	form.SetRemainingResponses(12)
+	assert.Equal(t, 0, *form.RemainingResponses)
+
+	negative := -1
+	form.SetMaxResponses(&negative)
+	assert.Error(t, form.ValidateMaxResponses())
+
+	zero := 0
+	form.SetMaxResponses(&zero)
+	assert.Nil(t, form.MaxResponses)
+	form.SetRemainingResponses(7)
+	assert.Nil(t, form.RemainingResponses)
+}
+
+func TestForm_DuplicatePolicy(t *testing.T) {
+	form := &Form{}
+
+	form.SetDuplicatePolicy("", nil)
+	assert.Equal(t, DuplicatePolicyNone, form.DuplicatePolicy)
+	assert.NoError(t, form.ValidateDuplicatePolicy())
+
+	window := 3600
+	form.SetDuplicatePolicy(DuplicatePolicyIP, &window)
+	assert.NoError(t, form.ValidateDuplicatePolicy())
+	assert.Equal(t, 3600, *form.DuplicateWindowSeconds)
+
+	zero := 0
+	form.SetDuplicatePolicy(DuplicatePolicyIP, &zero)
+	assert.Nil(t, form.DuplicateWindowSeconds)
+
+	negative := -5
+	form.SetDuplicatePolicy(DuplicatePolicyEmail, &negative)
+	assert.Error(t, form.ValidateDuplicatePolicy())
+
+	form.SetDuplicatePolicy("phone", nil)
+	assert.Error(t, form.ValidateDuplicatePolicy())
+}
+
+func TestForm_AnonymityLevel(t *testing.T) {
+	form := &Form{}
+	form.SetDuplicatePolicy(DuplicatePolicyIP, nil)
+
+	form.SetAnonymityLevel("")
+	assert.Equal(t, AnonymityIdentified, form.AnonymityLevel)
+	assert.NoError(t, form.ValidateAnonymityLevel())
+
+	form.SetAnonymityLevel(AnonymityPseudonymous)
+	assert.NoError(t, form.ValidateAnonymityLevel())
+
+	// Anonymous forms discard the identifiers the policy compares
+	form.SetAnonymityLevel(AnonymityAnonymous)
+	assert.Error(t, form.ValidateAnonymityLevel())
+	form.SetDuplicatePolicy(DuplicatePolicyBrowser, nil)
+	assert.NoError(t, form.ValidateAnonymityLevel())
+
+	form.SetAnonymityLevel("secret")
+	assert.Error(t, form.ValidateAnonymityLevel())
+}
+
+func TestForm_AnonymityDeclaration(t *testing.T) {
+	form := &Form{}
+	form.SetDuplicatePolicy("", nil)
+	form.SetAnonymityLevel(AnonymityIdentified)
+
+	declaration := form.AnonymityDeclaration()
+	assert.True(t, declaration.StoresName)
+	assert.True(t, declaration.StoresEmail)
+	assert.True(t, declaration.StoresIP)
+	assert.Empty(t, declaration.HashedIdentifiers)
+
+	form.SetDuplicatePolicy(DuplicatePolicyIP, nil)
+	declaration = form.AnonymityDeclaration()
+	assert.False(t, declaration.StoresIP)
+	assert.Equal(t, []string{"ip"}, declaration.HashedIdentifiers)
+
+	form.SetAnonymityLevel(AnonymityPseudonymous)
+	declaration = form.AnonymityDeclaration()
+	assert.Equal(t, AnonymityPseudonymous, declaration.Level)
+	assert.False(t, declaration.StoresName || declaration.StoresEmail || declaration.StoresIP)
+	assert.Equal(t, []string{"email", "ip"}, declaration.HashedIdentifiers)
+
+	form.SetDuplicatePolicy(DuplicatePolicyBrowser, nil)
+	form.SetAnonymityLevel(AnonymityAnonymous)
+	declaration = form.AnonymityDeclaration()
+	assert.False(t, declaration.StoresName || declaration.StoresEmail || declaration.StoresIP)
+	assert.Equal(t, []string{"browser"}, declaration.HashedIdentifiers)
+	assert.NotEmpty(t, declaration.Statement)
+}
+
+func TestForm_EditWindow(t *testing.T) {
+	form := &Form{}
+	submittedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	form.SetEditWindow(nil)
+	assert.False(t, form.AllowsEdits())
+	assert.NoError(t, form.ValidateEditWindow())
+
+	window := 3600
+	form.SetEditWindow(&window)
+	assert.True(t, form.AllowsEdits())
+	assert.NoError(t, form.ValidateEditWindow())
+	assert.Equal(t, submittedAt.Add(time.Hour), form.EditDeadline(submittedAt))
+
+	negative := -1
+	form.SetEditWindow(&negative)
+	assert.Error(t, form.ValidateEditWindow())
+
+	zero := 0
+	form.SetEditWindow(&zero)
+	assert.False(t, form.AllowsEdits())
+}
+
+func TestForm_StatusAt_Draft(t *testing.T) {
+	now := time.Now()
+	opensAt := now.Add(-time.Hour)
+
+	form := &Form{Status: FormStatusDraft}
+	form.SetSchedule(&opensAt, nil, now)
+
+	assert.Nil(t, form.ScheduleAppliedAt)
+	assert.Equal(t, FormStatusDraft, form.StatusAt(now))
+}
+
+func TestForm_SetStatus(t *testing.T) {
+	form := &Form{Status: FormStatusOpen}
+	require.NoError(t, form.SetStatus(FormStatusClosed))
+	assert.Equal(t, FormStatusClosed, form.Status)
+
+	assert.EqualError(t, form.SetStatus(FormStatusDraft), "A published form cannot return to draft")
+	assert.EqualError(t, form.SetStatus("archived"), "status must be open or closed")
+
+	draft := &Form{Status: FormStatusDraft}
+	assert.EqualError(t, draft.SetStatus(FormStatusOpen), "Publish the form before opening or closing it")
+	assert.Equal(t, FormStatusDraft, draft.Status)
+}
+
+func TestForm_Publish(t *testing.T) {
+	now := time.Now()
+	name := &Question{ID: uuid.New(), QuestionText: "Name?", Type: QuestionTypeBasic}
+	color := &Question{ID: uuid.New(), QuestionText: "Favourite color?", Type: QuestionTypeMultipleChoice, Choices: JSONStringArray{"Red", "Blue"}}
+	noChoices := &Question{ID: uuid.New(), QuestionText: "Pick one", Type: QuestionTypeMultipleChoice}
+
+	tests := []struct {
+		name      string
+		questions []*Question
+		errMsg    string
+	}{
+		{"no questions", nil, "Form must have at least one question"},
+		{"choice question without choices", []*Question{name, noChoices}, "Question 'Pick one': Multiple choice questions must have at least 2 choices"},
+		{"ready", []*Question{name, color}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := &Form{Status: FormStatusDraft}
+			err := form.Publish(tt.questions, now)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				assert.Equal(t, FormStatusDraft, form.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, FormStatusOpen, form.Status)
+			assert.Equal(t, now, form.UpdatedAt)
+		})
+	}
+}
+
+func TestForm_Publish_AppliesSchedule(t *testing.T) {
+	now := time.Now()
+	opensAt := now.Add(time.Hour)
+	questions := []*Question{{ID: uuid.New(), QuestionText: "Name?", Type: QuestionTypeBasic}}
+
+	form := &Form{Status: FormStatusDraft}
+	form.SetSchedule(&opensAt, nil, now)
+	require.NoError(t, form.Publish(questions, now))
+	assert.Equal(t, FormStatusClosed, form.Status)
+	assert.Equal(t, FormStatusOpen, form.StatusAt(opensAt))
+
+	assert.EqualError(t, form.Publish(questions, now), "Form is already published")
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Page size limits of paginated listings
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// SortOrder is the direction of a sorted listing
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// IsValid returns true if the order is supported
+func (o SortOrder) IsValid() bool {
+	return o == SortAsc || o == SortDesc
+}
+
+// ResponseSort is the column submissions are sorted by
+type ResponseSort string
+
+const (
+	ResponseSortCreatedAt ResponseSort = "created_at"
+	ResponseSortUpdatedAt ResponseSort = "updated_at"
+)
+
+// IsValid returns true if the sort column is supported
+func (s ResponseSort) IsValid() bool {
+	return s == ResponseSortCreatedAt || s == ResponseSortUpdatedAt
+}
+
+// Cursor marks the last row of a page. Rows are ordered by a timestamp with
+// the ID breaking ties, so the next page starts strictly after both.
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Order SortOrder `json:"o"`
+	Value time.Time `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Encode returns the opaque string handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil || !cursor.Order.IsValid() {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// PageRequest selects one page of a sorted listing
+type PageRequest struct {
+	Limit int
+	Sort  string
+	Order SortOrder
+	After *Cursor // Start after this row; nil for the first page
+}
+
+// NewPageRequest validates the page size and cursor of a listing sorted by
+// sort in order. A limit of zero selects the default page size.
+func NewPageRequest(limit int, sort string, order SortOrder, cursor string) (PageRequest, error) {
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit < 1 || limit > MaxPageSize {
+		return PageRequest{}, fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+
+	page := PageRequest{Limit: limit, Sort: sort, Order: order}
+	if cursor == "" {
+		return page, nil
+	}
+
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return PageRequest{}, err
+	}
+	// A cursor only makes sense in the ordering it was issued for
+	if after.Sort != sort || after.Order != order {
+		return PageRequest{}, fmt.Errorf("cursor does not match the requested sort")
+	}
+	page.After = after
+
+	return page, nil
+}
+
+// NextCursor returns the cursor of the page after one ending at the given row
+func (p PageRequest) NextCursor(value time.Time, id uuid.UUID) *Cursor {
+	return &Cursor{Sort: p.Sort, Order: p.Order, Value: value, ID: id}
+}
+
+// PageInfo describes where a page sits in its listing
+// @Description Pagination metadata; pass next_cursor as the cursor query parameter to get the next page
+type PageInfo struct {
+	Limit      int     `json:"limit" example:"50"`
+	HasMore    bool    `json:"has_more" example:"true"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewPageInfo builds the metadata of a page; next is nil on the last page
+func NewPageInfo(limit int, next *Cursor) PageInfo {
+	info := PageInfo{Limit: limit}
+	if next != nil {
+		encoded := next.Encode()
+		info.HasMore = true
+		info.NextCursor = &encoded
+	}
+	return info
+}
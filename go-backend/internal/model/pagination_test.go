@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := Cursor{Sort: "created_at", Order: SortDesc, Value: time.Now().UTC().Truncate(time.Microsecond), ID: uuid.New()}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.Value.Equal(decoded.Value))
+
+	_, err = DecodeCursor("not a cursor")
+	assert.Error(t, err)
+}
+
+func TestNewPageRequest(t *testing.T) {
+	page, err := NewPageRequest(0, "created_at", SortDesc, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageSize, page.Limit)
+	assert.Nil(t, page.After)
+
+	_, err = NewPageRequest(MaxPageSize+1, "created_at", SortDesc, "")
+	assert.Error(t, err)
+
+	// Cursors are tied to the ordering they were issued for
+	cursor := page.NextCursor(time.Now(), uuid.New()).Encode()
+	page, err = NewPageRequest(10, "created_at", SortDesc, cursor)
+	require.NoError(t, err)
+	assert.NotNil(t, page.After)
+
+	_, err = NewPageRequest(10, "updated_at", SortDesc, cursor)
+	assert.Error(t, err)
+	_, err = NewPageRequest(10, "created_at", SortAsc, cursor)
+	assert.Error(t, err)
+}
+
+func TestNewPageInfo(t *testing.T) {
+	last := NewPageInfo(20, nil)
+	assert.False(t, last.HasMore)
+	assert.Nil(t, last.NextCursor)
+
+	next := NewPageInfo(20, &Cursor{Sort: "created_at", Order: SortDesc, ID: uuid.New()})
+	assert.True(t, next.HasMore)
+	assert.NotNil(t, next.NextCursor)
+}
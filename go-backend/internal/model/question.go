@@ -15,6 +15,22 @@ type QuestionType string
 const (
 	QuestionTypeBasic          QuestionType = "basic"
 	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeRating         QuestionType = "rating"
+	QuestionTypeNumber         QuestionType = "number"
+	QuestionTypeDate           QuestionType = "date"
+	QuestionTypeTime           QuestionType = "time"
+	QuestionTypeDateTime       QuestionType = "datetime"
+	QuestionTypeEmail          QuestionType = "email"
+	QuestionTypeURL            QuestionType = "url"
+	QuestionTypeDropdown       QuestionType = "dropdown"
+	QuestionTypeYesNo          QuestionType = "yes_no"
+	QuestionTypeLongText       QuestionType = "long_text"
+)
+
+// Default bounds for rating questions created without an explicit scale
+const (
+	DefaultScaleMin = 1
+	DefaultScaleMax = 5
 )
 
 // JSONStringArray represents a JSON array of strings stored in database
@@ -50,7 +66,7 @@ type Question struct {
 	FormID       uuid.UUID    `json:"form_id" db:"form_id" example:"550e8400-e29b-41d4-a716-446655440002"`                                    // Associated form ID
 	QuestionText string       `json:"question_text" db:"question_text" validate:"required" example:"How satisfied are you with our service?"` // Question text content
 	Answer       *string      `json:"answer,omitempty" db:"answer" example:"Very satisfied"`                                                  // Answer for basic questions
-	Type         QuestionType `json:"type" db:"type" example:"multiple_choice"`                                                               // Question type (basic/multiple_choice)
+	Type         QuestionType `json:"type" db:"type" example:"multiple_choice"`                                                               // Question type (basic, multiple_choice, rating, number, ...)
 	Position     int          `json:"position" db:"position" example:"1"`                                                                     // Question position in form
 	Required     bool         `json:"required" db:"required" example:"true"`                                                                  // Whether question is required
 	CreatedAt    time.Time    `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`                                              // Question creation timestamp
@@ -59,17 +75,42 @@ type Question struct {
 	Choices        JSONStringArray `json:"choices,omitempty" db:"choices" example:"[\"Very satisfied\", \"Satisfied\", \"Neutral\", \"Dissatisfied\", \"Very dissatisfied\"]"` // Available choices for multiple choice questions
 	SelectedChoice JSONStringArray `json:"selected_choice,omitempty" db:"selected_choice" example:"[\"Very satisfied\"]"`                                                      // Selected choices
 	AllowMultiple  bool            `json:"allow_multiple,omitempty" db:"allow_multiple" example:"false"`                                                                       // Whether multiple selections are allowed
+
+	// Rating specific fields
+	ScaleMin *int    `json:"scale_min,omitempty" db:"scale_min" example:"1"`                // Lowest value on the rating scale
+	ScaleMax *int    `json:"scale_max,omitempty" db:"scale_max" example:"5"`                // Highest value on the rating scale
+	MinLabel *string `json:"min_label,omitempty" db:"min_label" example:"Not likely"`       // Label shown at the low end of the scale
+	MaxLabel *string `json:"max_label,omitempty" db:"max_label" example:"Extremely likely"` // Label shown at the high end of the scale
+
+	// Number specific fields
+	MinValue    *float64 `json:"min_value,omitempty" db:"min_value" example:"0"`           // Smallest accepted number
+	MaxValue    *float64 `json:"max_value,omitempty" db:"max_value" example:"100"`         // Largest accepted number
+	IntegerOnly bool     `json:"integer_only,omitempty" db:"integer_only" example:"false"` // Whether only whole numbers are accepted
+
+	// Long text specific fields
+	MaxLength *int `json:"max_length,omitempty" db:"max_length" example:"2000"` // Maximum answer length in characters
+
+	DisplayLogic *DisplayLogic `json:"display_logic,omitempty" db:"display_logic"` // Skip logic deciding when the question is shown
 }
 
 // CreateQuestionRequest represents the request payload for creating a question
 // @Description Request payload for creating a new question
 type CreateQuestionRequest struct {
-	QuestionText  string       `json:"question_text" validate:"required" example:"How satisfied are you with our service?"` // Question text (required)
-	Type          QuestionType `json:"type" validate:"required" example:"multiple_choice"`                                  // Question type: basic or multiple_choice (required)
-	Position      int          `json:"position" example:"1"`                                                                // Position in form (optional, auto-assigned if not provided)
-	Required      bool         `json:"required" example:"true"`                                                             // Whether question is required
-	Choices       []string     `json:"choices,omitempty" example:"[\"Very satisfied\", \"Satisfied\", \"Neutral\"]"`        // Choices for multiple_choice questions
-	AllowMultiple bool         `json:"allow_multiple,omitempty" example:"false"`                                            // Allow multiple selections for multiple_choice questions
+	QuestionText  string        `json:"question_text" validate:"required" example:"How satisfied are you with our service?"` // Question text (required)
+	Type          QuestionType  `json:"type" validate:"required" example:"multiple_choice"`                                  // Question type, e.g. basic, multiple_choice, rating, number, date, dropdown (required)
+	Position      int           `json:"position" example:"1"`                                                                // Position in form (optional, auto-assigned if not provided)
+	Required      bool          `json:"required" example:"true"`                                                             // Whether question is required
+	Choices       []string      `json:"choices,omitempty" example:"[\"Very satisfied\", \"Satisfied\", \"Neutral\"]"`        // Choices for multiple_choice and dropdown questions
+	AllowMultiple bool          `json:"allow_multiple,omitempty" example:"false"`                                            // Allow multiple selections for multiple_choice questions
+	ScaleMin      *int          `json:"scale_min,omitempty" example:"1"`                                                     // Lowest rating value (defaults to 1)
+	ScaleMax      *int          `json:"scale_max,omitempty" example:"5"`                                                     // Highest rating value (defaults to 5)
+	MinLabel      *string       `json:"min_label,omitempty" example:"Not likely"`                                            // Label for the lowest rating
+	MaxLabel      *string       `json:"max_label,omitempty" example:"Extremely likely"`                                      // Label for the highest rating
+	MinValue      *float64      `json:"min_value,omitempty" example:"0"`                                                     // Lower bound for number questions
+	MaxValue      *float64      `json:"max_value,omitempty" example:"100"`                                                   // Upper bound for number questions
+	IntegerOnly   bool          `json:"integer_only,omitempty" example:"false"`                                              // Only accept whole numbers for number questions
+	MaxLength     *int          `json:"max_length,omitempty" example:"2000"`                                                 // Maximum length for long_text questions
+	DisplayLogic  *DisplayLogic `json:"display_logic,omitempty"`                                                             // Optional skip logic; the question is shown only when it matches
 }
 
 // UpdateQuestionRequest represents the request payload for updating a question
@@ -81,21 +122,39 @@ type UpdateQuestionRequest struct {
 	Required      *bool         `json:"required,omitempty"`
 	Choices       []string      `json:"choices,omitempty"`
 	AllowMultiple *bool         `json:"allow_multiple,omitempty"`
+	ScaleMin      *int          `json:"scale_min,omitempty"`
+	ScaleMax      *int          `json:"scale_max,omitempty"`
+	MinLabel      *string       `json:"min_label,omitempty"`
+	MaxLabel      *string       `json:"max_label,omitempty"`
+	MinValue      *float64      `json:"min_value,omitempty"`
+	MaxValue      *float64      `json:"max_value,omitempty"`
+	IntegerOnly   *bool         `json:"integer_only,omitempty"`
+	MaxLength     *int          `json:"max_length,omitempty"`
+	DisplayLogic  *DisplayLogic `json:"display_logic,omitempty"` // Send with no conditions to remove the logic
 }
 
 // QuestionResponse represents the response payload for question data
 type QuestionResponse struct {
-	ID             uuid.UUID    `json:"id"`
-	FormID         uuid.UUID    `json:"form_id"`
-	QuestionText   string       `json:"question_text"`
-	Answer         *string      `json:"answer,omitempty"`
-	Type           QuestionType `json:"type"`
-	Position       int          `json:"position"`
-	Required       bool         `json:"required"`
-	CreatedAt      time.Time    `json:"created_at"`
-	Choices        []string     `json:"choices,omitempty"`
-	SelectedChoice []string     `json:"selected_choice,omitempty"`
-	AllowMultiple  bool         `json:"allow_multiple,omitempty"`
+	ID             uuid.UUID     `json:"id"`
+	FormID         uuid.UUID     `json:"form_id"`
+	QuestionText   string        `json:"question_text"`
+	Answer         *string       `json:"answer,omitempty"`
+	Type           QuestionType  `json:"type"`
+	Position       int           `json:"position"`
+	Required       bool          `json:"required"`
+	CreatedAt      time.Time     `json:"created_at"`
+	Choices        []string      `json:"choices,omitempty"`
+	SelectedChoice []string      `json:"selected_choice,omitempty"`
+	AllowMultiple  bool          `json:"allow_multiple,omitempty"`
+	ScaleMin       *int          `json:"scale_min,omitempty"`
+	ScaleMax       *int          `json:"scale_max,omitempty"`
+	MinLabel       *string       `json:"min_label,omitempty"`
+	MaxLabel       *string       `json:"max_label,omitempty"`
+	MinValue       *float64      `json:"min_value,omitempty"`
+	MaxValue       *float64      `json:"max_value,omitempty"`
+	IntegerOnly    bool          `json:"integer_only,omitempty"`
+	MaxLength      *int          `json:"max_length,omitempty"`
+	DisplayLogic   *DisplayLogic `json:"display_logic,omitempty"`
 }
 
 // ToResponse converts a Question to QuestionResponse
@@ -120,6 +179,17 @@ func (q *Question) ToResponse() *QuestionResponse {
 	}
 	resp.AllowMultiple = q.AllowMultiple
 
+	// Copy type-specific settings
+	resp.ScaleMin = q.ScaleMin
+	resp.ScaleMax = q.ScaleMax
+	resp.MinLabel = q.MinLabel
+	resp.MaxLabel = q.MaxLabel
+	resp.MinValue = q.MinValue
+	resp.MaxValue = q.MaxValue
+	resp.IntegerOnly = q.IntegerOnly
+	resp.MaxLength = q.MaxLength
+	resp.DisplayLogic = q.DisplayLogic
+
 	return resp
 }
 
@@ -131,12 +201,28 @@ func (q *Question) FromCreateRequest(req *CreateQuestionRequest, formID uuid.UUI
 	q.Type = req.Type
 	q.Position = req.Position
 	q.Required = req.Required
+	q.DisplayLogic = req.DisplayLogic
 	q.CreatedAt = time.Now()
 
-	// Handle multiple choice specific fields
-	if req.Type == QuestionTypeMultipleChoice {
+	// Handle type specific fields
+	switch req.Type {
+	case QuestionTypeMultipleChoice:
 		q.Choices = JSONStringArray(req.Choices)
 		q.AllowMultiple = req.AllowMultiple
+	case QuestionTypeDropdown:
+		q.Choices = JSONStringArray(req.Choices)
+	case QuestionTypeRating:
+		q.ScaleMin = req.ScaleMin
+		q.ScaleMax = req.ScaleMax
+		q.applyScaleDefaults()
+		q.MinLabel = req.MinLabel
+		q.MaxLabel = req.MaxLabel
+	case QuestionTypeNumber:
+		q.MinValue = req.MinValue
+		q.MaxValue = req.MaxValue
+		q.IntegerOnly = req.IntegerOnly
+	case QuestionTypeLongText:
+		q.MaxLength = req.MaxLength
 	}
 }
 
@@ -160,6 +246,53 @@ func (q *Question) UpdateFromRequest(req *UpdateQuestionRequest) {
 	if req.AllowMultiple != nil {
 		q.AllowMultiple = *req.AllowMultiple
 	}
+	if req.ScaleMin != nil {
+		q.ScaleMin = req.ScaleMin
+	}
+	if req.ScaleMax != nil {
+		q.ScaleMax = req.ScaleMax
+	}
+	if req.MinLabel != nil {
+		q.MinLabel = req.MinLabel
+	}
+	if req.MaxLabel != nil {
+		q.MaxLabel = req.MaxLabel
+	}
+	if req.MinValue != nil {
+		q.MinValue = req.MinValue
+	}
+	if req.MaxValue != nil {
+		q.MaxValue = req.MaxValue
+	}
+	if req.IntegerOnly != nil {
+		q.IntegerOnly = *req.IntegerOnly
+	}
+	if req.MaxLength != nil {
+		q.MaxLength = req.MaxLength
+	}
+	if req.DisplayLogic != nil {
+		q.DisplayLogic = req.DisplayLogic
+		if len(req.DisplayLogic.Conditions) == 0 {
+			q.DisplayLogic = nil
+		}
+	}
+
+	// A question switched to a rating scale needs bounds
+	if q.Type == QuestionTypeRating {
+		q.applyScaleDefaults()
+	}
+}
+
+// applyScaleDefaults fills in the default rating bounds where none were given
+func (q *Question) applyScaleDefaults() {
+	if q.ScaleMin == nil {
+		scaleMin := DefaultScaleMin
+		q.ScaleMin = &scaleMin
+	}
+	if q.ScaleMax == nil {
+		scaleMax := DefaultScaleMax
+		q.ScaleMax = &scaleMax
+	}
 }
 
 // IsMultipleChoice returns true if the question is a multiple choice question
@@ -171,3 +304,8 @@ func (q *Question) IsMultipleChoice() bool {
 func (q *Question) IsBasic() bool {
 	return q.Type == QuestionTypeBasic
 }
+
+// HasChoices returns true if answers are picked from a list of choices
+func (q *Question) HasChoices() bool {
+	return q.Type == QuestionTypeMultipleChoice || q.Type == QuestionTypeDropdown
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuestionChanges describes how to turn the stored questions of a form into
+// the list sent with a form update
+type QuestionChanges struct {
+	Created   []*Question // Listed without an ID
+	Updated   []*Question // Listed with the ID of a stored question
+	Deleted   []uuid.UUID // Stored but not listed
+	Questions []*Question // Every question of the form after the changes, in order
+}
+
+// PlanQuestionChanges compares the stored questions of a form with the
+// complete list requested for it. Listed questions with an ID update the
+// stored question, those without one are created, and stored questions left
+// out are deleted. Questions are positioned in the order they are listed.
+func PlanQuestionChanges(formID uuid.UUID, stored []*Question, requested []UpdateQuestionRequest, now time.Time) (*QuestionChanges, error) {
+	storedByID := make(map[uuid.UUID]*Question, len(stored))
+	for _, question := range stored {
+		storedByID[question.ID] = question
+	}
+
+	changes := &QuestionChanges{Questions: make([]*Question, 0, len(requested))}
+	listed := make(map[uuid.UUID]bool, len(requested))
+	for i := range requested {
+		req := &requested[i]
+
+		var question *Question
+		if req.ID != nil {
+			existing, ok := storedByID[*req.ID]
+			if !ok {
+				return nil, fmt.Errorf("Question not found: %s", *req.ID)
+			}
+			if listed[*req.ID] {
+				return nil, fmt.Errorf("Question %s is listed more than once", *req.ID)
+			}
+			listed[*req.ID] = true
+
+			// Changes apply to a copy so the stored question is left intact
+			// when the plan is rejected
+			copied := *existing
+			question = &copied
+			question.UpdateFromRequest(req)
+			changes.Updated = append(changes.Updated, question)
+		} else {
+			question = &Question{ID: uuid.New(), FormID: formID, CreatedAt: now}
+			question.UpdateFromRequest(req)
+			changes.Created = append(changes.Created, question)
+		}
+		question.Position = i + 1
+
+		if strings.TrimSpace(question.QuestionText) == "" {
+			return nil, fmt.Errorf("Question at index %d: question_text is required", i)
+		}
+		if err := question.Validate(); err != nil {
+			return nil, fmt.Errorf("Question '%s': %w", question.QuestionText, err)
+		}
+		changes.Questions = append(changes.Questions, question)
+	}
+
+	for _, question := range stored {
+		if !listed[question.ID] {
+			changes.Deleted = append(changes.Deleted, question.ID)
+		}
+	}
+
+	if err := ValidateDisplayLogic(changes.Questions); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// NewQuestions creates the questions of a new form from the create request,
+// positioned in the order they are listed unless they set a position
+func NewQuestions(formID uuid.UUID, requested []CreateQuestionRequest, now time.Time) ([]*Question, error) {
+	questions := make([]*Question, len(requested))
+	for i := range requested {
+		question := &Question{}
+		question.FromCreateRequest(&requested[i], formID)
+		question.CreatedAt = now
+		if question.Position == 0 {
+			question.Position = i + 1
+		}
+
+		if strings.TrimSpace(question.QuestionText) == "" {
+			return nil, fmt.Errorf("Question at index %d: question_text is required", i)
+		}
+		if err := question.Validate(); err != nil {
+			return nil, fmt.Errorf("Question '%s': %w", question.QuestionText, err)
+		}
+		questions[i] = question
+	}
+
+	if err := ValidateDisplayLogic(questions); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func storedQuestions(formID uuid.UUID) []*Question {
+	return []*Question{
+		{ID: uuid.New(), FormID: formID, QuestionText: "Name?", Type: QuestionTypeBasic, Position: 1},
+		{ID: uuid.New(), FormID: formID, QuestionText: "Mood?", Type: QuestionTypeMultipleChoice, Position: 2, Choices: JSONStringArray{"Good", "Bad"}},
+		{ID: uuid.New(), FormID: formID, QuestionText: "Phone?", Type: QuestionTypeBasic, Position: 3},
+	}
+}
+
+func TestPlanQuestionChanges(t *testing.T) {
+	formID := uuid.New()
+	stored := storedQuestions(formID)
+	now := time.Now()
+
+	newText := "How are you feeling?"
+	emailText := "Email?"
+	emailType := QuestionTypeEmail
+	requested := []UpdateQuestionRequest{
+		{ID: &stored[1].ID, QuestionText: &newText},
+		{QuestionText: &emailText, Type: &emailType},
+		{ID: &stored[0].ID},
+	}
+
+	changes, err := PlanQuestionChanges(formID, stored, requested, now)
+	require.NoError(t, err)
+
+	require.Len(t, changes.Updated, 2)
+	assert.Equal(t, stored[1].ID, changes.Updated[0].ID)
+	assert.Equal(t, newText, changes.Updated[0].QuestionText)
+	assert.Equal(t, 1, changes.Updated[0].Position)
+	assert.Equal(t, 3, changes.Updated[1].Position)
+	// The stored questions are not modified
+	assert.Equal(t, "Mood?", stored[1].QuestionText)
+	assert.Equal(t, 2, stored[1].Position)
+
+	require.Len(t, changes.Created, 1)
+	assert.Equal(t, formID, changes.Created[0].FormID)
+	assert.Equal(t, QuestionTypeEmail, changes.Created[0].Type)
+	assert.Equal(t, 2, changes.Created[0].Position)
+	assert.Equal(t, now, changes.Created[0].CreatedAt)
+
+	assert.Equal(t, []uuid.UUID{stored[2].ID}, changes.Deleted)
+
+	require.Len(t, changes.Questions, 3)
+	assert.Equal(t, stored[1].ID, changes.Questions[0].ID)
+	assert.Equal(t, changes.Created[0].ID, changes.Questions[1].ID)
+	assert.Equal(t, stored[0].ID, changes.Questions[2].ID)
+}
+
+func TestPlanQuestionChanges_EmptyListDeletesEverything(t *testing.T) {
+	formID := uuid.New()
+	stored := storedQuestions(formID)
+
+	changes, err := PlanQuestionChanges(formID, stored, []UpdateQuestionRequest{}, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, changes.Deleted, 3)
+	assert.Empty(t, changes.Questions)
+}
+
+func TestPlanQuestionChanges_Rejects(t *testing.T) {
+	formID := uuid.New()
+	stored := storedQuestions(formID)
+	unknown := uuid.New()
+	basic := QuestionTypeBasic
+	text := "Anything else?"
+
+	// Removing the question a condition refers to breaks the logic
+	logic := &DisplayLogic{Match: LogicMatchAll, Conditions: []DisplayCondition{{QuestionID: stored[2].ID, Operator: ConditionIsAnswered}}}
+
+	tests := []struct {
+		name      string
+		requested []UpdateQuestionRequest
+		errMsg    string
+	}{
+		{"unknown question", []UpdateQuestionRequest{{ID: &unknown}}, "Question not found: " + unknown.String()},
+		{"listed twice", []UpdateQuestionRequest{{ID: &stored[0].ID}, {ID: &stored[0].ID}}, "listed more than once"},
+		{"new question without text", []UpdateQuestionRequest{{Type: &basic}}, "Question at index 0: question_text is required"},
+		{"new question without type", []UpdateQuestionRequest{{QuestionText: &text}}, "Invalid question type"},
+		{"choices removed", []UpdateQuestionRequest{{ID: &stored[1].ID, Choices: []string{"Only one"}}}, "Question 'Mood?': Multiple choice questions must have at least 2 choices"},
+		{"condition on deleted question", []UpdateQuestionRequest{{ID: &stored[0].ID, DisplayLogic: logic}}, "Condition refers to unknown question"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PlanQuestionChanges(formID, stored, tt.requested, time.Now())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestNewQuestions(t *testing.T) {
+	formID := uuid.New()
+	now := time.Now()
+
+	questions, err := NewQuestions(formID, []CreateQuestionRequest{
+		{QuestionText: "Name?", Type: QuestionTypeBasic},
+		{QuestionText: "Mood?", Type: QuestionTypeMultipleChoice, Choices: []string{"Good", "Bad"}, Position: 5},
+	}, now)
+	require.NoError(t, err)
+	require.Len(t, questions, 2)
+	assert.Equal(t, formID, questions[0].FormID)
+	assert.Equal(t, 1, questions[0].Position)
+	assert.Equal(t, 5, questions[1].Position)
+	assert.Equal(t, JSONStringArray{"Good", "Bad"}, questions[1].Choices)
+	assert.Equal(t, now, questions[1].CreatedAt)
+
+	_, err = NewQuestions(formID, []CreateQuestionRequest{{QuestionText: "Mood?", Type: QuestionTypeMultipleChoice}}, now)
+	assert.EqualError(t, err, "Question 'Mood?': Multiple choice questions must have at least 2 choices")
+}
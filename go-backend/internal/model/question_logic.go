@@ -0,0 +1,306 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ConditionOperator represents how a condition compares an answer
+type ConditionOperator string
+
+const (
+	ConditionEquals        ConditionOperator = "equals"
+	ConditionNotEquals     ConditionOperator = "not_equals"
+	ConditionContains      ConditionOperator = "contains"
+	ConditionIsOneOf       ConditionOperator = "is_one_of"
+	ConditionIsAnswered    ConditionOperator = "is_answered"
+	ConditionIsNotAnswered ConditionOperator = "is_not_answered"
+)
+
+// LogicMatch represents how several conditions are combined
+type LogicMatch string
+
+const (
+	LogicMatchAll LogicMatch = "all"
+	LogicMatchAny LogicMatch = "any"
+)
+
+// DisplayCondition compares the answer of another question
+// @Description A single skip-logic condition on another question's answer
+type DisplayCondition struct {
+	QuestionID uuid.UUID         `json:"question_id" example:"550e8400-e29b-41d4-a716-446655440003"` // Question whose answer is checked
+	Operator   ConditionOperator `json:"operator" example:"equals"`                                  // equals, not_equals, contains, is_one_of, is_answered or is_not_answered
+	Value      string            `json:"value,omitempty" example:"Yes"`                              // Value for equals, not_equals and contains
+	Values     []string          `json:"values,omitempty" example:"[\"Red\", \"Blue\"]"`             // Values for is_one_of
+}
+
+// DisplayLogic decides whether a question is shown based on earlier answers
+// @Description Skip logic: the question is shown only when its conditions match
+type DisplayLogic struct {
+	Match      LogicMatch         `json:"match" example:"all"` // Whether all or any of the conditions must match
+	Conditions []DisplayCondition `json:"conditions"`          // Conditions on other questions' answers
+}
+
+// Value implements the driver.Valuer interface
+func (l *DisplayLogic) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface
+func (l *DisplayLogic) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan DisplayLogic from non-[]byte")
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// validate checks the operators and values of the logic itself
+func (l *DisplayLogic) validate() error {
+	if l.Match != LogicMatchAll && l.Match != LogicMatchAny {
+		return errors.New("Display logic match must be 'all' or 'any'")
+	}
+	if len(l.Conditions) == 0 {
+		return errors.New("Display logic must have at least one condition")
+	}
+
+	for _, condition := range l.Conditions {
+		switch condition.Operator {
+		case ConditionEquals, ConditionNotEquals, ConditionContains:
+			if condition.Value == "" {
+				return fmt.Errorf("Condition '%s' requires a value", condition.Operator)
+			}
+		case ConditionIsOneOf:
+			if len(condition.Values) == 0 {
+				return fmt.Errorf("Condition '%s' requires values", condition.Operator)
+			}
+		case ConditionIsAnswered, ConditionIsNotAnswered:
+		default:
+			return fmt.Errorf("Invalid condition operator '%s'", condition.Operator)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDisplayLogic checks the display logic of every question in a form.
+// Conditions may only refer to other questions of the same form placed
+// before the question they control.
+func ValidateDisplayLogic(questions []*Question) error {
+	byID := make(map[uuid.UUID]*Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	for _, question := range questions {
+		if question.DisplayLogic == nil {
+			continue
+		}
+
+		if err := question.DisplayLogic.validate(); err != nil {
+			return err
+		}
+
+		for _, condition := range question.DisplayLogic.Conditions {
+			target, ok := byID[condition.QuestionID]
+			if !ok {
+				return fmt.Errorf("Condition refers to unknown question %s", condition.QuestionID)
+			}
+			if target.ID == question.ID || target.Position >= question.Position {
+				return fmt.Errorf("Conditions for question '%s' may only refer to earlier questions", question.QuestionText)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DependentQuestions returns the questions whose display logic refers to questionID
+func DependentQuestions(questions []*Question, questionID uuid.UUID) []*Question {
+	var dependents []*Question
+	for _, question := range questions {
+		if question.DisplayLogic == nil {
+			continue
+		}
+		for _, condition := range question.DisplayLogic.Conditions {
+			if condition.QuestionID == questionID {
+				dependents = append(dependents, question)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// VisibleQuestions evaluates display logic against the submitted answers and
+// returns which questions are shown. A hidden question counts as unanswered
+// for the conditions of questions that depend on it.
+func VisibleQuestions(questions []*Question, answers []CreateAnswerRequest) map[uuid.UUID]bool {
+	byID := make(map[uuid.UUID]*Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	answersByID := make(map[uuid.UUID]*CreateAnswerRequest, len(answers))
+	for i := range answers {
+		answersByID[answers[i].QuestionID] = &answers[i]
+	}
+
+	visible := make(map[uuid.UUID]bool, len(questions))
+	visiting := make(map[uuid.UUID]bool)
+
+	var isVisible func(id uuid.UUID) bool
+	isVisible = func(id uuid.UUID) bool {
+		if shown, done := visible[id]; done {
+			return shown
+		}
+
+		question, ok := byID[id]
+		if !ok || visiting[id] {
+			// Unknown questions and cycles never show anything
+			return false
+		}
+		if question.DisplayLogic == nil {
+			visible[id] = true
+			return true
+		}
+
+		visiting[id] = true
+		matched := 0
+		for _, condition := range question.DisplayLogic.Conditions {
+			var answer *CreateAnswerRequest
+			if isVisible(condition.QuestionID) {
+				answer = answersByID[condition.QuestionID]
+			}
+			if condition.matches(answer) {
+				matched++
+			}
+		}
+		delete(visiting, id)
+
+		shown := matched == len(question.DisplayLogic.Conditions)
+		if question.DisplayLogic.Match == LogicMatchAny {
+			shown = matched > 0
+		}
+
+		visible[id] = shown
+		return shown
+	}
+
+	for _, question := range questions {
+		isVisible(question.ID)
+	}
+
+	return visible
+}
+
+// ValidateAnswers checks the answers against the questions of the form and
+// returns those of questions shown to the respondent. Every shown question
+// marked Required must be answered, whether or not it was submitted; hidden
+// questions must not be answered and are never required.
+func ValidateAnswers(questions []*Question, answers []CreateAnswerRequest) ([]CreateAnswerRequest, error) {
+	byID := make(map[uuid.UUID]*Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	// Evaluate display logic to find which questions the respondent was shown
+	visible := VisibleQuestions(questions, answers)
+
+	answered := make(map[uuid.UUID]bool, len(answers))
+	shownAnswers := make([]CreateAnswerRequest, 0, len(answers))
+	for _, answer := range answers {
+		question, ok := byID[answer.QuestionID]
+		if !ok {
+			return nil, fmt.Errorf("Invalid question ID: %s", answer.QuestionID)
+		}
+
+		// Hidden questions must not be answered; empty entries for them are dropped
+		if !visible[question.ID] {
+			if answer.HasAnswer() {
+				return nil, fmt.Errorf("Question is hidden by display logic: %s", question.QuestionText)
+			}
+			continue
+		}
+
+		// Validate the answer against the question type
+		if err := question.ValidateAnswer(&answer); err != nil {
+			return nil, err
+		}
+
+		if answer.HasAnswer() {
+			answered[question.ID] = true
+		}
+		shownAnswers = append(shownAnswers, answer)
+	}
+
+	for _, question := range questions {
+		if question.Required && visible[question.ID] && !answered[question.ID] {
+			return nil, fmt.Errorf("Answer required for question: %s", question.QuestionText)
+		}
+	}
+
+	return shownAnswers, nil
+}
+
+// matches evaluates the condition against an answer, which is nil when the
+// referenced question was not answered or not shown
+func (c *DisplayCondition) matches(answer *CreateAnswerRequest) bool {
+	answered := answer != nil && answer.HasAnswer()
+
+	switch c.Operator {
+	case ConditionIsAnswered:
+		return answered
+	case ConditionIsNotAnswered:
+		return !answered
+	}
+
+	if !answered {
+		// Comparisons against a missing answer only hold for not_equals
+		return c.Operator == ConditionNotEquals
+	}
+
+	values := answer.SelectedChoices
+	if len(values) == 0 {
+		values = []string{*answer.Answer}
+	}
+
+	switch c.Operator {
+	case ConditionEquals:
+		return len(values) == 1 && values[0] == c.Value
+	case ConditionNotEquals:
+		return !(len(values) == 1 && values[0] == c.Value)
+	case ConditionContains:
+		for _, value := range values {
+			if len(answer.SelectedChoices) > 0 && value == c.Value {
+				return true
+			}
+			if len(answer.SelectedChoices) == 0 && strings.Contains(strings.ToLower(value), strings.ToLower(c.Value)) {
+				return true
+			}
+		}
+	case ConditionIsOneOf:
+		for _, value := range values {
+			for _, candidate := range c.Values {
+				if value == candidate {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayLogic_ValueAndScan(t *testing.T) {
+	logic := &DisplayLogic{
+		Match: LogicMatchAny,
+		Conditions: []DisplayCondition{
+			{QuestionID: uuid.New(), Operator: ConditionIsOneOf, Values: []string{"Red", "Blue"}},
+		},
+	}
+
+	value, err := logic.Value()
+	require.NoError(t, err)
+
+	var scanned DisplayLogic
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, *logic, scanned)
+
+	var empty *DisplayLogic
+	value, err = empty.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestValidateDisplayLogic(t *testing.T) {
+	first := &Question{ID: uuid.New(), QuestionText: "First", Position: 1}
+	logicOn := func(id uuid.UUID, operator ConditionOperator, value string) *DisplayLogic {
+		return &DisplayLogic{
+			Match:      LogicMatchAll,
+			Conditions: []DisplayCondition{{QuestionID: id, Operator: operator, Value: value}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		logic   *DisplayLogic
+		wantErr string
+	}{
+		{"valid", logicOn(first.ID, ConditionEquals, "yes"), ""},
+		{"no logic", nil, ""},
+		{"unknown question", logicOn(uuid.New(), ConditionEquals, "yes"), "unknown question"},
+		{"missing value", logicOn(first.ID, ConditionEquals, ""), "requires a value"},
+		{"invalid operator", logicOn(first.ID, "greater_than", "1"), "Invalid condition operator"},
+		{"invalid match", &DisplayLogic{Match: "some", Conditions: logicOn(first.ID, ConditionIsAnswered, "").Conditions}, "match"},
+		{"no conditions", &DisplayLogic{Match: LogicMatchAll}, "at least one condition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			second := &Question{ID: uuid.New(), QuestionText: "Second", Position: 2, DisplayLogic: tt.logic}
+			err := ValidateDisplayLogic([]*Question{first, second})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("later question", func(t *testing.T) {
+		second := &Question{ID: uuid.New(), QuestionText: "Second", Position: 2}
+		earlier := &Question{ID: uuid.New(), QuestionText: "Earlier", Position: 1, DisplayLogic: logicOn(second.ID, ConditionIsAnswered, "")}
+		err := ValidateDisplayLogic([]*Question{second, earlier})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "earlier questions")
+	})
+}
+
+func TestVisibleQuestions(t *testing.T) {
+	color := &Question{ID: uuid.New(), Type: QuestionTypeMultipleChoice, Position: 1}
+	why := &Question{ID: uuid.New(), Type: QuestionTypeBasic, Position: 2, DisplayLogic: &DisplayLogic{
+		Match:      LogicMatchAll,
+		Conditions: []DisplayCondition{{QuestionID: color.ID, Operator: ConditionEquals, Value: "Red"}},
+	}}
+	followUp := &Question{ID: uuid.New(), Type: QuestionTypeBasic, Position: 3, DisplayLogic: &DisplayLogic{
+		Match:      LogicMatchAll,
+		Conditions: []DisplayCondition{{QuestionID: why.ID, Operator: ConditionContains, Value: "price"}},
+	}}
+	fallback := &Question{ID: uuid.New(), Type: QuestionTypeBasic, Position: 4, DisplayLogic: &DisplayLogic{
+		Match: LogicMatchAny,
+		Conditions: []DisplayCondition{
+			{QuestionID: color.ID, Operator: ConditionIsOneOf, Values: []string{"Green", "Blue"}},
+			{QuestionID: color.ID, Operator: ConditionIsNotAnswered},
+		},
+	}}
+	questions := []*Question{color, why, followUp, fallback}
+
+	tests := []struct {
+		name    string
+		answers []CreateAnswerRequest
+		visible []*Question
+		hidden  []*Question
+	}{
+		{
+			name:    "nothing answered",
+			visible: []*Question{color, fallback},
+			hidden:  []*Question{why, followUp},
+		},
+		{
+			name: "chain shown",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Red"}},
+				{QuestionID: why.ID, Answer: stringPtr("The Price is right")},
+			},
+			visible: []*Question{color, why, followUp},
+			hidden:  []*Question{fallback},
+		},
+		{
+			name: "hidden answer does not count",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Blue"}},
+				{QuestionID: why.ID, Answer: stringPtr("price")},
+			},
+			visible: []*Question{color, fallback},
+			hidden:  []*Question{why, followUp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visible := VisibleQuestions(questions, tt.answers)
+			for _, question := range tt.visible {
+				assert.True(t, visible[question.ID], "question at position %d should be visible", question.Position)
+			}
+			for _, question := range tt.hidden {
+				assert.False(t, visible[question.ID], "question at position %d should be hidden", question.Position)
+			}
+		})
+	}
+}
+
+func TestValidateAnswers(t *testing.T) {
+	color := &Question{ID: uuid.New(), QuestionText: "Color", Type: QuestionTypeMultipleChoice, Choices: []string{"Red", "Blue"}, Required: true, Position: 1}
+	why := &Question{ID: uuid.New(), QuestionText: "Why red?", Type: QuestionTypeBasic, Required: true, Position: 2, DisplayLogic: &DisplayLogic{
+		Match:      LogicMatchAll,
+		Conditions: []DisplayCondition{{QuestionID: color.ID, Operator: ConditionEquals, Value: "Red"}},
+	}}
+	comment := &Question{ID: uuid.New(), QuestionText: "Comment", Type: QuestionTypeBasic, Position: 3}
+	questions := []*Question{color, why, comment}
+
+	tests := []struct {
+		name    string
+		answers []CreateAnswerRequest
+		shown   int
+		wantErr string
+	}{
+		{
+			name: "required hidden question omitted",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Blue"}},
+				{QuestionID: why.ID, Answer: stringPtr("")},
+			},
+			shown: 1,
+		},
+		{
+			name: "required visible question omitted",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Red"}},
+				{QuestionID: comment.ID, Answer: stringPtr("Nice")},
+			},
+			wantErr: "Answer required for question: Why red?",
+		},
+		{
+			name: "required visible question empty",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Red"}},
+				{QuestionID: why.ID, Answer: stringPtr("")},
+			},
+			wantErr: "Answer required for question: Why red?",
+		},
+		{
+			name:    "nothing submitted",
+			wantErr: "Answer required for question: Color",
+		},
+		{
+			name: "hidden question answered",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Blue"}},
+				{QuestionID: why.ID, Answer: stringPtr("Because")},
+			},
+			wantErr: "Question is hidden by display logic: Why red?",
+		},
+		{
+			name:    "unknown question",
+			answers: []CreateAnswerRequest{{QuestionID: uuid.New(), Answer: stringPtr("?")}},
+			wantErr: "Invalid question ID",
+		},
+		{
+			name: "all required answered",
+			answers: []CreateAnswerRequest{
+				{QuestionID: color.ID, SelectedChoices: []string{"Red"}},
+				{QuestionID: why.ID, Answer: stringPtr("Because")},
+			},
+			shown: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown, err := ValidateAnswers(questions, tt.answers)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, shown, tt.shown)
+		})
+	}
+}
+
+func TestDependentQuestions(t *testing.T) {
+	first := &Question{ID: uuid.New(), Position: 1}
+	second := &Question{ID: uuid.New(), Position: 2, DisplayLogic: &DisplayLogic{
+		Match:      LogicMatchAll,
+		Conditions: []DisplayCondition{{QuestionID: first.ID, Operator: ConditionIsAnswered}},
+	}}
+
+	assert.Equal(t, []*Question{second}, DependentQuestions([]*Question{first, second}, first.ID))
+	assert.Empty(t, DependentQuestions([]*Question{first, second}, second.ID))
+}
+
+func TestQuestion_UpdateFromRequest_ClearsDisplayLogic(t *testing.T) {
+	q := &Question{DisplayLogic: &DisplayLogic{Match: LogicMatchAll}}
+
+	var req UpdateQuestionRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"display_logic": {"match": "all", "conditions": []}}`), &req))
+	q.UpdateFromRequest(&req)
+
+	assert.Nil(t, q.DisplayLogic)
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Accepted answer formats for date and time questions
+const (
+	DateAnswerLayout     = "2006-01-02"
+	TimeAnswerLayout     = "15:04"
+	DateTimeAnswerLayout = time.RFC3339
+)
+
+// Accepted answers for yes/no questions
+const (
+	AnswerYes = "yes"
+	AnswerNo  = "no"
+)
+
+// Limits on question settings
+const (
+	MinChoices     = 2
+	MaxScaleRange  = 10
+	MaxLongTextLen = 100000
+)
+
+// IsValid returns true if the question type is known
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionTypeBasic, QuestionTypeMultipleChoice, QuestionTypeRating, QuestionTypeNumber,
+		QuestionTypeDate, QuestionTypeTime, QuestionTypeDateTime, QuestionTypeEmail,
+		QuestionTypeURL, QuestionTypeDropdown, QuestionTypeYesNo, QuestionTypeLongText:
+		return true
+	}
+	return false
+}
+
+// Validate checks that a question definition is consistent with its type
+func (q *Question) Validate() error {
+	if !q.Type.IsValid() {
+		return errors.New("Invalid question type")
+	}
+
+	switch q.Type {
+	case QuestionTypeMultipleChoice:
+		if len(q.Choices) < MinChoices {
+			return errors.New("Multiple choice questions must have at least 2 choices")
+		}
+	case QuestionTypeDropdown:
+		if len(q.Choices) < MinChoices {
+			return errors.New("Dropdown questions must have at least 2 choices")
+		}
+	case QuestionTypeRating:
+		if q.ScaleMin == nil || q.ScaleMax == nil {
+			return errors.New("Rating questions must have a scale")
+		}
+		if *q.ScaleMin >= *q.ScaleMax {
+			return errors.New("Rating scale minimum must be less than its maximum")
+		}
+		if *q.ScaleMax-*q.ScaleMin > MaxScaleRange {
+			return fmt.Errorf("Rating scale cannot span more than %d steps", MaxScaleRange)
+		}
+	case QuestionTypeNumber:
+		if q.MinValue != nil && q.MaxValue != nil && *q.MinValue > *q.MaxValue {
+			return errors.New("Number minimum must not be greater than its maximum")
+		}
+	case QuestionTypeLongText:
+		if q.MaxLength != nil && (*q.MaxLength < 1 || *q.MaxLength > MaxLongTextLen) {
+			return fmt.Errorf("Long text max length must be between 1 and %d", MaxLongTextLen)
+		}
+	}
+
+	return nil
+}
+
+// HasAnswer returns true if the answer request carries a non-empty answer
+func (a *CreateAnswerRequest) HasAnswer() bool {
+	return (a.Answer != nil && *a.Answer != "") || len(a.SelectedChoices) > 0
+}
+
+// ValidateAnswer checks that an answer is acceptable for the question's type.
+// Empty answers are accepted here; required checks are done separately.
+func (q *Question) ValidateAnswer(answer *CreateAnswerRequest) error {
+	if q.HasChoices() {
+		return q.validateChoices(answer.SelectedChoices)
+	}
+
+	if answer.Answer == nil || *answer.Answer == "" {
+		return nil
+	}
+	value := *answer.Answer
+
+	switch q.Type {
+	case QuestionTypeRating:
+		rating, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("Rating must be a whole number for question: %s", q.QuestionText)
+		}
+		if (q.ScaleMin != nil && rating < *q.ScaleMin) || (q.ScaleMax != nil && rating > *q.ScaleMax) {
+			return fmt.Errorf("Rating out of range for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeNumber:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return fmt.Errorf("Invalid number for question: %s", q.QuestionText)
+		}
+		if q.IntegerOnly && number != math.Trunc(number) {
+			return fmt.Errorf("Whole number required for question: %s", q.QuestionText)
+		}
+		if q.MinValue != nil && number < *q.MinValue {
+			return fmt.Errorf("Number below minimum for question: %s", q.QuestionText)
+		}
+		if q.MaxValue != nil && number > *q.MaxValue {
+			return fmt.Errorf("Number above maximum for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeDate:
+		if _, err := time.Parse(DateAnswerLayout, value); err != nil {
+			return fmt.Errorf("Date must be formatted as YYYY-MM-DD for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeTime:
+		if _, err := time.Parse(TimeAnswerLayout, value); err != nil {
+			return fmt.Errorf("Time must be formatted as HH:MM for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeDateTime:
+		if _, err := time.Parse(DateTimeAnswerLayout, value); err != nil {
+			return fmt.Errorf("Date and time must be in RFC 3339 format for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return fmt.Errorf("Invalid email address for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeURL:
+		parsed, err := url.ParseRequestURI(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("Invalid URL for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeYesNo:
+		if value != AnswerYes && value != AnswerNo {
+			return fmt.Errorf("Answer must be 'yes' or 'no' for question: %s", q.QuestionText)
+		}
+
+	case QuestionTypeLongText:
+		if q.MaxLength != nil && utf8.RuneCountInString(value) > *q.MaxLength {
+			return fmt.Errorf("Answer exceeds %d characters for question: %s", *q.MaxLength, q.QuestionText)
+		}
+	}
+
+	return nil
+}
+
+// validateChoices checks selected choices against the question's choices
+func (q *Question) validateChoices(selected []string) error {
+	if len(selected) == 0 {
+		return nil
+	}
+
+	// Dropdowns are always single select
+	if (!q.AllowMultiple || q.Type == QuestionTypeDropdown) && len(selected) > 1 {
+		return fmt.Errorf("Multiple selections not allowed for question: %s", q.QuestionText)
+	}
+
+	validChoices := make(map[string]bool)
+	for _, choice := range q.Choices {
+		validChoices[choice] = true
+	}
+
+	for _, selectedChoice := range selected {
+		if !validChoices[selectedChoice] {
+			return fmt.Errorf("Invalid choice '%s' for question: %s", selectedChoice, q.QuestionText)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func TestQuestion_FromCreateRequest_RatingDefaults(t *testing.T) {
+	req := &CreateQuestionRequest{
+		QuestionText: "How did we do?",
+		Type:         QuestionTypeRating,
+	}
+
+	q := &Question{}
+	q.FromCreateRequest(req, uuid.New())
+
+	assert.Equal(t, DefaultScaleMin, *q.ScaleMin)
+	assert.Equal(t, DefaultScaleMax, *q.ScaleMax)
+	assert.NoError(t, q.Validate())
+}
+
+func TestQuestion_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		wantErr  string
+	}{
+		{"basic", Question{Type: QuestionTypeBasic}, ""},
+		{"unknown type", Question{Type: "matrix"}, "Invalid question type"},
+		{"multiple choice too few choices", Question{Type: QuestionTypeMultipleChoice, Choices: JSONStringArray{"A"}}, "at least 2 choices"},
+		{"dropdown", Question{Type: QuestionTypeDropdown, Choices: JSONStringArray{"A", "B"}}, ""},
+		{"dropdown too few choices", Question{Type: QuestionTypeDropdown}, "at least 2 choices"},
+		{"rating inverted scale", Question{Type: QuestionTypeRating, ScaleMin: intPtr(5), ScaleMax: intPtr(1)}, "less than"},
+		{"rating scale too wide", Question{Type: QuestionTypeRating, ScaleMin: intPtr(0), ScaleMax: intPtr(20)}, "cannot span"},
+		{"number inverted bounds", Question{Type: QuestionTypeNumber, MinValue: float64Ptr(10), MaxValue: float64Ptr(1)}, "minimum"},
+		{"long text zero length", Question{Type: QuestionTypeLongText, MaxLength: intPtr(0)}, "max length"},
+		{"yes no", Question{Type: QuestionTypeYesNo}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.question.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestQuestion_ValidateAnswer(t *testing.T) {
+	rating := Question{QuestionText: "Rate", Type: QuestionTypeRating, ScaleMin: intPtr(1), ScaleMax: intPtr(5)}
+	number := Question{QuestionText: "Age", Type: QuestionTypeNumber, MinValue: float64Ptr(0), MaxValue: float64Ptr(120), IntegerOnly: true}
+	dropdown := Question{QuestionText: "Country", Type: QuestionTypeDropdown, Choices: JSONStringArray{"IN", "US"}}
+	multipleChoice := Question{QuestionText: "Colors", Type: QuestionTypeMultipleChoice, Choices: JSONStringArray{"Red", "Blue"}, AllowMultiple: true}
+	longText := Question{QuestionText: "Bio", Type: QuestionTypeLongText, MaxLength: intPtr(5)}
+
+	tests := []struct {
+		name     string
+		question Question
+		answer   CreateAnswerRequest
+		valid    bool
+	}{
+		{"rating in range", rating, CreateAnswerRequest{Answer: stringPtr("4")}, true},
+		{"rating out of range", rating, CreateAnswerRequest{Answer: stringPtr("6")}, false},
+		{"rating not a number", rating, CreateAnswerRequest{Answer: stringPtr("great")}, false},
+		{"number in range", number, CreateAnswerRequest{Answer: stringPtr("42")}, true},
+		{"number fractional", number, CreateAnswerRequest{Answer: stringPtr("42.5")}, false},
+		{"number below min", number, CreateAnswerRequest{Answer: stringPtr("-1")}, false},
+		{"number above max", number, CreateAnswerRequest{Answer: stringPtr("121")}, false},
+		{"date", Question{Type: QuestionTypeDate}, CreateAnswerRequest{Answer: stringPtr("2024-02-29")}, true},
+		{"date invalid", Question{Type: QuestionTypeDate}, CreateAnswerRequest{Answer: stringPtr("29/02/2024")}, false},
+		{"time", Question{Type: QuestionTypeTime}, CreateAnswerRequest{Answer: stringPtr("09:30")}, true},
+		{"time invalid", Question{Type: QuestionTypeTime}, CreateAnswerRequest{Answer: stringPtr("25:00")}, false},
+		{"datetime", Question{Type: QuestionTypeDateTime}, CreateAnswerRequest{Answer: stringPtr("2024-02-29T09:30:00Z")}, true},
+		{"email", Question{Type: QuestionTypeEmail}, CreateAnswerRequest{Answer: stringPtr("jane@example.com")}, true},
+		{"email invalid", Question{Type: QuestionTypeEmail}, CreateAnswerRequest{Answer: stringPtr("Jane <jane@example.com>")}, false},
+		{"url", Question{Type: QuestionTypeURL}, CreateAnswerRequest{Answer: stringPtr("https://example.com/page")}, true},
+		{"url without scheme", Question{Type: QuestionTypeURL}, CreateAnswerRequest{Answer: stringPtr("example.com")}, false},
+		{"yes", Question{Type: QuestionTypeYesNo}, CreateAnswerRequest{Answer: stringPtr("yes")}, true},
+		{"maybe", Question{Type: QuestionTypeYesNo}, CreateAnswerRequest{Answer: stringPtr("maybe")}, false},
+		{"long text within limit", longText, CreateAnswerRequest{Answer: stringPtr("héllo")}, true},
+		{"long text over limit", longText, CreateAnswerRequest{Answer: stringPtr("hello!")}, false},
+		{"dropdown single", dropdown, CreateAnswerRequest{SelectedChoices: []string{"IN"}}, true},
+		{"dropdown multiple", dropdown, CreateAnswerRequest{SelectedChoices: []string{"IN", "US"}}, false},
+		{"dropdown invalid choice", dropdown, CreateAnswerRequest{SelectedChoices: []string{"FR"}}, false},
+		{"multiple choice multiple", multipleChoice, CreateAnswerRequest{SelectedChoices: []string{"Red", "Blue"}}, true},
+		{"empty answer", number, CreateAnswerRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.question.ValidateAnswer(&tt.answer)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a single-use token exchanged for a new access token and
+// the next refresh token of its family
+type RefreshToken struct {
+	ID        uuid.UUID  `db:"id"`
+	FamilyID  uuid.UUID  `db:"family_id"` // Shared by every token descending from one sign-in
+	UserID    uuid.UUID  `db:"user_id"`
+	TokenHash string     `db:"token_hash"` // SHA-256 of the token; the token itself is never stored
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"` // Set once the token has been exchanged
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// NewRefreshToken creates a refresh token in a family that expires after ttl
+func NewRefreshToken(userID, familyID uuid.UUID, tokenHash string, now time.Time, ttl time.Duration) *RefreshToken {
+	return &RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired returns true if the token can no longer be exchanged
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// RefreshTokenRequest represents the request payload carrying a refresh token
+// @Description Request payload carrying a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"kq3Zb0n9yX5t2mRvW8cJ1aPfLhU4sE7dGiO6NlBrT0Q"`
+}
+
+// TokenResponse carries a signed access token and the refresh token to
+// exchange for the next one
+// @Description Access token and refresh token
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string    `json:"token_type" example:"Bearer"`
+	ExpiresIn    int       `json:"expires_in" example:"900"` // Seconds until the access token expires
+	ExpiresAt    time.Time `json:"expires_at" example:"2023-01-01T10:15:00Z"`
+	RefreshToken string    `json:"refresh_token" example:"kq3Zb0n9yX5t2mRvW8cJ1aPfLhU4sE7dGiO6NlBrT0Q"` // Single use
+}
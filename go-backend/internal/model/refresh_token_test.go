@@ -0,0 +1,23 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	now := time.Now()
+	userID, familyID := uuid.New(), uuid.New()
+
+	token := NewRefreshToken(userID, familyID, "hash", now, time.Hour)
+
+	assert.NotEqual(t, uuid.Nil, token.ID)
+	assert.Equal(t, userID, token.UserID)
+	assert.Equal(t, familyID, token.FamilyID)
+	assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+	assert.False(t, token.IsExpired(now))
+	assert.True(t, token.IsExpired(now.Add(time.Hour)))
+}
@@ -1,9 +1,14 @@
 package model
 
 import (
+	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/respondent"
 )
 
 // FilledForm represents a form submission
@@ -17,6 +22,98 @@ type FilledForm struct {
 	UpdatedAt time.Time            `json:"updated_at" db:"updated_at"`
 	Answers   []FilledFormQuestion `json:"answers,omitempty"`
 	Form      *Form                `json:"form,omitempty"`
+
+	FormVersion *int `json:"form_version,omitempty" db:"form_version"` // Version of the form answered; unset for responses from before versioning
+
+	StartedAt        *time.Time `json:"started_at,omitempty" db:"started_at"`                 // When the respondent opened the form
+	CompletionTimeMs *int64     `json:"completion_time_ms,omitempty" db:"completion_time_ms"` // Time from opening the form to submitting it
+	FlaggedFast      bool       `json:"flagged_fast" db:"flagged_fast"`                       // Submitted suspiciously fast
+
+	// Salted hashes identifying the respondent for the form's duplicate policy
+	EmailHash   *string `json:"-" db:"email_hash"`
+	IPHash      *string `json:"-" db:"ip_hash"`
+	BrowserHash *string `json:"-" db:"browser_hash"`
+
+	EditTokenHash *string `json:"-" db:"edit_token_hash"` // Hash of the token the respondent edits the response with
+
+	// Triage state set by the form owner
+	Spam     bool            `json:"spam" db:"spam"`
+	Reviewed bool            `json:"reviewed" db:"reviewed"`
+	Starred  bool            `json:"starred" db:"starred"`
+	Tags     JSONStringArray `json:"tags" db:"tags"`
+
+	Highlights []AnswerHighlight `json:"highlights,omitempty"` // Matching answer snippets, set by searches
+}
+
+// Limits on response tags
+const (
+	MaxResponseTags      = 20
+	MaxResponseTagLength = 50
+)
+
+// ResponseFilter selects responses by their triage state, submission time and
+// answers; nil and empty fields match anything
+type ResponseFilter struct {
+	Spam     *bool
+	Reviewed *bool
+	Starred  *bool
+	Tag      string
+
+	SubmittedFrom *time.Time // Inclusive
+	SubmittedTo   *time.Time // Exclusive
+	Answers       []AnswerFilter
+	Search        string // Full-text search over the text answers
+}
+
+// AnswerFilter matches responses by their answer to a question. Equals
+// matches a text answer exactly or one of the selected choices; Contains
+// matches a text answer containing the value, ignoring case.
+type AnswerFilter struct {
+	QuestionID uuid.UUID
+	Equals     string
+	Contains   string
+}
+
+// MaxAnswerFilters bounds the answer filters of a single listing
+const MaxAnswerFilters = 10
+
+// MaxSearchLength bounds the length of a full-text search query
+const MaxSearchLength = 200
+
+// Markers around the matched terms of a highlight snippet
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// AnswerHighlight is a snippet of an answer matching a search
+type AnswerHighlight struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Snippet    string    `json:"snippet" example:"the <mark>delivery</mark> was late"` // HTML escaped, matched terms wrapped in mark tags
+}
+
+// NewAnswerHighlight builds a highlight from a snippet whose matched terms are
+// wrapped in HighlightStart and HighlightStop. The answer text is escaped so
+// the snippet can be rendered as HTML, keeping only the markers as tags.
+func NewAnswerHighlight(questionID uuid.UUID, snippet string) AnswerHighlight {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, html.EscapeString(HighlightStart), HighlightStart)
+	escaped = strings.ReplaceAll(escaped, html.EscapeString(HighlightStop), HighlightStop)
+	return AnswerHighlight{QuestionID: questionID, Snippet: escaped}
+}
+
+// TriageResponseRequest represents the request payload for triaging a response
+// @Description Request payload for marking a response; omitted fields are left unchanged
+type TriageResponseRequest struct {
+	Spam     *bool    `json:"spam,omitempty" example:"true"`
+	Reviewed *bool    `json:"reviewed,omitempty" example:"true"`
+	Starred  *bool    `json:"starred,omitempty" example:"false"`
+	Tags     []string `json:"tags,omitempty" example:"[\"follow-up\"]"` // Replaces the tags, an empty list removes them
+}
+
+// BulkDeleteResponsesRequest represents the request payload for deleting several responses
+type BulkDeleteResponsesRequest struct {
+	ResponseIDs []uuid.UUID `json:"response_ids" binding:"required" example:"[\"550e8400-e29b-41d4-a716-446655440004\"]"`
 }
 
 // FilledFormQuestion represents an answer to a specific question
@@ -37,6 +134,8 @@ type CreateResponseRequest struct {
 	Name    *string               `json:"name,omitempty" example:"John Doe"`                                          // Optional respondent name
 	Email   *string               `json:"email,omitempty" validate:"omitempty,email" example:"john.doe@example.com"`  // Optional respondent email
 	Answers []CreateAnswerRequest `json:"answers" validate:"required,dive"`                                           // List of answers to form questions (required)
+
+	StartToken *string `json:"start_token,omitempty" example:"Vb0jR...Q.9x2k..."` // Token returned with the form, used to measure completion time
 }
 
 // CreateAnswerRequest represents the request payload for creating an answer
@@ -70,6 +169,14 @@ type ResponseListResponse struct {
 	Email     *string   `json:"email,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	CompletionTimeMs *int64 `json:"completion_time_ms,omitempty"`
+	FlaggedFast      bool   `json:"flagged_fast"`
+
+	Spam     bool     `json:"spam"`
+	Reviewed bool     `json:"reviewed"`
+	Starred  bool     `json:"starred"`
+	Tags     []string `json:"tags"`
 }
 
 // ResponseDetailResponse represents the response payload for detailed response view
@@ -83,6 +190,19 @@ type ResponseDetailResponse struct {
 	UpdatedAt time.Time        `json:"updated_at"`
 	Answers   []AnswerResponse `json:"answers"`
 	Form      *FormResponse    `json:"form,omitempty"`
+
+	FormVersion *int `json:"form_version,omitempty"`
+
+	StartedAt        *time.Time `json:"started_at,omitempty"`
+	CompletionTimeMs *int64     `json:"completion_time_ms,omitempty"`
+	FlaggedFast      bool       `json:"flagged_fast"`
+
+	Spam     bool     `json:"spam"`
+	Reviewed bool     `json:"reviewed"`
+	Starred  bool     `json:"starred"`
+	Tags     []string `json:"tags"`
+
+	Highlights []AnswerHighlight `json:"highlights,omitempty"`
 }
 
 // AnswerResponse represents the response payload for answer data
@@ -101,6 +221,10 @@ type FormSubmissionStats struct {
 	TotalSubmissions int        `json:"total_submissions"`
 	UniqueEmails     int        `json:"unique_emails"`
 	AverageTime      float64    `json:"average_completion_time_minutes"`
+	MedianTime       float64    `json:"median_completion_time_minutes"`
+	P90Time          float64    `json:"p90_completion_time_minutes"`
+	TimedSubmissions int        `json:"timed_submissions"` // Submissions with a completion time
+	FastSubmissions  int        `json:"fast_submissions"`  // Submissions flagged as suspiciously fast
 	LastSubmission   *time.Time `json:"last_submission,omitempty"`
 }
 
@@ -113,6 +237,14 @@ func (f *FilledForm) ToResponseList() *ResponseListResponse {
 		Email:     f.Email,
 		CreatedAt: f.CreatedAt,
 		UpdatedAt: f.UpdatedAt,
+
+		CompletionTimeMs: f.CompletionTimeMs,
+		FlaggedFast:      f.FlaggedFast,
+
+		Spam:     f.Spam,
+		Reviewed: f.Reviewed,
+		Starred:  f.Starred,
+		Tags:     f.tagList(),
 	}
 }
 
@@ -126,6 +258,19 @@ func (f *FilledForm) ToDetailResponse() *ResponseDetailResponse {
 		UserIP:    f.UserIP,
 		CreatedAt: f.CreatedAt,
 		UpdatedAt: f.UpdatedAt,
+
+		FormVersion: f.FormVersion,
+
+		StartedAt:        f.StartedAt,
+		CompletionTimeMs: f.CompletionTimeMs,
+		FlaggedFast:      f.FlaggedFast,
+
+		Spam:     f.Spam,
+		Reviewed: f.Reviewed,
+		Starred:  f.Starred,
+		Tags:     f.tagList(),
+
+		Highlights: f.Highlights,
 	}
 
 	// Convert answers
@@ -144,8 +289,13 @@ func (f *FilledForm) ToDetailResponse() *ResponseDetailResponse {
 	return resp
 }
 
-// FromCreateRequest creates a FilledForm from CreateResponseRequest
-func (f *FilledForm) FromCreateRequest(req *CreateResponseRequest, userIP string) {
+// IdentityHasher returns a salted hash of a respondent identifier
+type IdentityHasher func(kind respondent.Kind, value string) string
+
+// FromCreateRequest creates a FilledForm from CreateResponseRequest, keeping
+// only the respondent identifiers the anonymity level allows. Pseudonymous
+// responses keep the email and IP address as hashes from hash.
+func (f *FilledForm) FromCreateRequest(req *CreateResponseRequest, userIP string, anonymity AnonymityLevel, hash IdentityHasher) {
 	f.ID = uuid.New()
 	f.FormID = req.FormID
 	f.Name = req.Name
@@ -155,6 +305,109 @@ func (f *FilledForm) FromCreateRequest(req *CreateResponseRequest, userIP string
 	}
 	f.CreatedAt = time.Now()
 	f.UpdatedAt = time.Now()
+
+	if anonymity == AnonymityPseudonymous {
+		if f.Email != nil && strings.TrimSpace(*f.Email) != "" {
+			emailHash := hash(respondent.KindEmail, *f.Email)
+			f.EmailHash = &emailHash
+		}
+		if f.UserIP != nil {
+			ipHash := hash(respondent.KindIP, *f.UserIP)
+			f.IPHash = &ipHash
+		}
+	}
+	f.ApplyAnonymity(anonymity)
+}
+
+// ApplyAnonymity drops the IP address, name and email unless the anonymity
+// level is identified
+func (f *FilledForm) ApplyAnonymity(anonymity AnonymityLevel) {
+	if anonymity == AnonymityIdentified || anonymity == "" {
+		return
+	}
+	f.Name = nil
+	f.Email = nil
+	f.UserIP = nil
+}
+
+// ApplyTriage updates the triage state from the request; tags are trimmed
+// and deduplicated
+func (f *FilledForm) ApplyTriage(req *TriageResponseRequest) error {
+	if req.Tags != nil {
+		tags, err := NormalizeTags(req.Tags)
+		if err != nil {
+			return err
+		}
+		f.Tags = tags
+	}
+	if req.Spam != nil {
+		f.Spam = *req.Spam
+	}
+	if req.Reviewed != nil {
+		f.Reviewed = *req.Reviewed
+	}
+	if req.Starred != nil {
+		f.Starred = *req.Starred
+	}
+	f.UpdatedAt = time.Now()
+	return nil
+}
+
+// NormalizeTags trims tags, drops empty and repeated ones and checks the limits
+func NormalizeTags(tags []string) (JSONStringArray, error) {
+	normalized := make(JSONStringArray, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxResponseTagLength {
+			return nil, fmt.Errorf("tags must be at most %d characters", MaxResponseTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxResponseTags {
+		return nil, fmt.Errorf("a response can have at most %d tags", MaxResponseTags)
+	}
+	return normalized, nil
+}
+
+// tagList returns the tags, never nil so they encode as a JSON array
+func (f *FilledForm) tagList() []string {
+	if f.Tags == nil {
+		return []string{}
+	}
+	return f.Tags
+}
+
+// SetCompletionTime records when the respondent started and flags the
+// submission when it took less than fastThreshold
+func (f *FilledForm) SetCompletionTime(startedAt time.Time, fastThreshold time.Duration) {
+	duration := f.CreatedAt.Sub(startedAt)
+	if duration < 0 {
+		duration = 0
+	}
+
+	completionTimeMs := duration.Milliseconds()
+	f.StartedAt = &startedAt
+	f.CompletionTimeMs = &completionTimeMs
+	f.FlaggedFast = duration < fastThreshold
+}
+
+// AnswerRequests returns the edited answers in the form they are validated
+// in; an edit replaces every answer, so omitted answers count as removed
+func (r *UpdateResponseRequest) AnswerRequests() []CreateAnswerRequest {
+	answers := make([]CreateAnswerRequest, len(r.Answers))
+	for i, answer := range r.Answers {
+		answers[i] = CreateAnswerRequest{
+			QuestionID:      answer.QuestionID,
+			Answer:          answer.Answer,
+			SelectedChoices: answer.SelectedChoices,
+		}
+	}
+	return answers
 }
 
 // UpdateFromRequest updates a FilledForm from UpdateResponseRequest
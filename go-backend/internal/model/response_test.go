@@ -6,8 +6,14 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ayan-sh03/anoq/internal/respondent"
 )
 
+func testHasher(kind respondent.Kind, value string) string {
+	return string(kind) + ":" + value
+}
+
 func TestFilledForm_ToDetailResponse(t *testing.T) {
 	now := time.Now()
 	form := &Form{ID: uuid.New(), Title: "Survey"}
@@ -63,7 +69,7 @@ func TestFilledForm_FromCreateRequest(t *testing.T) {
 	userIP := "192.168.1.1"
 
 	ff := &FilledForm{}
-	ff.FromCreateRequest(req, userIP)
+	ff.FromCreateRequest(req, userIP, AnonymityIdentified, testHasher)
 
 	assert.NotEqual(t, uuid.Nil, ff.ID)
 	assert.Equal(t, req.FormID, ff.FormID)
@@ -74,6 +80,54 @@ func TestFilledForm_FromCreateRequest(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), ff.UpdatedAt, time.Second)
 }
 
+func TestFilledForm_FromCreateRequest_Pseudonymous(t *testing.T) {
+	req := &CreateResponseRequest{
+		FormID: uuid.New(),
+		Name:   stringPtr("Jane Doe"),
+		Email:  stringPtr("jane@example.com"),
+	}
+
+	ff := &FilledForm{}
+	ff.FromCreateRequest(req, "192.168.1.1", AnonymityPseudonymous, testHasher)
+
+	assert.Nil(t, ff.Name)
+	assert.Nil(t, ff.Email)
+	assert.Nil(t, ff.UserIP)
+	assert.Equal(t, "email:jane@example.com", *ff.EmailHash)
+	assert.Equal(t, "ip:192.168.1.1", *ff.IPHash)
+}
+
+func TestFilledForm_FromCreateRequest_Anonymous(t *testing.T) {
+	req := &CreateResponseRequest{
+		FormID: uuid.New(),
+		Name:   stringPtr("Jane Doe"),
+		Email:  stringPtr("jane@example.com"),
+	}
+
+	ff := &FilledForm{}
+	ff.FromCreateRequest(req, "192.168.1.1", AnonymityAnonymous, testHasher)
+
+	assert.Nil(t, ff.Name)
+	assert.Nil(t, ff.Email)
+	assert.Nil(t, ff.UserIP)
+	assert.Nil(t, ff.EmailHash)
+	assert.Nil(t, ff.IPHash)
+}
+
+func TestFilledForm_SetCompletionTime(t *testing.T) {
+	ff := &FilledForm{CreatedAt: time.Now()}
+	ff.SetCompletionTime(ff.CreatedAt.Add(-90*time.Second), 5*time.Second)
+
+	assert.Equal(t, int64(90000), *ff.CompletionTimeMs)
+	assert.False(t, ff.FlaggedFast)
+
+	fast := &FilledForm{CreatedAt: time.Now()}
+	fast.SetCompletionTime(fast.CreatedAt.Add(-2*time.Second), 5*time.Second)
+
+	assert.Equal(t, int64(2000), *fast.CompletionTimeMs)
+	assert.True(t, fast.FlaggedFast)
+}
+
 func TestFilledFormQuestion_ToResponse(t *testing.T) {
 	now := time.Now()
 	question := &Question{ID: uuid.New(), QuestionText: "Q1"}
@@ -112,3 +166,66 @@ func TestFilledFormQuestion_FromCreateRequest(t *testing.T) {
 	assert.EqualValues(t, req.SelectedChoices, ffq.SelectedChoices)
 	assert.WithinDuration(t, time.Now(), ffq.CreatedAt, time.Second)
 }
+
+func TestFilledForm_ApplyTriage(t *testing.T) {
+	spam, reviewed := true, true
+	ff := &FilledForm{Starred: true}
+
+	err := ff.ApplyTriage(&TriageResponseRequest{
+		Spam:     &spam,
+		Reviewed: &reviewed,
+		Tags:     []string{" follow-up ", "follow-up", "", "billing"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, ff.Spam)
+	assert.True(t, ff.Reviewed)
+	assert.True(t, ff.Starred)
+	assert.Equal(t, JSONStringArray{"follow-up", "billing"}, ff.Tags)
+
+	// Omitted tags are kept, an empty list removes them
+	assert.NoError(t, ff.ApplyTriage(&TriageResponseRequest{}))
+	assert.Len(t, ff.Tags, 2)
+	assert.NoError(t, ff.ApplyTriage(&TriageResponseRequest{Tags: []string{}}))
+	assert.Empty(t, ff.Tags)
+	assert.Equal(t, []string{}, ff.ToResponseList().Tags)
+}
+
+func TestNormalizeTags_Limits(t *testing.T) {
+	tooLong := make([]byte, MaxResponseTagLength+1)
+	for i := range tooLong {
+		tooLong[i] = 'a'
+	}
+	_, err := NormalizeTags([]string{string(tooLong)})
+	assert.Error(t, err)
+
+	tooMany := make([]string, MaxResponseTags+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()[:8]
+	}
+	_, err = NormalizeTags(tooMany)
+	assert.Error(t, err)
+}
+
+func TestUpdateResponseRequest_RemovingRequiredAnswer(t *testing.T) {
+	name := &Question{ID: uuid.New(), QuestionText: "Name", Type: QuestionTypeBasic, Required: true, Position: 1}
+	comment := &Question{ID: uuid.New(), QuestionText: "Comment", Type: QuestionTypeBasic, Position: 2}
+	questions := []*Question{name, comment}
+
+	edit := &UpdateResponseRequest{Answers: []UpdateAnswerRequest{
+		{QuestionID: name.ID, Answer: stringPtr("Ada")},
+		{QuestionID: comment.ID, Answer: stringPtr("First")},
+	}}
+	shown, err := ValidateAnswers(questions, edit.AnswerRequests())
+	assert.NoError(t, err)
+	assert.Len(t, shown, 2)
+
+	// The edit drops the required answer
+	edit.Answers = edit.Answers[1:]
+	_, err = ValidateAnswers(questions, edit.AnswerRequests())
+	assert.EqualError(t, err, "Answer required for question: Name")
+
+	// Or removes every answer
+	edit.Answers = nil
+	_, err = ValidateAnswers(questions, edit.AnswerRequests())
+	assert.EqualError(t, err, "Answer required for question: Name")
+}
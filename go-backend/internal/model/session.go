@@ -0,0 +1,95 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength bounds the user agent stored with a session
+const maxUserAgentLength = 512
+
+// SessionClient describes the client signing in
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// NewUserSession creates a session for a user that expires after ttl unless
+// it is used
+func NewUserSession(userID uuid.UUID, token string, client SessionClient, now time.Time, ttl time.Duration) *UserSession {
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return &UserSession{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Token:      token,
+		UserAgent:  userAgent,
+		IPAddress:  client.IPAddress,
+		Device:     DescribeDevice(userAgent),
+		ExpiresAt:  now.Add(ttl),
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// userAgentBrowsers maps user agent tokens to browser names. Order matters:
+// Edge and Opera also claim to be Chrome, and Chrome claims to be Safari.
+var userAgentBrowsers = []struct {
+	token, name string
+}{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// userAgentSystems maps user agent tokens to operating system names. iOS and
+// Android user agents also mention Mac OS X and Linux.
+var userAgentSystems = []struct {
+	token, name string
+}{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeDevice describes the browser and operating system of a user agent,
+// such as "Chrome on macOS"
+func DescribeDevice(userAgent string) string {
+	var browser, system string
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, s := range userAgentSystems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	return "Unknown device"
+}
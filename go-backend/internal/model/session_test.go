@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserSession(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	client := SessionClient{
+		UserAgent: strings.Repeat("a", maxUserAgentLength+10),
+		IPAddress: "203.0.113.7",
+	}
+
+	session := NewUserSession(userID, "token", client, now, time.Hour)
+
+	assert.Equal(t, userID, session.UserID)
+	assert.Equal(t, "token", session.Token)
+	assert.Len(t, session.UserAgent, maxUserAgentLength)
+	assert.Equal(t, "203.0.113.7", session.IPAddress)
+	assert.Equal(t, now.Add(time.Hour), session.ExpiresAt)
+	assert.Equal(t, now, session.LastUsedAt)
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", "Safari on iOS"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"curl/8.4.0", "curl"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, DescribeDevice(tt.userAgent))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateVisibility represents who can see and use a template
+type TemplateVisibility string
+
+const (
+	TemplateVisibilityPrivate TemplateVisibility = "private" // Only the author
+	TemplateVisibilityPublic  TemplateVisibility = "public"  // Every user
+)
+
+// IsValid returns true if the visibility is known
+func (v TemplateVisibility) IsValid() bool {
+	return v == TemplateVisibilityPrivate || v == TemplateVisibilityPublic
+}
+
+// TemplateScope selects which templates are listed
+type TemplateScope string
+
+const (
+	TemplateScopeAll    TemplateScope = "all"    // The user's templates and every public one
+	TemplateScopeMine   TemplateScope = "mine"   // The user's templates
+	TemplateScopePublic TemplateScope = "public" // Every public template
+)
+
+// IsValid returns true if the scope is known
+func (s TemplateScope) IsValid() bool {
+	switch s {
+	case TemplateScopeAll, TemplateScopeMine, TemplateScopePublic:
+		return true
+	}
+	return false
+}
+
+// MaxTemplateNameLength bounds the length of template names
+const MaxTemplateNameLength = 255
+
+// FormTemplate is a reusable form structure forms can be created from
+// @Description Template holding the settings and questions of a form
+type FormTemplate struct {
+	ID           uuid.UUID          `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440020"`
+	AuthorID     uuid.UUID          `json:"author_id" db:"author_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name         string             `json:"name" db:"name" example:"Weekly check-in"`
+	Description  string             `json:"description" db:"description" example:"Five questions for the Friday team check-in"`
+	Visibility   TemplateVisibility `json:"visibility" db:"visibility" example:"private"`
+	Definition   FormDefinition     `json:"definition" db:"definition"`
+	SourceFormID *uuid.UUID         `json:"source_form_id,omitempty" db:"source_form_id"` // Form the template was published from, while it exists
+	CreatedAt    time.Time          `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// CreateTemplateRequest represents the request payload for publishing a form as a template
+// @Description Request payload for publishing a form as a template
+type CreateTemplateRequest struct {
+	Name        string             `json:"name" binding:"required" example:"Weekly check-in"`     // Template name (required)
+	Description string             `json:"description" example:"Five questions for the check-in"` // Template description
+	Visibility  TemplateVisibility `json:"visibility,omitempty" example:"private"`                // private (default) or public
+}
+
+// UpdateTemplateRequest represents the request payload for updating a template
+type UpdateTemplateRequest struct {
+	Name        *string             `json:"name,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Visibility  *TemplateVisibility `json:"visibility,omitempty"`
+}
+
+// CopyFormRequest represents the request payload for creating a form from
+// another form or a template
+// @Description Title and slug of the new form
+type CopyFormRequest struct {
+	Title string `json:"title,omitempty" example:"Weekly check-in, week 12"` // Defaults to the original title
+	Slug  string `json:"slug,omitempty" example:"weekly-check-in-12"`        // URL-friendly identifier of the new form
+}
+
+// TemplateResponse represents the response payload for template data. The
+// definition is only included when a single template is requested.
+type TemplateResponse struct {
+	ID            uuid.UUID          `json:"id"`
+	AuthorID      uuid.UUID          `json:"author_id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	Visibility    TemplateVisibility `json:"visibility"`
+	QuestionCount int                `json:"question_count"`
+	SourceFormID  *uuid.UUID         `json:"source_form_id,omitempty"`
+	Definition    *FormDefinition    `json:"definition,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// FromCreateRequest creates a FormTemplate of a form from CreateTemplateRequest
+func (t *FormTemplate) FromCreateRequest(req *CreateTemplateRequest, authorID uuid.UUID, sourceFormID uuid.UUID, definition *FormDefinition) {
+	t.ID = uuid.New()
+	t.AuthorID = authorID
+	t.Name = strings.TrimSpace(req.Name)
+	t.Description = req.Description
+	t.Visibility = req.Visibility
+	if t.Visibility == "" {
+		t.Visibility = TemplateVisibilityPrivate
+	}
+	t.Definition = *definition
+	t.SourceFormID = &sourceFormID
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = t.CreatedAt
+}
+
+// UpdateFromRequest updates a FormTemplate from UpdateTemplateRequest
+func (t *FormTemplate) UpdateFromRequest(req *UpdateTemplateRequest) {
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+	if req.Visibility != nil {
+		t.Visibility = *req.Visibility
+	}
+	t.UpdatedAt = time.Now()
+}
+
+// Validate checks the name and visibility of the template
+func (t *FormTemplate) Validate() error {
+	if t.Name == "" {
+		return errors.New("Template name is required")
+	}
+	if len(t.Name) > MaxTemplateNameLength {
+		return fmt.Errorf("Template name must be at most %d characters", MaxTemplateNameLength)
+	}
+	if !t.Visibility.IsValid() {
+		return errors.New("Template visibility must be 'private' or 'public'")
+	}
+	return nil
+}
+
+// VisibleTo returns true if the user can see and use the template
+func (t *FormTemplate) VisibleTo(userID uuid.UUID) bool {
+	return t.AuthorID == userID || t.Visibility == TemplateVisibilityPublic
+}
+
+// ToResponse converts a FormTemplate to TemplateResponse
+func (t *FormTemplate) ToResponse(withDefinition bool) *TemplateResponse {
+	resp := &TemplateResponse{
+		ID:            t.ID,
+		AuthorID:      t.AuthorID,
+		Name:          t.Name,
+		Description:   t.Description,
+		Visibility:    t.Visibility,
+		QuestionCount: len(t.Definition.Questions),
+		SourceFormID:  t.SourceFormID,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+	if withDefinition {
+		definition := t.Definition
+		resp.Definition = &definition
+	}
+	return resp
+}
@@ -22,12 +22,17 @@ type User struct {
 // UserSession represents a user session
 // @Description User authentication session
 type UserSession struct {
-	ID        uuid.UUID `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440001"`           // Session unique identifier
-	UserID    uuid.UUID `json:"user_id" db:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Associated user ID
-	Token     string    `json:"token" db:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`  // Session token
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at" example:"2023-01-02T10:00:00Z"`           // Session expiration time
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`           // Session creation time
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`           // Last update time
+	ID         uuid.UUID `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440001"`                            // Session unique identifier
+	UserID     uuid.UUID `json:"user_id" db:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`                  // Associated user ID
+	Token      string    `json:"-" db:"token"`                                                                         // Session token; only returned when signing in
+	UserAgent  string    `json:"user_agent" db:"user_agent" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)"` // User agent of the client that signed in
+	IPAddress  string    `json:"ip_address" db:"ip_address" example:"203.0.113.7"`                                     // IP address the client signed in from
+	Device     string    `json:"device" db:"device" example:"Chrome on macOS"`                                         // Browser and OS described from the user agent
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at" example:"2023-01-02T10:00:00Z"`                            // Session expiration time, pushed back on use
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at" example:"2023-01-01T12:00:00Z"`                        // Last time the session authenticated a request
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`                            // Session creation time
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`                            // Last update time
+	Current    bool      `json:"current" db:"-" example:"true"`                                                        // Whether the request was authenticated by this session
 }
 
 // CreateUserRequest represents the request payload for creating a user
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FormVersion is an immutable snapshot of a form's settings and questions.
+// A version is recorded when a response is submitted after the form changed,
+// and responses keep the version they answered.
+// @Description Snapshot of a form as respondents saw it
+type FormVersion struct {
+	FormID     uuid.UUID      `json:"form_id" db:"form_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	Version    int            `json:"version" db:"version" example:"3"`
+	Definition FormDefinition `json:"definition" db:"definition"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// FormVersionSummary describes a version in version listings
+type FormVersionSummary struct {
+	Version       int       `json:"version" db:"version" example:"3"`
+	QuestionCount int       `json:"question_count" db:"question_count" example:"5"`
+	ResponseCount int       `json:"response_count" db:"response_count" example:"42"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// SameDefinition returns true if the definitions would render the same form
+func SameDefinition(a, b *FormDefinition) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// FieldChange is a field whose value differs between two versions
+type FieldChange struct {
+	Field string      `json:"field" example:"question_text"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// QuestionChange lists the changed fields of a question present in both versions
+type QuestionChange struct {
+	QuestionID uuid.UUID     `json:"question_id"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// VersionDiff describes how a form changed from one version to another.
+// Questions are matched by ID.
+// @Description Changes between two versions of a form
+type VersionDiff struct {
+	From     int                  `json:"from" example:"2"`
+	To       int                  `json:"to" example:"3"`
+	Settings []FieldChange        `json:"settings"`
+	Added    []QuestionDefinition `json:"added"`
+	Removed  []QuestionDefinition `json:"removed"`
+	Changed  []QuestionChange     `json:"changed"`
+}
+
+// DiffVersions compares two versions of a form
+func DiffVersions(from, to *FormVersion) *VersionDiff {
+	diff := &VersionDiff{
+		From:     from.Version,
+		To:       to.Version,
+		Settings: []FieldChange{},
+		Added:    []QuestionDefinition{},
+		Removed:  []QuestionDefinition{},
+		Changed:  []QuestionChange{},
+	}
+
+	// Settings are compared without the questions
+	fromSettings, toSettings := from.Definition, to.Definition
+	fromSettings.Questions, toSettings.Questions = nil, nil
+	diff.Settings = append(diff.Settings, diffFields(fromSettings, toSettings, "questions")...)
+
+	oldQuestions := make(map[uuid.UUID]QuestionDefinition, len(from.Definition.Questions))
+	for _, question := range from.Definition.Questions {
+		oldQuestions[question.ID] = question
+	}
+
+	for _, question := range to.Definition.Questions {
+		old, exists := oldQuestions[question.ID]
+		if !exists {
+			diff.Added = append(diff.Added, question)
+			continue
+		}
+		delete(oldQuestions, question.ID)
+
+		if changes := diffFields(old, question); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, QuestionChange{QuestionID: question.ID, Changes: changes})
+		}
+	}
+
+	// Removed questions keep their order in the older version
+	for _, question := range from.Definition.Questions {
+		if _, removed := oldQuestions[question.ID]; removed {
+			diff.Removed = append(diff.Removed, question)
+		}
+	}
+
+	return diff
+}
+
+// diffFields compares the JSON fields of two values, ignoring the skipped
+// fields, and returns the changes sorted by field name
+func diffFields(from, to interface{}, skip ...string) []FieldChange {
+	fromFields, toFields := jsonFields(from), jsonFields(to)
+	for _, field := range skip {
+		delete(fromFields, field)
+		delete(toFields, field)
+	}
+
+	names := make(map[string]bool, len(fromFields)+len(toFields))
+	for name := range fromFields {
+		names[name] = true
+	}
+	for name := range toFields {
+		names[name] = true
+	}
+
+	var changes []FieldChange
+	for name := range names {
+		if !reflect.DeepEqual(fromFields[name], toFields[name]) {
+			changes = append(changes, FieldChange{Field: name, From: fromFields[name], To: toFields[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes
+}
+
+// jsonFields returns the fields of a value as encoded in JSON
+func jsonFields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if data, err := json.Marshal(value); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// ToQuestion returns the question a version showed, identified by its ID in
+// the form
+func (d *QuestionDefinition) ToQuestion(formID uuid.UUID) *Question {
+	question := &Question{}
+	question.FromCreateRequest(&d.CreateQuestionRequest, formID)
+	question.ID = d.ID
+	return question
+}
+
+// ApplyVersion replaces the questions attached to the answers with those of
+// the version the response answered, so answers show the question text the
+// respondent saw, including questions deleted since. Answers are ordered as
+// the version ordered its questions.
+func (f *FilledForm) ApplyVersion(version *FormVersion) {
+	questions := make(map[uuid.UUID]*Question, len(version.Definition.Questions))
+	positions := make(map[uuid.UUID]int, len(version.Definition.Questions))
+	for i := range version.Definition.Questions {
+		def := &version.Definition.Questions[i]
+		questions[def.ID] = def.ToQuestion(version.FormID)
+		positions[def.ID] = i
+	}
+
+	for i := range f.Answers {
+		f.Answers[i].Question = questions[f.Answers[i].QuestionID]
+	}
+
+	sort.SliceStable(f.Answers, func(i, j int) bool {
+		pi, iKnown := positions[f.Answers[i].QuestionID]
+		pj, jKnown := positions[f.Answers[j].QuestionID]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		return pi < pj
+	})
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versionQuestion(id uuid.UUID, text string, position int) QuestionDefinition {
+	return QuestionDefinition{
+		ID:                    id,
+		CreateQuestionRequest: CreateQuestionRequest{QuestionText: text, Type: QuestionTypeBasic, Position: position},
+	}
+}
+
+func TestSameDefinition(t *testing.T) {
+	id := uuid.New()
+	a := &FormDefinition{Title: "Survey", Questions: []QuestionDefinition{versionQuestion(id, "Name?", 1)}}
+	b := &FormDefinition{Title: "Survey", Questions: []QuestionDefinition{versionQuestion(id, "Name?", 1)}}
+	assert.True(t, SameDefinition(a, b))
+
+	b.Questions[0].Required = true
+	assert.False(t, SameDefinition(a, b))
+}
+
+func TestDiffVersions(t *testing.T) {
+	kept, edited, removed, added := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	maxResponses := 50
+
+	from := &FormVersion{Version: 1, Definition: FormDefinition{
+		Title: "Survey",
+		Questions: []QuestionDefinition{
+			versionQuestion(kept, "Name?", 1),
+			versionQuestion(edited, "Age?", 2),
+			versionQuestion(removed, "Phone?", 3),
+		},
+	}}
+	to := &FormVersion{Version: 2, Definition: FormDefinition{
+		Title:        "Survey 2024",
+		MaxResponses: &maxResponses,
+		Questions: []QuestionDefinition{
+			versionQuestion(kept, "Name?", 1),
+			versionQuestion(edited, "How old are you?", 2),
+			versionQuestion(added, "Email?", 3),
+		},
+	}}
+
+	diff := DiffVersions(from, to)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+
+	require.Len(t, diff.Settings, 2)
+	assert.Equal(t, "max_responses", diff.Settings[0].Field)
+	assert.Nil(t, diff.Settings[0].From)
+	assert.Equal(t, FieldChange{Field: "title", From: "Survey", To: "Survey 2024"}, diff.Settings[1])
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, added, diff.Added[0].ID)
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, removed, diff.Removed[0].ID)
+
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, edited, diff.Changed[0].QuestionID)
+	assert.Equal(t, []FieldChange{{Field: "question_text", From: "Age?", To: "How old are you?"}}, diff.Changed[0].Changes)
+}
+
+func TestDiffVersions_Unchanged(t *testing.T) {
+	id := uuid.New()
+	version := &FormVersion{Version: 1, Definition: FormDefinition{Title: "Survey", Questions: []QuestionDefinition{versionQuestion(id, "Name?", 1)}}}
+
+	diff := DiffVersions(version, version)
+	assert.Empty(t, diff.Settings)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Changed)
+}
+
+func TestFilledForm_ApplyVersion(t *testing.T) {
+	formID, first, second := uuid.New(), uuid.New(), uuid.New()
+	version := &FormVersion{FormID: formID, Version: 1, Definition: FormDefinition{
+		Questions: []QuestionDefinition{
+			versionQuestion(first, "Original wording?", 1),
+			versionQuestion(second, "Since deleted?", 2),
+		},
+	}}
+
+	answer := "Yes"
+	response := &FilledForm{Answers: []FilledFormQuestion{
+		{QuestionID: uuid.New(), Answer: &answer, CreatedAt: time.Now()},
+		{QuestionID: second, Answer: &answer},
+		{QuestionID: first, Answer: &answer, Question: &Question{ID: first, QuestionText: "Current wording?"}},
+	}}
+
+	response.ApplyVersion(version)
+
+	require.Len(t, response.Answers, 3)
+	assert.Equal(t, first, response.Answers[0].QuestionID)
+	assert.Equal(t, "Original wording?", response.Answers[0].Question.QuestionText)
+	assert.Equal(t, formID, response.Answers[0].Question.FormID)
+	assert.Equal(t, "Since deleted?", response.Answers[1].Question.QuestionText)
+	// Answers to questions the version does not know come last
+	assert.Nil(t, response.Answers[2].Question)
+}
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent represents an event a webhook can subscribe to
+type WebhookEvent string
+
+const (
+	WebhookEventResponseCreated WebhookEvent = "response.created"
+	WebhookEventResponseUpdated WebhookEvent = "response.updated"
+	WebhookEventResponseDeleted WebhookEvent = "response.deleted"
+	WebhookEventFormOpened      WebhookEvent = "form.opened"
+	WebhookEventFormClosed      WebhookEvent = "form.closed"
+	WebhookEventFormDeleted     WebhookEvent = "form.deleted"
+)
+
+// IsValid returns true if the event is known
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventResponseCreated, WebhookEventResponseUpdated, WebhookEventResponseDeleted, WebhookEventFormOpened, WebhookEventFormClosed, WebhookEventFormDeleted:
+		return true
+	}
+	return false
+}
+
+// DeliveryStatus represents the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Webhook represents an outgoing webhook subscription on a form
+// @Description Webhook subscription delivering form events to a URL
+type Webhook struct {
+	ID        uuid.UUID       `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440010"`           // Webhook unique identifier
+	FormID    uuid.UUID       `json:"form_id" db:"form_id" example:"550e8400-e29b-41d4-a716-446655440002"` // Form whose events are delivered
+	URL       string          `json:"url" db:"url" example:"https://example.com/hooks/anoq"`               // Endpoint receiving the events
+	Secret    string          `json:"-" db:"secret"`                                                       // Key used to sign deliveries
+	Events    JSONStringArray `json:"events" db:"events" example:"[\"response.created\"]"`                 // Subscribed events
+	Active    bool            `json:"active" db:"active" example:"true"`                                   // Inactive webhooks receive no events
+	CreatedAt time.Time       `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// CreateWebhookRequest represents the request payload for creating a webhook
+// @Description Request payload for subscribing a URL to form events
+type CreateWebhookRequest struct {
+	URL    string         `json:"url" validate:"required,url" example:"https://example.com/hooks/anoq"`         // Endpoint receiving the events (required)
+	Secret *string        `json:"secret,omitempty" example:"whsec_9f8e7d6c"`                                    // Signing secret, generated when omitted
+	Events []WebhookEvent `json:"events" validate:"required" example:"[\"response.created\", \"form.closed\"]"` // Events to subscribe to (required)
+}
+
+// UpdateWebhookRequest represents the request payload for updating a webhook
+type UpdateWebhookRequest struct {
+	URL    *string        `json:"url,omitempty"`
+	Secret *string        `json:"secret,omitempty"`
+	Events []WebhookEvent `json:"events,omitempty"`
+	Active *bool          `json:"active,omitempty"`
+}
+
+// WebhookResponse represents the response payload for webhook data. The
+// secret is only included when the webhook is created.
+type WebhookResponse struct {
+	ID        uuid.UUID `json:"id"`
+	FormID    uuid.UUID `json:"form_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery represents one event queued for delivery to a webhook.
+// The URL, payload and signature are captured when the event happens so the
+// delivery still goes out after its webhook or form is deleted.
+// @Description Delivery attempt log of a webhook event
+type WebhookDelivery struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	WebhookID      *uuid.UUID     `json:"webhook_id,omitempty" db:"webhook_id"`
+	FormID         uuid.UUID      `json:"form_id" db:"form_id"`
+	Event          WebhookEvent   `json:"event" db:"event" example:"response.created"`
+	URL            string         `json:"url" db:"url"`
+	Payload        string         `json:"payload" db:"payload"`
+	Signature      string         `json:"-" db:"signature"`
+	Status         DeliveryStatus `json:"status" db:"status" example:"succeeded"`
+	Attempts       int            `json:"attempts" db:"attempts" example:"1"`
+	LastStatusCode *int           `json:"last_status_code,omitempty" db:"last_status_code" example:"200"`
+	LastError      *string        `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookPayload is the JSON body sent to webhook endpoints
+type WebhookPayload struct {
+	ID        uuid.UUID    `json:"id"` // Event ID, shared by every delivery of the event
+	Event     WebhookEvent `json:"event"`
+	FormID    uuid.UUID    `json:"form_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      interface{}  `json:"data"`
+}
+
+// FromCreateRequest populates Webhook from CreateWebhookRequest
+func (w *Webhook) FromCreateRequest(req *CreateWebhookRequest, formID uuid.UUID, secret string) {
+	w.ID = uuid.New()
+	w.FormID = formID
+	w.URL = req.URL
+	w.Secret = secret
+	w.Events = webhookEventStrings(req.Events)
+	w.Active = true
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = time.Now()
+}
+
+// UpdateFromRequest updates Webhook from UpdateWebhookRequest
+func (w *Webhook) UpdateFromRequest(req *UpdateWebhookRequest) {
+	if req.URL != nil {
+		w.URL = *req.URL
+	}
+	if req.Secret != nil {
+		w.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		w.Events = webhookEventStrings(req.Events)
+	}
+	if req.Active != nil {
+		w.Active = *req.Active
+	}
+	w.UpdatedAt = time.Now()
+}
+
+// Validate checks the URL and events of a webhook
+func (w *Webhook) Validate() error {
+	parsed, err := url.ParseRequestURI(w.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("Webhook URL must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		return errors.New("Webhook must subscribe to at least one event")
+	}
+	for _, event := range w.Events {
+		if !WebhookEvent(event).IsValid() {
+			return fmt.Errorf("Invalid webhook event '%s'", event)
+		}
+	}
+	if w.Secret == "" {
+		return errors.New("Webhook secret cannot be empty")
+	}
+	return nil
+}
+
+// StatusChangeEvent returns the event published when a form moves from the
+// previous status to the current one, and false when there is none
+func StatusChangeEvent(previous, current FormStatus) (WebhookEvent, bool) {
+	if previous == current {
+		return "", false
+	}
+	switch current {
+	case FormStatusOpen:
+		return WebhookEventFormOpened, true
+	case FormStatusClosed:
+		return WebhookEventFormClosed, true
+	}
+	return "", false
+}
+
+// Subscribes returns true if the webhook receives the event
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if WebhookEvent(subscribed) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ToResponse converts Webhook to WebhookResponse without its secret
+func (w *Webhook) ToResponse() *WebhookResponse {
+	return &WebhookResponse{
+		ID:        w.ID,
+		FormID:    w.FormID,
+		URL:       w.URL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// webhookEventStrings converts events for storage
+func webhookEventStrings(events []WebhookEvent) JSONStringArray {
+	strs := make(JSONStringArray, len(events))
+	for i, event := range events {
+		strs[i] = string(event)
+	}
+	return strs
+}
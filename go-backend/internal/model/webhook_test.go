@@ -0,0 +1,109 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_FromCreateRequest(t *testing.T) {
+	formID := uuid.New()
+	req := &CreateWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []WebhookEvent{WebhookEventResponseCreated, WebhookEventFormClosed},
+	}
+
+	webhook := &Webhook{}
+	webhook.FromCreateRequest(req, formID, "secret")
+
+	assert.NotEqual(t, uuid.Nil, webhook.ID)
+	assert.Equal(t, formID, webhook.FormID)
+	assert.Equal(t, "secret", webhook.Secret)
+	assert.Equal(t, JSONStringArray{"response.created", "form.closed"}, webhook.Events)
+	assert.True(t, webhook.Active)
+	assert.True(t, webhook.Subscribes(WebhookEventFormClosed))
+	assert.False(t, webhook.Subscribes(WebhookEventFormOpened))
+	assert.Empty(t, webhook.ToResponse().Secret)
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	valid := func() *Webhook {
+		return &Webhook{
+			URL:    "https://example.com/hooks",
+			Secret: "secret",
+			Events: JSONStringArray{"response.created"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(w *Webhook)
+		wantErr string
+	}{
+		{"valid", func(w *Webhook) {}, ""},
+		{"relative URL", func(w *Webhook) { w.URL = "/hooks" }, "absolute http or https URL"},
+		{"other scheme", func(w *Webhook) { w.URL = "ftp://example.com/hooks" }, "absolute http or https URL"},
+		{"no events", func(w *Webhook) { w.Events = JSONStringArray{} }, "at least one event"},
+		{"unknown event", func(w *Webhook) { w.Events = JSONStringArray{"form.renamed"} }, "Invalid webhook event 'form.renamed'"},
+		{"empty secret", func(w *Webhook) { w.Secret = "" }, "secret cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := valid()
+			tt.modify(webhook)
+
+			err := webhook.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWebhook_UpdateFromRequest(t *testing.T) {
+	webhook := &Webhook{
+		URL:    "https://example.com/hooks",
+		Secret: "secret",
+		Events: JSONStringArray{"response.created"},
+		Active: true,
+	}
+
+	inactive := false
+	webhook.UpdateFromRequest(&UpdateWebhookRequest{
+		Events: []WebhookEvent{WebhookEventFormDeleted},
+		Active: &inactive,
+	})
+
+	assert.Equal(t, "https://example.com/hooks", webhook.URL)
+	assert.Equal(t, "secret", webhook.Secret)
+	assert.Equal(t, JSONStringArray{"form.deleted"}, webhook.Events)
+	assert.False(t, webhook.Active)
+}
+
+func TestStatusChangeEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous FormStatus
+		current  FormStatus
+		want     WebhookEvent
+		changed  bool
+	}{
+		{"opened", FormStatusClosed, FormStatusOpen, WebhookEventFormOpened, true},
+		{"closed", FormStatusOpen, FormStatusClosed, WebhookEventFormClosed, true},
+		{"published", FormStatusDraft, FormStatusOpen, WebhookEventFormOpened, true},
+		{"unchanged", FormStatusOpen, FormStatusOpen, "", false},
+		{"still a draft", FormStatusDraft, FormStatusDraft, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, changed := StatusChangeEvent(tt.previous, tt.current)
+			assert.Equal(t, tt.want, event)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceRole represents what a member may do with a workspace and its forms
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleAdmin   WorkspaceRole = "admin"   // Manage members and invitations, delete forms and manage their webhooks
+	WorkspaceRoleEditor  WorkspaceRole = "editor"  // Create and change forms and triage their responses
+	WorkspaceRoleAnalyst WorkspaceRole = "analyst" // Read responses, exports and analytics
+	WorkspaceRoleViewer  WorkspaceRole = "viewer"  // Read forms and their questions
+)
+
+// IsValid returns true if the role is known
+func (r WorkspaceRole) IsValid() bool {
+	return r.rank() > 0
+}
+
+// Allows returns true if the role includes the required role
+func (r WorkspaceRole) Allows(required WorkspaceRole) bool {
+	return r.IsValid() && r.rank() >= required.rank()
+}
+
+// rank orders the roles, each including the roles below it
+func (r WorkspaceRole) rank() int {
+	switch r {
+	case WorkspaceRoleViewer:
+		return 1
+	case WorkspaceRoleAnalyst:
+		return 2
+	case WorkspaceRoleEditor:
+		return 3
+	case WorkspaceRoleAdmin:
+		return 4
+	}
+	return 0
+}
+
+// MaxWorkspaceNameLength bounds the length of workspace names
+const MaxWorkspaceNameLength = 255
+
+// Workspace is a team sharing forms
+// @Description Workspace whose members share its forms
+type Workspace struct {
+	ID        uuid.UUID     `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440030"`
+	Name      string        `json:"name" db:"name" example:"Research team"`
+	CreatedBy *uuid.UUID    `json:"created_by,omitempty" db:"created_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Role      WorkspaceRole `json:"role,omitempty" db:"role" example:"editor"` // Role of the user the workspace was listed for
+	CreatedAt time.Time     `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// WorkspaceMember is a user's membership of a workspace
+// @Description Member of a workspace and their role
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID     `json:"workspace_id" db:"workspace_id" example:"550e8400-e29b-41d4-a716-446655440030"`
+	UserID      uuid.UUID     `json:"user_id" db:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email       string        `json:"email,omitempty" db:"email" example:"user@example.com"` // Included when members are listed
+	Role        WorkspaceRole `json:"role" db:"role" example:"editor"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// WorkspaceInvitation invites an email address to join a workspace with a role
+// @Description Pending invitation to a workspace
+type WorkspaceInvitation struct {
+	ID          uuid.UUID     `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440031"`
+	WorkspaceID uuid.UUID     `json:"workspace_id" db:"workspace_id" example:"550e8400-e29b-41d4-a716-446655440030"`
+	Email       string        `json:"email" db:"email" example:"colleague@example.com"`
+	Role        WorkspaceRole `json:"role" db:"role" example:"analyst"`
+	TokenHash   string        `json:"-" db:"token_hash"` // SHA-256 of the token; the token itself is never stored
+	InvitedBy   *uuid.UUID    `json:"invited_by,omitempty" db:"invited_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExpiresAt   time.Time     `json:"expires_at" db:"expires_at" example:"2023-01-08T10:00:00Z"`
+	AcceptedAt  *time.Time    `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at" example:"2023-01-01T10:00:00Z"`
+}
+
+// CreateWorkspaceRequest represents the request payload for creating a workspace
+// @Description Request payload for creating a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required" example:"Research team"` // Workspace name (required)
+}
+
+// UpdateWorkspaceRequest represents the request payload for renaming a workspace
+type UpdateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required" example:"Research team"`
+}
+
+// CreateInvitationRequest represents the request payload for inviting someone to a workspace
+// @Description Request payload for inviting someone to a workspace
+type CreateInvitationRequest struct {
+	Email string        `json:"email" binding:"required,email" example:"colleague@example.com"` // Address the invitation token is sent to (required)
+	Role  WorkspaceRole `json:"role" binding:"required" example:"analyst"`                      // admin, editor, analyst or viewer (required)
+}
+
+// AcceptInvitationRequest represents the request payload for accepting an invitation
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"` // Token from the invitation email
+}
+
+// UpdateMemberRequest represents the request payload for changing a member's role
+type UpdateMemberRequest struct {
+	Role WorkspaceRole `json:"role" binding:"required" example:"editor"`
+}
+
+// MoveFormRequest represents the request payload for moving a form into or out of a workspace
+type MoveFormRequest struct {
+	WorkspaceID *uuid.UUID `json:"workspace_id"` // Workspace to move the form into; null returns it to its author
+}
+
+// NewWorkspace creates a workspace from CreateWorkspaceRequest
+func NewWorkspace(req *CreateWorkspaceRequest, createdBy uuid.UUID, now time.Time) *Workspace {
+	return &Workspace{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: &createdBy,
+		Role:      WorkspaceRoleAdmin,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the name of the workspace
+func (w *Workspace) Validate() error {
+	if w.Name == "" {
+		return errors.New("Workspace name is required")
+	}
+	if len(w.Name) > MaxWorkspaceNameLength {
+		return fmt.Errorf("Workspace name must be at most %d characters", MaxWorkspaceNameLength)
+	}
+	return nil
+}
+
+// NewWorkspaceInvitation creates an invitation to a workspace that expires after ttl
+func NewWorkspaceInvitation(workspaceID uuid.UUID, req *CreateInvitationRequest, tokenHash string, invitedBy uuid.UUID, now time.Time, ttl time.Duration) (*WorkspaceInvitation, error) {
+	if !req.Role.IsValid() {
+		return nil, errors.New("Role must be admin, editor, analyst or viewer")
+	}
+
+	return &WorkspaceInvitation{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Email:       NormalizeEmail(req.Email),
+		Role:        req.Role,
+		TokenHash:   tokenHash,
+		InvitedBy:   &invitedBy,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}, nil
+}
+
+// IsExpired returns true if the invitation can no longer be accepted
+func (i *WorkspaceInvitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// IsFor returns true if the invitation was sent to the email address
+func (i *WorkspaceInvitation) IsFor(email string) bool {
+	return i.Email == NormalizeEmail(email)
+}
+
+// NormalizeEmail lower-cases an email address and trims surrounding spaces
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceRole_Allows(t *testing.T) {
+	assert.True(t, WorkspaceRoleAdmin.Allows(WorkspaceRoleEditor))
+	assert.True(t, WorkspaceRoleEditor.Allows(WorkspaceRoleAnalyst))
+	assert.True(t, WorkspaceRoleAnalyst.Allows(WorkspaceRoleViewer))
+	assert.True(t, WorkspaceRoleViewer.Allows(WorkspaceRoleViewer))
+	assert.False(t, WorkspaceRoleAnalyst.Allows(WorkspaceRoleEditor))
+	assert.False(t, WorkspaceRole("owner").Allows(WorkspaceRoleViewer))
+	assert.False(t, WorkspaceRole("").IsValid())
+}
+
+func TestNewWorkspace(t *testing.T) {
+	creator := uuid.New()
+	now := time.Now()
+
+	workspace := NewWorkspace(&CreateWorkspaceRequest{Name: "  Research  "}, creator, now)
+
+	assert.NotEqual(t, uuid.Nil, workspace.ID)
+	assert.Equal(t, "Research", workspace.Name)
+	assert.Equal(t, &creator, workspace.CreatedBy)
+	assert.Equal(t, WorkspaceRoleAdmin, workspace.Role)
+	assert.NoError(t, workspace.Validate())
+}
+
+func TestWorkspace_Validate(t *testing.T) {
+	workspace := &Workspace{}
+	assert.EqualError(t, workspace.Validate(), "Workspace name is required")
+
+	workspace.Name = strings.Repeat("a", MaxWorkspaceNameLength+1)
+	assert.EqualError(t, workspace.Validate(), "Workspace name must be at most 255 characters")
+}
+
+func TestNewWorkspaceInvitation(t *testing.T) {
+	workspaceID, inviter := uuid.New(), uuid.New()
+	now := time.Now()
+
+	invitation, err := NewWorkspaceInvitation(workspaceID, &CreateInvitationRequest{Email: " Colleague@Example.com", Role: WorkspaceRoleAnalyst}, "hash", inviter, now, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, workspaceID, invitation.WorkspaceID)
+	assert.Equal(t, "colleague@example.com", invitation.Email)
+	assert.Equal(t, now.Add(time.Hour), invitation.ExpiresAt)
+	assert.True(t, invitation.IsFor("COLLEAGUE@example.com"))
+	assert.False(t, invitation.IsFor("someone@example.com"))
+	assert.False(t, invitation.IsExpired(now))
+	assert.True(t, invitation.IsExpired(now.Add(time.Hour)))
+
+	_, err = NewWorkspaceInvitation(workspaceID, &CreateInvitationRequest{Email: "a@example.com", Role: "owner"}, "hash", inviter, now, time.Hour)
+	assert.EqualError(t, err, "Role must be admin, editor, analyst or viewer")
+}
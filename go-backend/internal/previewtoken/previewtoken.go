@@ -0,0 +1,54 @@
+// Package previewtoken issues and verifies signed tokens that let the owner
+// of a draft form share a preview of it before publishing
+package previewtoken
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/formtoken"
+)
+
+var (
+	ErrInvalid = errors.New("invalid preview token")
+	ErrExpired = errors.New("preview token expired")
+)
+
+// purpose keeps preview tokens apart from other form tokens
+const purpose = "form-preview"
+
+// Signer issues and verifies preview tokens
+type Signer struct {
+	tokens *formtoken.Signer
+	ttl    time.Duration
+}
+
+// NewSigner creates a signer issuing tokens valid for ttl
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{
+		tokens: formtoken.NewSigner(secret, purpose),
+		ttl:    ttl,
+	}
+}
+
+// Issue returns a token to preview the form and when it expires
+func (s *Signer) Issue(formID uuid.UUID, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Millisecond)
+	return s.tokens.Issue(formID, expiresAt), expiresAt
+}
+
+// Verify checks the token was issued for formID and has not expired
+func (s *Signer) Verify(token string, formID uuid.UUID, now time.Time) error {
+	expiresAt, err := s.tokens.Verify(token, formID)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !now.Before(expiresAt) {
+		return ErrExpired
+	}
+
+	return nil
+}
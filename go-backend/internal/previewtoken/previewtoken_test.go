@@ -0,0 +1,48 @@
+package previewtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	formID := uuid.New()
+	now := time.Now()
+
+	token, expiresAt := signer.Issue(formID, now)
+	assert.WithinDuration(t, now.Add(time.Hour), expiresAt, time.Millisecond)
+	require.NoError(t, signer.Verify(token, formID, now.Add(59*time.Minute)))
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	formID := uuid.New()
+	now := time.Now()
+	token, _ := signer.Issue(formID, now)
+	otherSecret, _ := NewSigner("other", time.Hour).Issue(formID, now)
+
+	tests := []struct {
+		name    string
+		token   string
+		formID  uuid.UUID
+		now     time.Time
+		wantErr error
+	}{
+		{"other form", token, uuid.New(), now, ErrInvalid},
+		{"other secret", otherSecret, formID, now, ErrInvalid},
+		{"tampered", token[:len(token)-2] + "AA", formID, now, ErrInvalid},
+		{"garbage", "not-a-token", formID, now, ErrInvalid},
+		{"expired", token, formID, now.Add(time.Hour), ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, signer.Verify(tt.token, tt.formID, tt.now), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type APIKeyRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *APIKeyRepository
+}
+
+func (s *APIKeyRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &APIKeyRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *APIKeyRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositorySuite))
+}
+
+var apiKeyRowColumns = []string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+func (s *APIKeyRepositorySuite) TestCreateAPIKey_Success() {
+	key := &model.APIKey{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Name:      "CI",
+		Prefix:    "anoq_abcdef",
+		KeyHash:   "hash",
+		Scopes:    model.JSONStringArray{"forms:read"},
+		CreatedAt: time.Now(),
+	}
+
+	query := `INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(key.ID, key.UserID, "CI", "anoq_abcdef", "hash", []byte(`["forms:read"]`), nil, key.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := s.repo.CreateAPIKey(context.Background(), key)
+	s.Require().NoError(err)
+}
+
+func (s *APIKeyRepositorySuite) TestGetAPIKeyByHash_Success() {
+	id := uuid.New()
+	rows := sqlmock.NewRows(apiKeyRowColumns).
+		AddRow(id, uuid.New(), "CI", "anoq_abcdef", "hash", []byte(`["forms:read","responses:read"]`), nil, nil, nil, time.Now())
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM api_keys WHERE key_hash = $1`)).WithArgs("hash").WillReturnRows(rows)
+
+	key, err := s.repo.GetAPIKeyByHash(context.Background(), "hash")
+	s.Require().NoError(err)
+	s.Equal(id, key.ID)
+	s.True(key.HasScope(model.APIKeyScopeResponsesRead))
+}
+
+func (s *APIKeyRepositorySuite) TestGetAPIKeyByHash_NotFound() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM api_keys WHERE key_hash = $1`)).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+	key, err := s.repo.GetAPIKeyByHash(context.Background(), "hash")
+	s.Nil(key)
+	s.EqualError(err, "api key not found")
+}
+
+func (s *APIKeyRepositorySuite) TestListAPIKeysByUserID_SkipsRevoked() {
+	userID := uuid.New()
+	rows := sqlmock.NewRows(apiKeyRowColumns).
+		AddRow(uuid.New(), userID, "CI", "anoq_abcdef", "hash", []byte(`["forms:read"]`), nil, nil, nil, time.Now())
+	s.mock.ExpectQuery(regexp.QuoteMeta(`WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC, id DESC`)).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	keys, err := s.repo.ListAPIKeysByUserID(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Len(keys, 1)
+}
+
+func (s *APIKeyRepositorySuite) TestRevokeAPIKey_NotFound() {
+	userID, id := uuid.New(), uuid.New()
+	now := time.Now()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE api_keys SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`)).
+		WithArgs(id, userID, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.RevokeAPIKey(context.Background(), userID, id, now)
+	s.EqualError(err, "api key not found")
+}
+
+func (s *APIKeyRepositorySuite) TestTouchAPIKey_AtMostOncePerInterval() {
+	id := uuid.New()
+	now := time.Now()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE api_keys SET last_used_at = $2 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)`)).
+		WithArgs(id, now, now.Add(-time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.TouchAPIKey(context.Background(), id, now)
+	s.Require().NoError(err)
+}
@@ -7,26 +7,63 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/ayan-sh03/anoq/internal/db"
 	"github.com/ayan-sh03/anoq/internal/model"
 )
 
+const formColumns = `id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses,
+	duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds`
+
 // CreateForm creates a new form
 func (r *FormRepository) CreateForm(ctx context.Context, form *model.Form) error {
+	return createForm(ctx, r.db, form)
+}
+
+// CreateFormWithQuestions creates a form together with its questions and their
+// type-specific settings in a single transaction
+func (r *FormRepository) CreateFormWithQuestions(ctx context.Context, form *model.Form, questions []*model.Question) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if err := createForm(ctx, tx, form); err != nil {
+			return err
+		}
+
+		for _, question := range questions {
+			if err := createQuestion(ctx, tx, question); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// createForm inserts a form
+func createForm(ctx context.Context, exec execer, form *model.Form) error {
 	query := `
-		INSERT INTO forms (id, title, description, slug, author_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO forms (id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses,
+		                   duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		form.ID,
 		form.Title,
 		form.Description,
 		form.Slug,
 		form.AuthorID, //author_id
+		form.WorkspaceID,
 		form.Status,
 		form.CreatedAt,
 		form.UpdatedAt,
+		form.OpensAt,
+		form.ClosesAt,
+		form.ScheduleAppliedAt,
+		form.MaxResponses,
+		form.DuplicatePolicy,
+		form.DuplicateWindowSeconds,
+		form.AnonymityLevel,
+		form.EditWindowSeconds,
 	)
 
 	if err != nil {
@@ -42,7 +79,7 @@ func (r *FormRepository) CreateForm(ctx context.Context, form *model.Form) error
 // GetFormByID retrieves a form by ID
 func (r *FormRepository) GetFormByID(ctx context.Context, id uuid.UUID) (*model.Form, error) {
 	query := `
-		SELECT id, title, description, slug, author_id, status, created_at, updated_at
+		SELECT ` + formColumns + `
 		FROM forms
 		WHERE id = $1`
 
@@ -61,7 +98,7 @@ func (r *FormRepository) GetFormByID(ctx context.Context, id uuid.UUID) (*model.
 // GetFormBySlug retrieves a form by slug
 func (r *FormRepository) GetFormBySlug(ctx context.Context, slug string) (*model.Form, error) {
 	query := `
-		SELECT id, title, description, slug, author_id, status, created_at, updated_at
+		SELECT ` + formColumns + `
 		FROM forms
 		WHERE slug = $1`
 
@@ -77,36 +114,109 @@ func (r *FormRepository) GetFormBySlug(ctx context.Context, slug string) (*model
 	return &form, nil
 }
 
-// ListFormsByUserID retrieves all forms for a user
-func (r *FormRepository) ListFormsByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Form, error) {
+// accessibleBy is the condition on forms of alias that the user in $1 can
+// access: forms they wrote and forms of the workspaces they are a member of
+func accessibleBy(alias string) string {
+	return `(` + alias + `.author_id = $1 OR ` + alias + `.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`
+}
+
+// ListFormsByUserID retrieves one page of the forms a user can access, their
+// own and their workspaces', newest first. The returned cursor is nil on the
+// last page.
+func (r *FormRepository) ListFormsByUserID(ctx context.Context, userID uuid.UUID, page model.PageRequest) ([]*model.Form, *model.Cursor, error) {
+	return r.listForms(ctx, accessibleBy("forms"), userID, page)
+}
+
+// ListFormsByWorkspaceID retrieves one page of the forms of a workspace,
+// newest first. The returned cursor is nil on the last page.
+func (r *FormRepository) ListFormsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID, page model.PageRequest) ([]*model.Form, *model.Cursor, error) {
+	return r.listForms(ctx, `workspace_id = $1`, workspaceID, page)
+}
+
+// listForms retrieves one page of the forms matching condition on $1
+func (r *FormRepository) listForms(ctx context.Context, condition string, id uuid.UUID, page model.PageRequest) ([]*model.Form, *model.Cursor, error) {
+	pageCondition, orderLimit, args := pageClauses(page, "created_at", "id", []interface{}{id})
 	query := `
-		SELECT id, title, description, slug, author_id, status, created_at, updated_at
+		SELECT ` + formColumns + `
 		FROM forms
-		WHERE author_id = $1
-		ORDER BY created_at DESC`
+		WHERE ` + condition + pageCondition + orderLimit
 
 	var forms []*model.Form
-	err := r.db.SelectContext(ctx, &forms, query, userID)
+	err := r.db.SelectContext(ctx, &forms, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list forms: %w", err)
+		return nil, nil, fmt.Errorf("failed to list forms: %w", err)
 	}
 
-	return forms, nil
+	if len(forms) <= page.Limit {
+		return forms, nil, nil
+	}
+
+	forms = forms[:page.Limit]
+	last := forms[len(forms)-1]
+	return forms, page.NextCursor(last.CreatedAt, last.ID), nil
 }
 
 // UpdateForm updates a form
 func (r *FormRepository) UpdateForm(ctx context.Context, form *model.Form) error {
+	return updateForm(ctx, r.db, form)
+}
+
+// UpdateFormWithQuestions updates a form and creates, updates and deletes its
+// questions in a single transaction
+func (r *FormRepository) UpdateFormWithQuestions(ctx context.Context, form *model.Form, changes *model.QuestionChanges) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if err := updateForm(ctx, tx, form); err != nil {
+			return err
+		}
+
+		// Type-specific settings are deleted with the question
+		for _, questionID := range changes.Deleted {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM questions WHERE id = $1 AND form_id = $2`, questionID, form.ID); err != nil {
+				return fmt.Errorf("failed to delete question: %w", err)
+			}
+		}
+
+		for _, question := range changes.Updated {
+			if err := updateQuestion(ctx, tx, question); err != nil {
+				return err
+			}
+		}
+
+		for _, question := range changes.Created {
+			if err := createQuestion(ctx, tx, question); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// updateForm updates the settings of a form
+func updateForm(ctx context.Context, exec execer, form *model.Form) error {
 	query := `
 		UPDATE forms
-		SET title = $2, description = $3, status = $4, updated_at = $5
+		SET title = $2, description = $3, status = $4, updated_at = $5,
+		    opens_at = $6, closes_at = $7, schedule_applied_at = $8, max_responses = $9,
+		    duplicate_policy = $10, duplicate_window_seconds = $11, anonymity_level = $12,
+		    edit_window_seconds = $13, workspace_id = $14
 		WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		form.ID,
 		form.Title,
 		form.Description,
 		form.Status,
 		form.UpdatedAt,
+		form.OpensAt,
+		form.ClosesAt,
+		form.ScheduleAppliedAt,
+		form.MaxResponses,
+		form.DuplicatePolicy,
+		form.DuplicateWindowSeconds,
+		form.AnonymityLevel,
+		form.EditWindowSeconds,
+		form.WorkspaceID,
 	)
 
 	if err != nil {
@@ -170,13 +280,57 @@ func (r *FormRepository) UpdateFormStatus(ctx context.Context, id uuid.UUID, sta
 	return nil
 }
 
-// GetDashboardStats retrieves dashboard statistics
+// ApplyDueSchedules sets the status of every form whose opens_at or closes_at
+// has passed since its schedule was last applied, and returns the forms whose
+// status changed. Drafts are skipped until they are published. Rows are
+// locked with SKIP LOCKED and each time is applied once, so several replicas
+// can run it concurrently.
+func (r *FormRepository) ApplyDueSchedules(ctx context.Context, now time.Time) ([]*model.Form, error) {
+	query := `
+		WITH due AS (
+			SELECT id, status AS previous_status
+			FROM forms
+			WHERE status <> 'draft'
+			  AND ((opens_at <= $1 AND (schedule_applied_at IS NULL OR schedule_applied_at < opens_at))
+			   OR (closes_at <= $1 AND (schedule_applied_at IS NULL OR schedule_applied_at < closes_at)))
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE forms f
+		SET status = CASE WHEN f.closes_at <= $1 THEN 'closed'::form_status ELSE 'open'::form_status END,
+		    schedule_applied_at = $1,
+		    updated_at = $1
+		FROM due
+		WHERE f.id = due.id
+		RETURNING f.id, f.title, f.description, f.slug, f.author_id, f.workspace_id, f.status, f.created_at, f.updated_at,
+		          f.opens_at, f.closes_at, f.schedule_applied_at, f.max_responses,
+		          f.duplicate_policy, f.duplicate_window_seconds, f.anonymity_level,
+		          f.edit_window_seconds, due.previous_status`
+
+	var rows []struct {
+		model.Form
+		PreviousStatus model.FormStatus `db:"previous_status"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, now); err != nil {
+		return nil, fmt.Errorf("failed to apply form schedules: %w", err)
+	}
+
+	var changed []*model.Form
+	for i := range rows {
+		if rows[i].Status != rows[i].PreviousStatus {
+			changed = append(changed, &rows[i].Form)
+		}
+	}
+
+	return changed, nil
+}
+
+// GetDashboardStats retrieves dashboard statistics over the forms a user can access
 func (r *FormRepository) GetDashboardStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Get total forms count
 	var totalForms int
-	err := r.db.GetContext(ctx, &totalForms, "SELECT COUNT(*) FROM forms WHERE author_id = $1", userID)
+	err := r.db.GetContext(ctx, &totalForms, "SELECT COUNT(*) FROM forms f WHERE "+accessibleBy("f"), userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total forms: %w", err)
 	}
@@ -187,14 +341,14 @@ func (r *FormRepository) GetDashboardStats(ctx context.Context, userID uuid.UUID
 		SELECT COUNT(*)
 		FROM filled_forms ff
 		JOIN forms f ON ff.form_id = f.id
-		WHERE f.author_id = $1`, userID)
+		WHERE `+accessibleBy("f"), userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total responses: %w", err)
 	}
 
 	// Get active forms count
 	var activeForms int
-	err = r.db.GetContext(ctx, &activeForms, "SELECT COUNT(*) FROM forms WHERE author_id = $1 AND status = 'open'", userID)
+	err = r.db.GetContext(ctx, &activeForms, "SELECT COUNT(*) FROM forms f WHERE "+accessibleBy("f")+" AND f.status = 'open'", userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active forms: %w", err)
 	}
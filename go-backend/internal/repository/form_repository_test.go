@@ -40,6 +40,8 @@ func TestFormRepositorySuite(t *testing.T) {
 	suite.Run(t, new(FormRepositorySuite))
 }
 
+var formRowColumns = []string{"id", "title", "description", "slug", "author_id", "workspace_id", "status", "created_at", "updated_at", "opens_at", "closes_at", "schedule_applied_at", "max_responses", "duplicate_policy", "duplicate_window_seconds", "anonymity_level", "edit_window_seconds"}
+
 func (s *FormRepositorySuite) TestCreateForm_Success() {
 	form := &model.Form{
 		ID:          uuid.New(),
@@ -52,9 +54,9 @@ func (s *FormRepositorySuite) TestCreateForm_Success() {
 		UpdatedAt:   time.Now(),
 	}
 
-	query := `INSERT INTO forms (id, title, description, slug, author_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO forms (id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
 	s.mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(form.ID, form.Title, form.Description, form.Slug, form.AuthorID, form.Status, form.CreatedAt, form.UpdatedAt).
+		WithArgs(form.ID, form.Title, form.Description, form.Slug, form.AuthorID, form.WorkspaceID, form.Status, form.CreatedAt, form.UpdatedAt, form.OpensAt, form.ClosesAt, form.ScheduleAppliedAt, form.MaxResponses, form.DuplicatePolicy, form.DuplicateWindowSeconds, form.AnonymityLevel, form.EditWindowSeconds).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := s.repo.CreateForm(context.Background(), form)
@@ -75,6 +77,79 @@ func (s *FormRepositorySuite) TestCreateForm_UniqueViolation() {
 	s.Contains(err.Error(), "form with slug existing-slug already exists")
 }
 
+func (s *FormRepositorySuite) TestCreateFormWithQuestions_SingleTransaction() {
+	form := &model.Form{ID: uuid.New(), Slug: "weekly-copy", Status: model.FormStatusOpen}
+	questions := []*model.Question{
+		{ID: uuid.New(), FormID: form.ID, QuestionText: "Name?", Type: model.QuestionTypeBasic, Position: 1},
+		{ID: uuid.New(), FormID: form.ID, QuestionText: "Mood?", Type: model.QuestionTypeMultipleChoice, Position: 2, Choices: model.JSONStringArray{"Good", "Bad"}},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO forms`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO questions`).WithArgs(questions[0].ID, form.ID, "Name?", nil, model.QuestionTypeBasic, 1, false, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO questions`).WithArgs(questions[1].ID, form.ID, "Mood?", nil, model.QuestionTypeMultipleChoice, 2, false, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO multiple_choice_questions`).WithArgs(sqlmock.AnyArg(), questions[1].ID, []byte(`["Good","Bad"]`), false).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.CreateFormWithQuestions(context.Background(), form, questions)
+	s.Require().NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *FormRepositorySuite) TestCreateFormWithQuestions_RollsBackOnSlugConflict() {
+	form := &model.Form{ID: uuid.New(), Slug: "taken"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO forms`).WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key"})
+	s.mock.ExpectRollback()
+
+	err := s.repo.CreateFormWithQuestions(context.Background(), form, nil)
+	s.Require().Error(err)
+	s.Equal("form with slug taken already exists", err.Error())
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *FormRepositorySuite) TestUpdateFormWithQuestions_SingleTransaction() {
+	form := &model.Form{ID: uuid.New(), Title: "Weekly", Status: model.FormStatusDraft}
+	updated := &model.Question{ID: uuid.New(), FormID: form.ID, QuestionText: "Mood?", Type: model.QuestionTypeMultipleChoice, Position: 1, Choices: model.JSONStringArray{"Good", "Bad"}}
+	created := &model.Question{ID: uuid.New(), FormID: form.ID, QuestionText: "Name?", Type: model.QuestionTypeBasic, Position: 2}
+	deleted := uuid.New()
+	changes := &model.QuestionChanges{
+		Created:   []*model.Question{created},
+		Updated:   []*model.Question{updated},
+		Deleted:   []uuid.UUID{deleted},
+		Questions: []*model.Question{updated, created},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE forms`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM questions WHERE id = $1 AND form_id = $2`)).WithArgs(deleted, form.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(`UPDATE questions`).WithArgs("Mood?", nil, model.QuestionTypeMultipleChoice, 1, false, nil, updated.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM multiple_choice_questions WHERE question_id = $1)`)).WithArgs(updated.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.mock.ExpectExec(`UPDATE multiple_choice_questions`).WithArgs([]byte(`["Good","Bad"]`), false, updated.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(`INSERT INTO questions`).WithArgs(created.ID, form.ID, "Name?", nil, model.QuestionTypeBasic, 2, false, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.UpdateFormWithQuestions(context.Background(), form, changes)
+	s.Require().NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *FormRepositorySuite) TestUpdateFormWithQuestions_RollsBackOnFailure() {
+	form := &model.Form{ID: uuid.New(), Title: "Weekly"}
+	created := &model.Question{ID: uuid.New(), FormID: form.ID, QuestionText: "Name?", Type: model.QuestionTypeBasic, Position: 1}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE forms`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(`INSERT INTO questions`).WillReturnError(sql.ErrConnDone)
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateFormWithQuestions(context.Background(), form, &model.QuestionChanges{Created: []*model.Question{created}})
+	s.Require().Error(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
 func (s *FormRepositorySuite) TestGetFormBySlug_Success() {
 	slug := "test-form"
 	expectedForm := &model.Form{
@@ -83,10 +158,10 @@ func (s *FormRepositorySuite) TestGetFormBySlug_Success() {
 		Title: "Test Form",
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "slug", "author_id", "status", "created_at", "updated_at"}).
-		AddRow(expectedForm.ID, expectedForm.Title, "", expectedForm.Slug, uuid.New(), "open", time.Now(), time.Now())
+	rows := sqlmock.NewRows(formRowColumns).
+		AddRow(expectedForm.ID, expectedForm.Title, "", expectedForm.Slug, uuid.New(), nil, "open", time.Now(), time.Now(), nil, nil, nil, nil, "none", nil, "identified", nil)
 
-	query := `SELECT id, title, description, slug, author_id, status, created_at, updated_at FROM forms WHERE slug = $1`
+	query := `SELECT id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds FROM forms WHERE slug = $1`
 	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(slug).WillReturnRows(rows)
 
 	form, err := s.repo.GetFormBySlug(context.Background(), slug)
@@ -100,44 +175,85 @@ func (s *FormRepositorySuite) TestGetForm_NotFound() {
 	slug := "non-existent-form"
 
 	// Test by ID
-	idQuery := `SELECT id, title, description, slug, author_id, status, created_at, updated_at FROM forms WHERE id = $1`
+	idQuery := `SELECT id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds FROM forms WHERE id = $1`
 	s.mock.ExpectQuery(regexp.QuoteMeta(idQuery)).WithArgs(id).WillReturnError(sql.ErrNoRows)
 	_, err := s.repo.GetFormByID(context.Background(), id)
 	s.Require().Error(err)
 	s.Contains(err.Error(), "form not found")
 
 	// Test by Slug
-	slugQuery := `SELECT id, title, description, slug, author_id, status, created_at, updated_at FROM forms WHERE slug = $1`
+	slugQuery := `SELECT id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds FROM forms WHERE slug = $1`
 	s.mock.ExpectQuery(regexp.QuoteMeta(slugQuery)).WithArgs(slug).WillReturnError(sql.ErrNoRows)
 	_, err = s.repo.GetFormBySlug(context.Background(), slug)
 	s.Require().Error(err)
 	s.Contains(err.Error(), "form not found")
 }
 
+var firstFormsPage = model.PageRequest{Limit: model.DefaultPageSize, Sort: "created_at", Order: model.SortDesc}
+
 func (s *FormRepositorySuite) TestListFormsByUserID() {
 	userID := uuid.New()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "slug", "author_id", "status", "created_at", "updated_at"}).
-		AddRow(uuid.New(), "Form 1", "", "form-1", userID, "open", time.Now(), time.Now()).
-		AddRow(uuid.New(), "Form 2", "", "form-2", userID, "closed", time.Now(), time.Now())
+	rows := sqlmock.NewRows(formRowColumns).
+		AddRow(uuid.New(), "Form 1", "", "form-1", userID, nil, "open", time.Now(), time.Now(), nil, nil, nil, nil, "none", nil, "identified", nil).
+		AddRow(uuid.New(), "Form 2", "", "form-2", userID, nil, "closed", time.Now(), time.Now(), nil, nil, nil, nil, "none", nil, "identified", nil)
 
-	query := `SELECT id, title, description, slug, author_id, status, created_at, updated_at FROM forms WHERE author_id = $1 ORDER BY created_at DESC`
-	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnRows(rows)
+	query := `SELECT id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds FROM forms WHERE (forms.author_id = $1 OR forms.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)) ORDER BY created_at DESC, id DESC LIMIT $2`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 51).WillReturnRows(rows)
 
-	forms, err := s.repo.ListFormsByUserID(context.Background(), userID)
+	forms, next, err := s.repo.ListFormsByUserID(context.Background(), userID, firstFormsPage)
 	s.Require().NoError(err)
 	s.Len(forms, 2)
+	s.Nil(next)
+}
+
+func (s *FormRepositorySuite) TestListFormsByUserID_NextPage() {
+	userID := uuid.New()
+	newest, older := time.Now(), time.Now().Add(-time.Hour)
+	after := &model.Cursor{Sort: "created_at", Order: model.SortDesc, Value: time.Now().Add(-time.Minute), ID: uuid.New()}
+	page := model.PageRequest{Limit: 1, Sort: "created_at", Order: model.SortDesc, After: after}
+
+	olderID := uuid.New()
+	rows := sqlmock.NewRows(formRowColumns).
+		AddRow(olderID, "Form 1", "", "form-1", userID, nil, "open", older, older, nil, nil, nil, nil, "none", nil, "identified", nil).
+		AddRow(uuid.New(), "Form 2", "", "form-2", userID, nil, "open", newest, newest, nil, nil, nil, nil, "none", nil, "identified", nil)
+
+	query := `FROM forms WHERE (forms.author_id = $1 OR forms.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)) AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, after.Value, after.ID, 2).WillReturnRows(rows)
+
+	forms, next, err := s.repo.ListFormsByUserID(context.Background(), userID, page)
+	s.Require().NoError(err)
+	s.Len(forms, 1)
+	s.Require().NotNil(next)
+	s.Equal(olderID, next.ID)
+	s.True(next.Value.Equal(older))
 }
 
 func (s *FormRepositorySuite) TestListFormsByUserID_Empty() {
 	userID := uuid.New()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "slug", "author_id", "status", "created_at", "updated_at"})
+	rows := sqlmock.NewRows(formRowColumns)
 
-	query := `SELECT id, title, description, slug, author_id, status, created_at, updated_at FROM forms WHERE author_id = $1 ORDER BY created_at DESC`
-	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnRows(rows)
+	query := `SELECT id, title, description, slug, author_id, workspace_id, status, created_at, updated_at, opens_at, closes_at, schedule_applied_at, max_responses, duplicate_policy, duplicate_window_seconds, anonymity_level, edit_window_seconds FROM forms WHERE (forms.author_id = $1 OR forms.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)) ORDER BY created_at DESC, id DESC LIMIT $2`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID, 51).WillReturnRows(rows)
 
-	forms, err := s.repo.ListFormsByUserID(context.Background(), userID)
+	forms, next, err := s.repo.ListFormsByUserID(context.Background(), userID, firstFormsPage)
 	s.Require().NoError(err)
 	s.Len(forms, 0) // Expect an empty slice, not nil
+	s.Nil(next)
+}
+
+func (s *FormRepositorySuite) TestListFormsByWorkspaceID() {
+	workspaceID := uuid.New()
+	rows := sqlmock.NewRows(formRowColumns).
+		AddRow(uuid.New(), "Shared", "", "shared", uuid.New(), workspaceID, "open", time.Now(), time.Now(), nil, nil, nil, nil, "allow", nil, "anonymous", nil)
+
+	query := `FROM forms WHERE workspace_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(workspaceID, 51).WillReturnRows(rows)
+
+	forms, next, err := s.repo.ListFormsByWorkspaceID(context.Background(), workspaceID, firstFormsPage)
+	s.Require().NoError(err)
+	s.Require().Len(forms, 1)
+	s.Equal(&workspaceID, forms[0].WorkspaceID)
+	s.Nil(next)
 }
 
 func (s *FormRepositorySuite) TestUpdateForm_Success() {
@@ -148,9 +264,9 @@ func (s *FormRepositorySuite) TestUpdateForm_Success() {
 		Status:      model.FormStatusClosed,
 		UpdatedAt:   time.Now(),
 	}
-	query := `UPDATE forms SET title = $2, description = $3, status = $4, updated_at = $5 WHERE id = $1`
+	query := `UPDATE forms SET title = $2, description = $3, status = $4, updated_at = $5, opens_at = $6, closes_at = $7, schedule_applied_at = $8, max_responses = $9, duplicate_policy = $10, duplicate_window_seconds = $11, anonymity_level = $12, edit_window_seconds = $13, workspace_id = $14 WHERE id = $1`
 	s.mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(form.ID, form.Title, form.Description, form.Status, form.UpdatedAt).
+		WithArgs(form.ID, form.Title, form.Description, form.Status, form.UpdatedAt, form.OpensAt, form.ClosesAt, form.ScheduleAppliedAt, form.MaxResponses, form.DuplicatePolicy, form.DuplicateWindowSeconds, form.AnonymityLevel, form.EditWindowSeconds, form.WorkspaceID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := s.repo.UpdateForm(context.Background(), form)
@@ -210,17 +326,17 @@ func (s *FormRepositorySuite) TestGetDashboardStats_Success() {
 	userID := uuid.New()
 
 	// Mock for total forms
-	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM forms WHERE author_id = $1")).
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM forms f WHERE (f.author_id = $1 OR f.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))")).
 		WithArgs(userID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
 	// Mock for total responses
-	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM filled_forms ff JOIN forms f ON ff.form_id = f.id WHERE f.author_id = $1`)).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM filled_forms ff JOIN forms f ON ff.form_id = f.id WHERE (f.author_id = $1 OR f.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`)).
 		WithArgs(userID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(120))
 
 	// Mock for active forms
-	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM forms WHERE author_id = $1 AND status = 'open'")).
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM forms f WHERE (f.author_id = $1 OR f.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)) AND f.status = 'open'")).
 		WithArgs(userID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
@@ -235,7 +351,7 @@ func (s *FormRepositorySuite) TestGetDashboardStats_PartialFailure() {
 	userID := uuid.New()
 
 	// Test case 1: Failure on the second query (total responses)
-	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms WHERE author_id = \$1`).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms f WHERE \(f.author_id = \$1`).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM filled_forms`).WithArgs(userID).WillReturnError(sql.ErrConnDone)
 	_, err := s.repo.GetDashboardStats(context.Background(), userID)
 	s.Require().Error(err)
@@ -246,10 +362,37 @@ func (s *FormRepositorySuite) TestGetDashboardStats_ThirdQueryFailure() {
 	userID := uuid.New()
 
 	// Test case 2: Failure on the third query (active forms)
-	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms WHERE author_id = \$1`).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms f WHERE \(f.author_id = \$1`).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM filled_forms`).WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(120))
-	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms WHERE author_id = \$1 AND status = 'open'`).WithArgs(userID).WillReturnError(sql.ErrConnDone)
+	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM forms f WHERE .* AND f.status = 'open'`).WithArgs(userID).WillReturnError(sql.ErrConnDone)
 	_, err := s.repo.GetDashboardStats(context.Background(), userID)
 	s.Require().Error(err)
 	s.Contains(err.Error(), "failed to get active forms")
 }
+
+func (s *FormRepositorySuite) TestApplyDueSchedules_ReturnsChangedForms() {
+	now := time.Now()
+	opensAt := now.Add(-time.Minute)
+	closesAt := now.Add(-time.Second)
+	columns := append(append([]string{}, formRowColumns...), "previous_status")
+	rows := sqlmock.NewRows(columns).
+		AddRow(uuid.New(), "Opened", "", "opened", uuid.New(), nil, "open", now, now, opensAt, nil, now, nil, "none", nil, "identified", nil, "closed").
+		AddRow(uuid.New(), "Closed", "", "closed", uuid.New(), nil, "closed", now, now, opensAt, closesAt, now, 50, "email", 3600, "pseudonymous", 600, "open").
+		AddRow(uuid.New(), "Unchanged", "", "unchanged", uuid.New(), nil, "open", now, now, opensAt, nil, now, nil, "none", nil, "identified", nil, "open")
+
+	// The CASE yields text unless the literals are cast to the enum
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, status AS previous_status FROM forms WHERE status <> 'draft'`) + `.*` +
+		regexp.QuoteMeta(`SET status = CASE WHEN f.closes_at <= $1 THEN 'closed'::form_status ELSE 'open'::form_status END`)).
+		WithArgs(now).
+		WillReturnRows(rows)
+
+	forms, err := s.repo.ApplyDueSchedules(context.Background(), now)
+	s.Require().NoError(err)
+	s.Require().Len(forms, 2)
+	s.Equal("opened", forms[0].Slug)
+	s.Equal(model.FormStatusOpen, forms[0].Status)
+	s.Equal("closed", forms[1].Slug)
+	s.Equal(model.FormStatusClosed, forms[1].Status)
+	s.Require().NotNil(forms[1].ClosesAt)
+	s.True(closesAt.Equal(*forms[1].ClosesAt))
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/ayan-sh03/anoq/internal/model"
 	gomock "github.com/golang/mock/gomock"
@@ -65,18 +66,18 @@ func (mr *MockUserRepoMockRecorder) CleanupExpiredSessions(arg0 interface{}) *go
 }
 
 // CreateSession mocks base method.
-func (m *MockUserRepo) CreateSession(arg0 context.Context, arg1 uuid.UUID) (*model.UserSession, error) {
+func (m *MockUserRepo) CreateSession(arg0 context.Context, arg1 uuid.UUID, arg2 model.SessionClient, arg3 time.Duration) (*model.UserSession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*model.UserSession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockUserRepoMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) CreateSession(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockUserRepo)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockUserRepo)(nil).CreateSession), arg0, arg1, arg2, arg3)
 }
 
 // CreateUser mocks base method.
@@ -107,6 +108,34 @@ func (mr *MockUserRepoMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockUserRepo)(nil).DeleteSession), arg0, arg1)
 }
 
+// DeleteUserSession mocks base method.
+func (m *MockUserRepo) DeleteUserSession(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSession", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSession indicates an expected call of DeleteUserSession.
+func (mr *MockUserRepoMockRecorder) DeleteUserSession(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSession", reflect.TypeOf((*MockUserRepo)(nil).DeleteUserSession), arg0, arg1, arg2)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockUserRepo) DeleteUserSessions(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockUserRepoMockRecorder) DeleteUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockUserRepo)(nil).DeleteUserSessions), arg0, arg1)
+}
+
 // GetSessionByToken mocks base method.
 func (m *MockUserRepo) GetSessionByToken(arg0 context.Context, arg1 string) (*model.UserSession, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +196,35 @@ func (mr *MockUserRepoMockRecorder) HashPassword(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockUserRepo)(nil).HashPassword), arg0)
 }
 
+// ListSessionsByUserID mocks base method.
+func (m *MockUserRepo) ListSessionsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]*model.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionsByUserID indicates an expected call of ListSessionsByUserID.
+func (mr *MockUserRepoMockRecorder) ListSessionsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionsByUserID", reflect.TypeOf((*MockUserRepo)(nil).ListSessionsByUserID), arg0, arg1)
+}
+
+// TouchSession mocks base method.
+func (m *MockUserRepo) TouchSession(arg0 context.Context, arg1 uuid.UUID, arg2 time.Time, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockUserRepoMockRecorder) TouchSession(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockUserRepo)(nil).TouchSession), arg0, arg1, arg2, arg3)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserRepo) UpdateUser(arg0 context.Context, arg1 *model.User) error {
 	m.ctrl.T.Helper()
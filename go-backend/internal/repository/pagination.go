@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// pageClauses returns the keyset condition selecting the rows after the
+// page's cursor, to be appended to a WHERE clause, and the ORDER BY and LIMIT
+// clauses of the page. Rows are sorted by column with idColumn breaking ties,
+// and one row more than the page size is fetched to tell whether another page
+// follows.
+func pageClauses(page model.PageRequest, column, idColumn string, args []interface{}) (condition, orderLimit string, _ []interface{}) {
+	direction, comparison := "DESC", "<"
+	if page.Order == model.SortAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	if page.After != nil {
+		args = append(args, page.After.Value, page.After.ID)
+		condition = fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", column, idColumn, comparison, len(args)-1, len(args))
+	}
+
+	args = append(args, page.Limit+1)
+	orderLimit = fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", column, direction, idColumn, direction, len(args))
+
+	return condition, orderLimit, args
+}
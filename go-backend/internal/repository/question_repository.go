@@ -12,11 +12,16 @@ import (
 
 // CreateQuestion creates a new question
 func (r *QuestionRepository) CreateQuestion(ctx context.Context, question *model.Question) error {
+	return createQuestion(ctx, r.db, question)
+}
+
+// createQuestion inserts a question with its type-specific settings
+func createQuestion(ctx context.Context, exec execer, question *model.Question) error {
 	query := `
-		INSERT INTO questions (id, form_id, question_text, answer, type, position, required, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO questions (id, form_id, question_text, answer, type, position, required, display_logic, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		question.ID,
 		question.FormID,
 		question.QuestionText,
@@ -24,6 +29,7 @@ func (r *QuestionRepository) CreateQuestion(ctx context.Context, question *model
 		question.Type,
 		question.Position,
 		question.Required,
+		question.DisplayLogic,
 		question.CreatedAt,
 	)
 
@@ -31,23 +37,41 @@ func (r *QuestionRepository) CreateQuestion(ctx context.Context, question *model
 		return fmt.Errorf("failed to create question: %w", err)
 	}
 
-	// If it's a multiple choice question, create the multiple choice entry
-	if question.Type == model.QuestionTypeMultipleChoice {
-		if err := r.createMultipleChoiceQuestion(ctx, question); err != nil {
+	// Store the type-specific settings (choices, rating scale, ...)
+	return createQuestionSettings(ctx, exec, question)
+}
+
+// createQuestionSettings stores the type-specific settings of a question in its own table
+func createQuestionSettings(ctx context.Context, exec execer, question *model.Question) error {
+	switch question.Type {
+	case model.QuestionTypeMultipleChoice, model.QuestionTypeDropdown:
+		if err := createMultipleChoiceQuestion(ctx, exec, question); err != nil {
 			return fmt.Errorf("failed to create multiple choice question: %w", err)
 		}
+	case model.QuestionTypeRating:
+		if err := upsertRatingQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to create rating question: %w", err)
+		}
+	case model.QuestionTypeNumber:
+		if err := upsertNumberQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to create number question: %w", err)
+		}
+	case model.QuestionTypeLongText:
+		if err := upsertLongTextQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to create long text question: %w", err)
+		}
 	}
 
 	return nil
 }
 
 // createMultipleChoiceQuestion creates a multiple choice question entry
-func (r *QuestionRepository) createMultipleChoiceQuestion(ctx context.Context, question *model.Question) error {
+func createMultipleChoiceQuestion(ctx context.Context, exec execer, question *model.Question) error {
 	query := `
 		INSERT INTO multiple_choice_questions (id, question_id, choices, allow_multiple)
 		VALUES ($1, $2, $3, $4)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		uuid.New(),
 		question.ID,
 		question.Choices,
@@ -57,32 +81,75 @@ func (r *QuestionRepository) createMultipleChoiceQuestion(ctx context.Context, q
 	return err
 }
 
+// upsertRatingQuestion creates or replaces the rating scale of a question
+func upsertRatingQuestion(ctx context.Context, exec execer, question *model.Question) error {
+	query := `
+		INSERT INTO rating_questions (id, question_id, scale_min, scale_max, min_label, max_label)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (question_id) DO UPDATE
+		SET scale_min = EXCLUDED.scale_min, scale_max = EXCLUDED.scale_max,
+		    min_label = EXCLUDED.min_label, max_label = EXCLUDED.max_label`
+
+	_, err := exec.ExecContext(ctx, query,
+		uuid.New(),
+		question.ID,
+		question.ScaleMin,
+		question.ScaleMax,
+		question.MinLabel,
+		question.MaxLabel,
+	)
+
+	return err
+}
+
+// upsertNumberQuestion creates or replaces the bounds of a number question
+func upsertNumberQuestion(ctx context.Context, exec execer, question *model.Question) error {
+	query := `
+		INSERT INTO number_questions (id, question_id, min_value, max_value, integer_only)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (question_id) DO UPDATE
+		SET min_value = EXCLUDED.min_value, max_value = EXCLUDED.max_value, integer_only = EXCLUDED.integer_only`
+
+	_, err := exec.ExecContext(ctx, query,
+		uuid.New(),
+		question.ID,
+		question.MinValue,
+		question.MaxValue,
+		question.IntegerOnly,
+	)
+
+	return err
+}
+
+// upsertLongTextQuestion creates or replaces the length limit of a long text question
+func upsertLongTextQuestion(ctx context.Context, exec execer, question *model.Question) error {
+	query := `
+		INSERT INTO long_text_questions (id, question_id, max_length)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (question_id) DO UPDATE
+		SET max_length = EXCLUDED.max_length`
+
+	_, err := exec.ExecContext(ctx, query,
+		uuid.New(),
+		question.ID,
+		question.MaxLength,
+	)
+
+	return err
+}
+
 // GetQuestionByID retrieves a question by ID
 func (r *QuestionRepository) GetQuestionByID(ctx context.Context, questionID uuid.UUID) (*model.Question, error) {
 	query := `
-		SELECT q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at,
-		       mc.choices, mc.allow_multiple
+		SELECT ` + questionColumns + `
 		FROM questions q
-		LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id
+		` + questionSettingsJoins + `
 		WHERE q.id = $1`
 
 	question := &model.Question{}
-	var choices sql.NullString
-	var allowMultiple sql.NullBool
-
-	err := r.db.QueryRowContext(ctx, query, questionID).Scan(
-		&question.ID,
-		&question.FormID,
-		&question.QuestionText,
-		&question.Answer,
-		&question.Type,
-		&question.Position,
-		&question.Required,
-		&question.CreatedAt,
-		&choices,
-		&allowMultiple,
-	)
+	var settings questionSettings
 
+	err := r.db.QueryRowContext(ctx, query, questionID).Scan(settings.dest(question)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("question not found")
@@ -90,14 +157,8 @@ func (r *QuestionRepository) GetQuestionByID(ctx context.Context, questionID uui
 		return nil, fmt.Errorf("failed to get question: %w", err)
 	}
 
-	// Handle multiple choice fields
-	if question.Type == model.QuestionTypeMultipleChoice {
-		if choices.Valid {
-			if err := question.Choices.Scan([]byte(choices.String)); err != nil {
-				return nil, fmt.Errorf("failed to parse choices: %w", err)
-			}
-		}
-		question.AllowMultiple = allowMultiple.Bool
+	if err := settings.apply(question); err != nil {
+		return nil, err
 	}
 
 	return question, nil
@@ -106,10 +167,9 @@ func (r *QuestionRepository) GetQuestionByID(ctx context.Context, questionID uui
 // GetQuestionsByFormID retrieves all questions for a form
 func (r *QuestionRepository) GetQuestionsByFormID(ctx context.Context, formID uuid.UUID) ([]*model.Question, error) {
 	query := `
-		SELECT q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at,
-		       mc.choices, mc.allow_multiple
+		SELECT ` + questionColumns + `
 		FROM questions q
-		LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id
+		` + questionSettingsJoins + `
 		WHERE q.form_id = $1
 		ORDER BY q.position`
 
@@ -122,33 +182,14 @@ func (r *QuestionRepository) GetQuestionsByFormID(ctx context.Context, formID uu
 	var questions []*model.Question
 	for rows.Next() {
 		question := &model.Question{}
-		var choices sql.NullString
-		var allowMultiple sql.NullBool
-
-		err := rows.Scan(
-			&question.ID,
-			&question.FormID,
-			&question.QuestionText,
-			&question.Answer,
-			&question.Type,
-			&question.Position,
-			&question.Required,
-			&question.CreatedAt,
-			&choices,
-			&allowMultiple,
-		)
-		if err != nil {
+		var settings questionSettings
+
+		if err := rows.Scan(settings.dest(question)...); err != nil {
 			return nil, fmt.Errorf("failed to scan question: %w", err)
 		}
 
-		// Handle multiple choice fields
-		if question.Type == model.QuestionTypeMultipleChoice {
-			if choices.Valid {
-				if err := question.Choices.Scan([]byte(choices.String)); err != nil {
-					return nil, fmt.Errorf("failed to parse choices: %w", err)
-				}
-			}
-			question.AllowMultiple = allowMultiple.Bool
+		if err := settings.apply(question); err != nil {
+			return nil, err
 		}
 
 		questions = append(questions, question)
@@ -159,17 +200,23 @@ func (r *QuestionRepository) GetQuestionsByFormID(ctx context.Context, formID uu
 
 // UpdateQuestion updates an existing question
 func (r *QuestionRepository) UpdateQuestion(ctx context.Context, question *model.Question) error {
+	return updateQuestion(ctx, r.db, question)
+}
+
+// updateQuestion updates a question with its type-specific settings
+func updateQuestion(ctx context.Context, exec execer, question *model.Question) error {
 	query := `
 		UPDATE questions 
-		SET question_text = $1, answer = $2, type = $3, position = $4, required = $5
-		WHERE id = $6`
+		SET question_text = $1, answer = $2, type = $3, position = $4, required = $5, display_logic = $6
+		WHERE id = $7`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		question.QuestionText,
 		question.Answer,
 		question.Type,
 		question.Position,
 		question.Required,
+		question.DisplayLogic,
 		question.ID,
 	)
 
@@ -186,22 +233,35 @@ func (r *QuestionRepository) UpdateQuestion(ctx context.Context, question *model
 		return fmt.Errorf("question not found")
 	}
 
-	// Handle multiple choice questions
-	if question.Type == model.QuestionTypeMultipleChoice {
-		if err := r.updateMultipleChoiceQuestion(ctx, question); err != nil {
+	// Handle type-specific settings
+	switch question.Type {
+	case model.QuestionTypeMultipleChoice, model.QuestionTypeDropdown:
+		if err := updateMultipleChoiceQuestion(ctx, exec, question); err != nil {
 			return fmt.Errorf("failed to update multiple choice question: %w", err)
 		}
+	case model.QuestionTypeRating:
+		if err := upsertRatingQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to update rating question: %w", err)
+		}
+	case model.QuestionTypeNumber:
+		if err := upsertNumberQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to update number question: %w", err)
+		}
+	case model.QuestionTypeLongText:
+		if err := upsertLongTextQuestion(ctx, exec, question); err != nil {
+			return fmt.Errorf("failed to update long text question: %w", err)
+		}
 	}
 
 	return nil
 }
 
 // updateMultipleChoiceQuestion updates multiple choice question data
-func (r *QuestionRepository) updateMultipleChoiceQuestion(ctx context.Context, question *model.Question) error {
+func updateMultipleChoiceQuestion(ctx context.Context, exec execer, question *model.Question) error {
 	// First, check if multiple choice entry exists
 	var exists bool
 	checkQuery := `SELECT EXISTS(SELECT 1 FROM multiple_choice_questions WHERE question_id = $1)`
-	err := r.db.QueryRowContext(ctx, checkQuery, question.ID).Scan(&exists)
+	err := exec.QueryRowContext(ctx, checkQuery, question.ID).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check multiple choice existence: %w", err)
 	}
@@ -213,7 +273,7 @@ func (r *QuestionRepository) updateMultipleChoiceQuestion(ctx context.Context, q
 			SET choices = $1, allow_multiple = $2
 			WHERE question_id = $3`
 
-		_, err := r.db.ExecContext(ctx, updateQuery,
+		_, err := exec.ExecContext(ctx, updateQuery,
 			question.Choices,
 			question.AllowMultiple,
 			question.ID,
@@ -221,7 +281,7 @@ func (r *QuestionRepository) updateMultipleChoiceQuestion(ctx context.Context, q
 		return err
 	} else {
 		// Create new
-		return r.createMultipleChoiceQuestion(ctx, question)
+		return createMultipleChoiceQuestion(ctx, exec, question)
 	}
 }
 
@@ -313,34 +373,137 @@ func (r *QuestionRepository) CreateQuestionsInBatch(ctx context.Context, questio
 
 	// Prepare statements for reuse
 	qStmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO questions (id, form_id, question_text, type, position, required, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+		INSERT INTO questions (id, form_id, question_text, type, position, required, display_logic, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare question statement: %w", err)
 	}
 	defer qStmt.Close()
 
-	mcqStmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO multiple_choice_questions (id, question_id, choices, allow_multiple)
-		VALUES ($1, $2, $3, $4)`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare multiple choice question statement: %w", err)
-	}
-	defer mcqStmt.Close()
-
 	for _, q := range questions {
 		// Use the prepared statements within the transaction
-		if _, err := qStmt.ExecContext(ctx, q.ID, q.FormID, q.QuestionText, q.Type, q.Position, q.Required, q.CreatedAt); err != nil {
+		if _, err := qStmt.ExecContext(ctx, q.ID, q.FormID, q.QuestionText, q.Type, q.Position, q.Required, q.DisplayLogic, q.CreatedAt); err != nil {
 			return fmt.Errorf("failed to execute prepared statement for question %s: %w", q.ID, err)
 		}
 
-		if q.Type == model.QuestionTypeMultipleChoice {
-			if _, err := mcqStmt.ExecContext(ctx, uuid.New(), q.ID, q.Choices, q.AllowMultiple); err != nil {
-				return fmt.Errorf("failed to execute prepared statement for multiple choice question %s: %w", q.ID, err)
-			}
+		if err := createQuestionSettings(ctx, tx, q); err != nil {
+			return fmt.Errorf("failed to store settings for question %s: %w", q.ID, err)
 		}
 	}
 
 	// Commit
 	return tx.Commit()
 }
+
+// questionColumns selects a question together with the type-specific
+// settings joined in by questionSettingsJoins
+const questionColumns = `q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at, q.display_logic,
+		       ` + questionSettingsColumns
+
+// optionalQuestionColumns selects the columns of questionColumns when q is
+// outer joined and may be missing, with zero values in place of NULLs
+const optionalQuestionColumns = `q.id, q.form_id, COALESCE(q.question_text, ''), q.answer, COALESCE(q.type, ''), COALESCE(q.position, 0),
+		       COALESCE(q.required, FALSE), COALESCE(q.created_at, ffq.created_at), q.display_logic,
+		       ` + questionSettingsColumns
+
+const questionSettingsColumns = `mc.choices, mc.allow_multiple,
+		       rq.scale_min, rq.scale_max, rq.min_label, rq.max_label,
+		       nq.min_value, nq.max_value, nq.integer_only,
+		       lt.max_length`
+
+// questionSettingsJoins joins the per-type settings tables onto questions q
+const questionSettingsJoins = `LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id
+		LEFT JOIN rating_questions rq ON q.id = rq.question_id
+		LEFT JOIN number_questions nq ON q.id = nq.question_id
+		LEFT JOIN long_text_questions lt ON q.id = lt.question_id`
+
+// questionSettings holds the nullable columns read from the settings tables
+// and the question's display logic
+type questionSettings struct {
+	displayLogic  []byte
+	choices       sql.NullString
+	allowMultiple sql.NullBool
+	scaleMin      sql.NullInt32
+	scaleMax      sql.NullInt32
+	minLabel      sql.NullString
+	maxLabel      sql.NullString
+	minValue      sql.NullFloat64
+	maxValue      sql.NullFloat64
+	integerOnly   sql.NullBool
+	maxLength     sql.NullInt32
+}
+
+// dest returns the scan destinations matching questionColumns
+func (s *questionSettings) dest(question *model.Question) []interface{} {
+	return []interface{}{
+		&question.ID,
+		&question.FormID,
+		&question.QuestionText,
+		&question.Answer,
+		&question.Type,
+		&question.Position,
+		&question.Required,
+		&question.CreatedAt,
+		&s.displayLogic,
+		&s.choices,
+		&s.allowMultiple,
+		&s.scaleMin,
+		&s.scaleMax,
+		&s.minLabel,
+		&s.maxLabel,
+		&s.minValue,
+		&s.maxValue,
+		&s.integerOnly,
+		&s.maxLength,
+	}
+}
+
+// apply copies the settings relevant to the question's type onto the question
+func (s *questionSettings) apply(question *model.Question) error {
+	if len(s.displayLogic) > 0 {
+		question.DisplayLogic = &model.DisplayLogic{}
+		if err := question.DisplayLogic.Scan(s.displayLogic); err != nil {
+			return fmt.Errorf("failed to parse display logic: %w", err)
+		}
+	}
+
+	switch question.Type {
+	case model.QuestionTypeMultipleChoice, model.QuestionTypeDropdown:
+		if s.choices.Valid {
+			if err := question.Choices.Scan([]byte(s.choices.String)); err != nil {
+				return fmt.Errorf("failed to parse choices: %w", err)
+			}
+		}
+		question.AllowMultiple = s.allowMultiple.Bool
+	case model.QuestionTypeRating:
+		if s.scaleMin.Valid {
+			scaleMin := int(s.scaleMin.Int32)
+			question.ScaleMin = &scaleMin
+		}
+		if s.scaleMax.Valid {
+			scaleMax := int(s.scaleMax.Int32)
+			question.ScaleMax = &scaleMax
+		}
+		if s.minLabel.Valid {
+			question.MinLabel = &s.minLabel.String
+		}
+		if s.maxLabel.Valid {
+			question.MaxLabel = &s.maxLabel.String
+		}
+	case model.QuestionTypeNumber:
+		if s.minValue.Valid {
+			question.MinValue = &s.minValue.Float64
+		}
+		if s.maxValue.Valid {
+			question.MaxValue = &s.maxValue.Float64
+		}
+		question.IntegerOnly = s.integerOnly.Bool
+	case model.QuestionTypeLongText:
+		if s.maxLength.Valid {
+			maxLength := int(s.maxLength.Int32)
+			question.MaxLength = &maxLength
+		}
+	}
+
+	return nil
+}
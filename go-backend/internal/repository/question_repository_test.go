@@ -50,9 +50,9 @@ func (s *QuestionRepositorySuite) TestCreateQuestion_Basic() {
 		CreatedAt:    time.Now(),
 	}
 
-	query := `INSERT INTO questions (id, form_id, question_text, answer, type, position, required, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO questions (id, form_id, question_text, answer, type, position, required, display_logic, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 	s.mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(q.ID, q.FormID, q.QuestionText, q.Answer, q.Type, q.Position, q.Required, q.CreatedAt).
+		WithArgs(q.ID, q.FormID, q.QuestionText, q.Answer, q.Type, q.Position, q.Required, q.DisplayLogic, q.CreatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := s.repo.CreateQuestion(context.Background(), q)
@@ -73,7 +73,7 @@ func (s *QuestionRepositorySuite) TestCreateQuestion_MultipleChoice() {
 
 	insertQQuery := `INSERT INTO questions`
 	s.mock.ExpectExec(insertQQuery).
-		WithArgs(q.ID, q.FormID, q.QuestionText, q.Answer, q.Type, q.Position, q.Required, q.CreatedAt).
+		WithArgs(q.ID, q.FormID, q.QuestionText, q.Answer, q.Type, q.Position, q.Required, q.DisplayLogic, q.CreatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	insertMCQQuery := `INSERT INTO multiple_choice_questions (id, question_id, choices, allow_multiple) VALUES ($1, $2, $3, $4)`
@@ -96,6 +96,82 @@ func (s *QuestionRepositorySuite) TestCreateQuestion_MCQError() {
 	s.Contains(err.Error(), "failed to create multiple choice question")
 }
 
+func (s *QuestionRepositorySuite) TestCreateQuestion_Rating() {
+	scaleMin, scaleMax := 0, 10
+	q := &model.Question{
+		ID:           uuid.New(),
+		FormID:       uuid.New(),
+		QuestionText: "How likely are you to recommend us?",
+		Type:         model.QuestionTypeRating,
+		ScaleMin:     &scaleMin,
+		ScaleMax:     &scaleMax,
+		MinLabel:     stringPtr("Not likely"),
+		CreatedAt:    time.Now(),
+	}
+
+	s.mock.ExpectExec(`INSERT INTO questions`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO rating_questions \(id, question_id, scale_min, scale_max, min_label, max_label\)`).
+		WithArgs(sqlmock.AnyArg(), q.ID, q.ScaleMin, q.ScaleMax, q.MinLabel, q.MaxLabel).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := s.repo.CreateQuestion(context.Background(), q)
+	s.Require().NoError(err)
+}
+
+func (s *QuestionRepositorySuite) TestCreateQuestion_NumberError() {
+	q := &model.Question{ID: uuid.New(), Type: model.QuestionTypeNumber}
+
+	s.mock.ExpectExec(`INSERT INTO questions`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO number_questions`).WillReturnError(sql.ErrConnDone)
+
+	err := s.repo.CreateQuestion(context.Background(), q)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "failed to create number question")
+}
+
+func (s *QuestionRepositorySuite) TestGetQuestionsByFormID_TypeSettings() {
+	formID := uuid.New()
+	ratingID, numberID, dropdownID := uuid.New(), uuid.New(), uuid.New()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "form_id", "question_text", "answer", "type", "position", "required", "created_at", "display_logic",
+		"choices", "allow_multiple",
+		"scale_min", "scale_max", "min_label", "max_label",
+		"min_value", "max_value", "integer_only",
+		"max_length",
+	}).
+		AddRow(ratingID, formID, "Rate us", nil, "rating", 1, true, time.Now(), nil,
+			nil, nil, 1, 5, "Bad", "Great", nil, nil, nil, nil).
+		AddRow(numberID, formID, "Age", nil, "number", 2, false, time.Now(), nil,
+			nil, nil, nil, nil, nil, nil, 0.0, 120.0, true, nil).
+		AddRow(dropdownID, formID, "Country", nil, "dropdown", 3, false, time.Now(),
+			[]byte(`{"match":"all","conditions":[{"question_id":"`+numberID.String()+`","operator":"is_answered"}]}`),
+			[]byte(`["IN","US"]`), false, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	s.mock.ExpectQuery(`SELECT q.id, .* FROM questions q .* WHERE q.form_id = \$1 ORDER BY q.position`).
+		WithArgs(formID).
+		WillReturnRows(rows)
+
+	questions, err := s.repo.GetQuestionsByFormID(context.Background(), formID)
+	s.Require().NoError(err)
+	s.Require().Len(questions, 3)
+
+	s.Equal(1, *questions[0].ScaleMin)
+	s.Equal(5, *questions[0].ScaleMax)
+	s.Equal("Great", *questions[0].MaxLabel)
+
+	s.Equal(0.0, *questions[1].MinValue)
+	s.Equal(120.0, *questions[1].MaxValue)
+	s.True(questions[1].IntegerOnly)
+
+	s.Equal(model.JSONStringArray{"IN", "US"}, questions[2].Choices)
+
+	s.Nil(questions[0].DisplayLogic)
+	s.Require().NotNil(questions[2].DisplayLogic)
+	s.Equal(model.LogicMatchAll, questions[2].DisplayLogic.Match)
+	s.Equal(numberID, questions[2].DisplayLogic.Conditions[0].QuestionID)
+}
+
 func (s *QuestionRepositorySuite) TestUpdateQuestion_CreateMCQonUpdate() {
 	q := &model.Question{ID: uuid.New(), Type: model.QuestionTypeMultipleChoice}
 
@@ -123,7 +199,7 @@ func (s *QuestionRepositorySuite) TestUpdateQuestion_UpdateExistingMCQ() {
 
 func (s *QuestionRepositorySuite) TestGetQuestionByID_NotFound() {
 	id := uuid.New()
-	query := `SELECT q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at, mc.choices, mc.allow_multiple FROM questions q LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id WHERE q.id = $1`
+	query := `SELECT q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at, q.display_logic, mc.choices, mc.allow_multiple, rq.scale_min, rq.scale_max, rq.min_label, rq.max_label, nq.min_value, nq.max_value, nq.integer_only, lt.max_length FROM questions q LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id LEFT JOIN rating_questions rq ON q.id = rq.question_id LEFT JOIN number_questions nq ON q.id = nq.question_id LEFT JOIN long_text_questions lt ON q.id = lt.question_id WHERE q.id = $1`
 	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(id).WillReturnError(sql.ErrNoRows)
 
 	_, err := s.repo.GetQuestionByID(context.Background(), id)
@@ -163,15 +239,14 @@ func (s *QuestionRepositorySuite) TestCreateQuestionsInBatch() {
 
 	s.mock.ExpectBegin()
 
-	qStmtSQL := `INSERT INTO questions (id, form_id, question_text, type, position, required, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	qStmtSQL := `INSERT INTO questions (id, form_id, question_text, type, position, required, display_logic, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	mcqStmtSQL := `INSERT INTO multiple_choice_questions (id, question_id, choices, allow_multiple) VALUES ($1, $2, $3, $4)`
 
 	s.mock.ExpectPrepare(regexp.QuoteMeta(qStmtSQL))
-	s.mock.ExpectPrepare(regexp.QuoteMeta(mcqStmtSQL))
 
 	for _, q := range questions {
 		s.mock.ExpectExec(regexp.QuoteMeta(qStmtSQL)).
-			WithArgs(q.ID, q.FormID, q.QuestionText, q.Type, q.Position, q.Required, q.CreatedAt).
+			WithArgs(q.ID, q.FormID, q.QuestionText, q.Type, q.Position, q.Required, q.DisplayLogic, q.CreatedAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		if q.Type == model.QuestionTypeMultipleChoice {
@@ -195,7 +270,6 @@ func (s *QuestionRepositorySuite) TestCreateQuestionsInBatch_TransactionFailure(
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectPrepare(`INSERT INTO questions`)
-	s.mock.ExpectPrepare(`INSERT INTO multiple_choice_questions`)
 
 	// First question succeeds
 	s.mock.ExpectExec(`INSERT INTO questions`).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const refreshTokenColumns = `id, family_id, user_id, token_hash, expires_at, used_at, revoked_at, created_at`
+
+// CreateRefreshToken creates a refresh token
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	return insertRefreshToken(ctx, r.db, token)
+}
+
+// GetRefreshTokenByHash retrieves the token that hashes to tokenHash
+func (r *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+
+	var token model.RefreshToken
+	if err := r.db.GetContext(ctx, &token, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken marks current as used and stores next in a single
+// transaction. Only one of several concurrent rotations of the same token
+// succeeds; the others get "refresh token already used".
+func (r *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, current, next *model.RefreshToken, now time.Time) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		query := `UPDATE refresh_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`
+
+		result, err := tx.ExecContext(ctx, query, current.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to use refresh token: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("refresh token already used")
+		}
+
+		return insertRefreshToken(ctx, tx, next)
+	})
+}
+
+// RevokeRefreshTokenFamily revokes every token of a family that has not been
+// revoked yet
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, familyID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user that has not
+// been revoked yet
+func (r *RefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// insertRefreshToken inserts a refresh token inside or outside a transaction
+func insertRefreshToken(ctx context.Context, ex execer, token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := ex.ExecContext(ctx, query,
+		token.ID,
+		token.FamilyID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
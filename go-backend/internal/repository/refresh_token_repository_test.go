@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type RefreshTokenRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *RefreshTokenRepository
+}
+
+func (s *RefreshTokenRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &RefreshTokenRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *RefreshTokenRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositorySuite))
+}
+
+const (
+	insertRefreshTokenQuery = `INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	useRefreshTokenQuery    = `UPDATE refresh_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`
+)
+
+func (s *RefreshTokenRepositorySuite) TestGetRefreshTokenByHash_NotFound() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM refresh_tokens WHERE token_hash = $1`)).WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	token, err := s.repo.GetRefreshTokenByHash(context.Background(), "hash")
+	s.Require().Error(err)
+	s.Nil(token)
+	s.Equal("refresh token not found", err.Error())
+}
+
+func (s *RefreshTokenRepositorySuite) TestRotateRefreshToken_Success() {
+	now := time.Now()
+	current := model.NewRefreshToken(uuid.New(), uuid.New(), "old", now.Add(-time.Hour), 24*time.Hour)
+	next := model.NewRefreshToken(current.UserID, current.FamilyID, "new", now, 24*time.Hour)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(useRefreshTokenQuery)).WithArgs(current.ID, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(insertRefreshTokenQuery)).
+		WithArgs(next.ID, current.FamilyID, current.UserID, "new", next.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.RotateRefreshToken(context.Background(), current, next, now)
+	s.Require().NoError(err)
+}
+
+func (s *RefreshTokenRepositorySuite) TestRotateRefreshToken_AlreadyUsed() {
+	now := time.Now()
+	current := model.NewRefreshToken(uuid.New(), uuid.New(), "old", now.Add(-time.Hour), 24*time.Hour)
+	next := model.NewRefreshToken(current.UserID, current.FamilyID, "new", now, 24*time.Hour)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(useRefreshTokenQuery)).WithArgs(current.ID, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.RotateRefreshToken(context.Background(), current, next, now)
+	s.Require().Error(err)
+	s.Equal("refresh token already used", err.Error())
+}
+
+func (s *RefreshTokenRepositorySuite) TestRevokeRefreshTokenFamily_Success() {
+	familyID := uuid.New()
+	now := time.Now()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`)).
+		WithArgs(familyID, now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := s.repo.RevokeRefreshTokenFamily(context.Background(), familyID, now)
+	s.Require().NoError(err)
+}
+
+func (s *RefreshTokenRepositorySuite) TestRevokeUserRefreshTokens_Success() {
+	userID := uuid.New()
+	now := time.Now()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`)).
+		WithArgs(userID, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := s.repo.RevokeUserRefreshTokens(context.Background(), userID, now)
+	s.Require().NoError(err)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/ayan-sh03/anoq/internal/db"
 	"github.com/ayan-sh03/anoq/internal/model"
@@ -11,6 +13,13 @@ import (
 //go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_user_repository.go -package=mocks . UserRepo
 //go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_form_repository.go -package=mocks . FormRepo
 //go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_response_repository.go -package=mocks . ResponseRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_question_repository.go -package=mocks . QuestionRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_webhook_repository.go -package=mocks . WebhookRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_template_repository.go -package=mocks . TemplateRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_version_repository.go -package=mocks . VersionRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_workspace_repository.go -package=mocks . WorkspaceRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_api_key_repository.go -package=mocks . APIKeyRepo
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_refresh_token_repository.go -package=mocks . RefreshTokenRepo
 
 type UserRepo interface {
 	CreateUser(ctx context.Context, user *model.User) error
@@ -19,29 +28,120 @@ type UserRepo interface {
 	UpdateUser(ctx context.Context, user *model.User) error
 	HashPassword(password string) (string, error)
 	CheckPassword(password, hash string) bool
-	CreateSession(ctx context.Context, userID uuid.UUID) (*model.UserSession, error)
+	CreateSession(ctx context.Context, userID uuid.UUID, client model.SessionClient, ttl time.Duration) (*model.UserSession, error)
 	GetSessionByToken(ctx context.Context, token string) (*model.UserSession, error)
+	TouchSession(ctx context.Context, id uuid.UUID, now time.Time, ttl time.Duration) error
+	ListSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*model.UserSession, error)
 	DeleteSession(ctx context.Context, token string) error
+	DeleteUserSession(ctx context.Context, userID, id uuid.UUID) error
+	DeleteUserSessions(ctx context.Context, userID uuid.UUID) error
 	CleanupExpiredSessions(ctx context.Context) error
 }
 
 type FormRepo interface {
 	CreateForm(ctx context.Context, form *model.Form) error
+	CreateFormWithQuestions(ctx context.Context, form *model.Form, questions []*model.Question) error
 	GetFormByID(ctx context.Context, id uuid.UUID) (*model.Form, error)
 	GetFormBySlug(ctx context.Context, slug string) (*model.Form, error)
-	ListFormsByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Form, error)
+	ListFormsByUserID(ctx context.Context, userID uuid.UUID, page model.PageRequest) ([]*model.Form, *model.Cursor, error)
+	ListFormsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID, page model.PageRequest) ([]*model.Form, *model.Cursor, error)
 	UpdateForm(ctx context.Context, form *model.Form) error
+	UpdateFormWithQuestions(ctx context.Context, form *model.Form, changes *model.QuestionChanges) error
 	DeleteForm(ctx context.Context, id uuid.UUID) error
 	UpdateFormStatus(ctx context.Context, id uuid.UUID, status string) error
+	ApplyDueSchedules(ctx context.Context, now time.Time) ([]*model.Form, error)
 	GetDashboardStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error)
 }
 
 type ResponseRepo interface {
-	CreateResponse(ctx context.Context, response *model.FilledForm, answers []model.CreateAnswerRequest) error
+	CreateResponse(ctx context.Context, response *model.FilledForm, answers []model.CreateAnswerRequest) (bool, error)
+	CountResponsesByFormID(ctx context.Context, formID uuid.UUID) (int, error)
 	GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error)
-	GetResponsesByFormID(ctx context.Context, formID uuid.UUID) ([]*model.FilledForm, error)
-	GetResponsesListByFormID(ctx context.Context, formID uuid.UUID) ([]*model.FilledForm, error)
+	GetResponsesByFormID(ctx context.Context, formID uuid.UUID, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error)
+	GetResponsesListByFormID(ctx context.Context, formID uuid.UUID, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error)
+	SearchResponses(ctx context.Context, formID uuid.UUID, search string, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error)
 	GetFormSubmissionStats(ctx context.Context, formID uuid.UUID) (*model.FormSubmissionStats, error)
+	StreamResponsesByFormID(ctx context.Context, formID uuid.UUID, fn func(*model.FilledForm) error) error
+	GetFormAnalytics(ctx context.Context, formID uuid.UUID, interval model.AnalyticsInterval) (*model.FormAnalytics, error)
+}
+
+type QuestionRepo interface {
+	CreateQuestion(ctx context.Context, question *model.Question) error
+	GetQuestionByID(ctx context.Context, questionID uuid.UUID) (*model.Question, error)
+	GetQuestionsByFormID(ctx context.Context, formID uuid.UUID) ([]*model.Question, error)
+	UpdateQuestion(ctx context.Context, question *model.Question) error
+	DeleteQuestion(ctx context.Context, questionID uuid.UUID) error
+	DeleteQuestionsByFormID(ctx context.Context, formID uuid.UUID) error
+	CreateQuestionsInBatch(ctx context.Context, questions []*model.Question) error
+}
+
+type WebhookRepo interface {
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) error
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+	ListWebhooksByFormID(ctx context.Context, formID uuid.UUID) ([]*model.Webhook, error)
+	ListActiveWebhooks(ctx context.Context, formID uuid.UUID, event model.WebhookEvent) ([]*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *model.Webhook) error
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	ListDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	UpdateDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+type TemplateRepo interface {
+	CreateTemplate(ctx context.Context, template *model.FormTemplate) error
+	GetTemplateByID(ctx context.Context, id uuid.UUID) (*model.FormTemplate, error)
+	ListTemplates(ctx context.Context, userID uuid.UUID, scope model.TemplateScope, page model.PageRequest) ([]*model.FormTemplate, *model.Cursor, error)
+	UpdateTemplate(ctx context.Context, template *model.FormTemplate) error
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+}
+
+type VersionRepo interface {
+	EnsureVersion(ctx context.Context, formID uuid.UUID, definition *model.FormDefinition) (*model.FormVersion, error)
+	GetVersion(ctx context.Context, formID uuid.UUID, version int) (*model.FormVersion, error)
+	GetLatestVersion(ctx context.Context, formID uuid.UUID) (*model.FormVersion, error)
+	ListVersions(ctx context.Context, formID uuid.UUID) ([]*model.FormVersionSummary, error)
+}
+
+type WorkspaceRepo interface {
+	CreateWorkspace(ctx context.Context, workspace *model.Workspace, adminID uuid.UUID) error
+	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error)
+	ListWorkspacesByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Workspace, error)
+	UpdateWorkspace(ctx context.Context, workspace *model.Workspace) error
+	DeleteWorkspace(ctx context.Context, id uuid.UUID) error
+	GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]*model.WorkspaceMember, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role model.WorkspaceRole, now time.Time) error
+	RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*model.WorkspaceInvitation, error)
+	ListPendingInvitations(ctx context.Context, workspaceID uuid.UUID) ([]*model.WorkspaceInvitation, error)
+	DeleteInvitation(ctx context.Context, workspaceID, id uuid.UUID) error
+	AcceptInvitation(ctx context.Context, invitation *model.WorkspaceInvitation, userID uuid.UUID, now time.Time) (*model.WorkspaceMember, error)
+}
+
+type APIKeyRepo interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	ListAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id uuid.UUID, now time.Time) error
+	TouchAPIKey(ctx context.Context, id uuid.UUID, now time.Time) error
+}
+
+type RefreshTokenRepo interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, current, next *model.RefreshToken, now time.Time) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+// execer is implemented by both the database and transactions so helpers
+// can write inside or outside a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // UserRepository handles user data operations
@@ -64,6 +164,36 @@ type ResponseRepository struct {
 	db *db.DB
 }
 
+// WebhookRepository handles webhook and delivery data operations
+type WebhookRepository struct {
+	db *db.DB
+}
+
+// TemplateRepository handles form template data operations
+type TemplateRepository struct {
+	db *db.DB
+}
+
+// VersionRepository handles form version data operations
+type VersionRepository struct {
+	db *db.DB
+}
+
+// WorkspaceRepository handles workspace, membership and invitation data operations
+type WorkspaceRepository struct {
+	db *db.DB
+}
+
+// APIKeyRepository handles API key data operations
+type APIKeyRepository struct {
+	db *db.DB
+}
+
+// RefreshTokenRepository handles refresh token data operations
+type RefreshTokenRepository struct {
+	db *db.DB
+}
+
 // NewUserRepository creates a new user repository
 func NewUserRepository(database *db.DB) *UserRepository {
 	return &UserRepository{
@@ -91,3 +221,45 @@ func NewResponseRepository(database *db.DB) *ResponseRepository {
 		db: database,
 	}
 }
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(database *db.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: database,
+	}
+}
+
+// NewTemplateRepository creates a new template repository
+func NewTemplateRepository(database *db.DB) *TemplateRepository {
+	return &TemplateRepository{
+		db: database,
+	}
+}
+
+// NewVersionRepository creates a new form version repository
+func NewVersionRepository(database *db.DB) *VersionRepository {
+	return &VersionRepository{
+		db: database,
+	}
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(database *db.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		db: database,
+	}
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(database *db.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: database,
+	}
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(database *db.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: database,
+	}
+}
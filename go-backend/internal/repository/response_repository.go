@@ -4,25 +4,82 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/ayan-sh03/anoq/internal/model"
 )
 
-// CreateResponse creates a new response with all individual question answers
-func (r *ResponseRepository) CreateResponse(ctx context.Context, response *model.FilledForm, answers []model.CreateAnswerRequest) error {
+// CreateResponse creates a new response with all individual question answers.
+// When the form has a response limit the submission is rejected once the
+// limit is reached, and the form is closed by the submission that reaches it;
+// formClosed reports whether this submission closed the form.
+func (r *ResponseRepository) CreateResponse(ctx context.Context, response *model.FilledForm, answers []model.CreateAnswerRequest) (formClosed bool, err error) {
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return false, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	// Drop the identifiers the form does not keep, whatever the caller set
+	var anonymity model.AnonymityLevel
+	anonymityQuery := `SELECT anonymity_level FROM forms WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, anonymityQuery, response.FormID).Scan(&anonymity); err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("form not found")
+		}
+		return false, fmt.Errorf("failed to get form anonymity level: %w", err)
+	}
+	response.ApplyAnonymity(anonymity)
+
+	// Lock forms with a response limit or a duplicate policy so concurrent
+	// submissions are checked one at a time
+	var (
+		maxResponses    sql.NullInt64
+		duplicatePolicy model.DuplicatePolicy
+		windowSeconds   sql.NullInt64
+	)
+	lockQuery := `
+		SELECT max_responses, duplicate_policy, duplicate_window_seconds
+		FROM forms
+		WHERE id = $1 AND (max_responses IS NOT NULL OR duplicate_policy <> 'none')
+		FOR UPDATE`
+	err = tx.QueryRowContext(ctx, lockQuery, response.FormID).Scan(&maxResponses, &duplicatePolicy, &windowSeconds)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to lock form: %w", err)
+	}
+
+	var count int
+	if maxResponses.Valid {
+		countQuery := `SELECT COUNT(*) FROM filled_forms WHERE form_id = $1`
+		if err := tx.QueryRowContext(ctx, countQuery, response.FormID).Scan(&count); err != nil {
+			return false, fmt.Errorf("failed to count responses: %w", err)
+		}
+		if count >= int(maxResponses.Int64) {
+			return false, fmt.Errorf("form has reached its response limit")
+		}
+	}
+
+	if duplicatePolicy != "" && duplicatePolicy != model.DuplicatePolicyNone {
+		var since *time.Time
+		if windowSeconds.Valid {
+			start := response.CreatedAt.Add(-time.Duration(windowSeconds.Int64) * time.Second)
+			since = &start
+		}
+		if err := checkDuplicate(ctx, tx, response, duplicatePolicy, since); err != nil {
+			return false, err
+		}
+	}
+
 	// Insert filled form
 	query := `
-		INSERT INTO filled_forms (id, form_id, name, email, user_ip, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		INSERT INTO filled_forms (id, form_id, name, email, user_ip, created_at, updated_at, started_at, completion_time_ms, flagged_fast,
+		                          email_hash, ip_hash, browser_hash, edit_token_hash, form_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 
 	_, err = tx.ExecContext(ctx, query,
 		response.ID,
@@ -32,10 +89,18 @@ func (r *ResponseRepository) CreateResponse(ctx context.Context, response *model
 		response.UserIP,
 		response.CreatedAt,
 		response.UpdatedAt,
+		response.StartedAt,
+		response.CompletionTimeMs,
+		response.FlaggedFast,
+		response.EmailHash,
+		response.IPHash,
+		response.BrowserHash,
+		response.EditTokenHash,
+		response.FormVersion,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to create response: %w", err)
+		return false, fmt.Errorf("failed to create response: %w", err)
 	}
 
 	// Insert individual question answers
@@ -58,28 +123,92 @@ func (r *ResponseRepository) CreateResponse(ctx context.Context, response *model
 			)
 
 			if err != nil {
-				return fmt.Errorf("failed to create answer for question %s: %w", answerReq.QuestionID, err)
+				return false, fmt.Errorf("failed to create answer for question %s: %w", answerReq.QuestionID, err)
 			}
+
+			response.Answers = append(response.Answers, *answer)
 		}
 	}
 
+	// Close the form with the submission that reaches the limit
+	if maxResponses.Valid && count+1 >= int(maxResponses.Int64) {
+		closeQuery := `UPDATE forms SET status = 'closed', updated_at = $2 WHERE id = $1 AND status = 'open'`
+		result, err := tx.ExecContext(ctx, closeQuery, response.FormID, response.CreatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to close form: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		formClosed = rowsAffected > 0
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return formClosed, nil
 }
 
-// GetResponseByID retrieves a response by ID with all its answers
-func (r *ResponseRepository) GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error) {
+// duplicateColumns maps duplicate policies to the hash column they compare
+var duplicateColumns = map[model.DuplicatePolicy]string{
+	model.DuplicatePolicyEmail:   "email_hash",
+	model.DuplicatePolicyIP:      "ip_hash",
+	model.DuplicatePolicyBrowser: "browser_hash",
+}
+
+// checkDuplicate returns an error when the respondent already answered the
+// form, since the given time when set
+func checkDuplicate(ctx context.Context, tx *sql.Tx, response *model.FilledForm, policy model.DuplicatePolicy, since *time.Time) error {
+	column, ok := duplicateColumns[policy]
+	if !ok {
+		return fmt.Errorf("unknown duplicate policy %s", policy)
+	}
+
+	hash := map[model.DuplicatePolicy]*string{
+		model.DuplicatePolicyEmail:   response.EmailHash,
+		model.DuplicatePolicyIP:      response.IPHash,
+		model.DuplicatePolicyBrowser: response.BrowserHash,
+	}[policy]
+	if hash == nil {
+		return fmt.Errorf("response has no %s identifier", policy)
+	}
+
 	query := `
-		SELECT id, form_id, name, email, user_ip, created_at, updated_at
-		FROM filled_forms
-		WHERE id = $1`
+		SELECT EXISTS (
+			SELECT 1 FROM filled_forms
+			WHERE form_id = $1 AND ` + column + ` = $2 AND ($3::timestamptz IS NULL OR created_at > $3)
+		)`
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, response.FormID, *hash, since).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check for duplicate responses: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("duplicate response")
+	}
 
-	var response model.FilledForm
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	return nil
+}
+
+// CountResponsesByFormID returns the number of responses of a form
+func (r *ResponseRepository) CountResponsesByFormID(ctx context.Context, formID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM filled_forms WHERE form_id = $1`, formID); err != nil {
+		return 0, fmt.Errorf("failed to count responses: %w", err)
+	}
+	return count, nil
+}
+
+const responseColumns = `id, form_id, name, email, user_ip, created_at, updated_at, started_at, completion_time_ms, flagged_fast,
+	spam, reviewed, starred, tags, form_version`
+
+// responseFields returns the scan destinations of responseColumns
+func responseFields(response *model.FilledForm) []interface{} {
+	return []interface{}{
 		&response.ID,
 		&response.FormID,
 		&response.Name,
@@ -87,7 +216,23 @@ func (r *ResponseRepository) GetResponseByID(ctx context.Context, id uuid.UUID)
 		&response.UserIP,
 		&response.CreatedAt,
 		&response.UpdatedAt,
-	)
+		&response.StartedAt,
+		&response.CompletionTimeMs,
+		&response.FlaggedFast,
+		&response.Spam,
+		&response.Reviewed,
+		&response.Starred,
+		&response.Tags,
+		&response.FormVersion,
+	}
+}
+
+// GetResponseByID retrieves a response by ID with all its answers
+func (r *ResponseRepository) GetResponseByID(ctx context.Context, id uuid.UUID) (*model.FilledForm, error) {
+	query := `SELECT ` + responseColumns + `, edit_token_hash FROM filled_forms WHERE id = $1`
+
+	var response model.FilledForm
+	err := r.db.QueryRowContext(ctx, query, id).Scan(append(responseFields(&response), &response.EditTokenHash)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -106,66 +251,217 @@ func (r *ResponseRepository) GetResponseByID(ctx context.Context, id uuid.UUID)
 	return &response, nil
 }
 
-// GetResponsesByFormID retrieves all responses for a form with their answers
-func (r *ResponseRepository) GetResponsesByFormID(ctx context.Context, formID uuid.UUID) ([]*model.FilledForm, error) {
+// responseFilterConditions returns the SQL conditions of the filter, to be
+// appended to a WHERE clause, with args extended by their parameters
+func responseFilterConditions(filter model.ResponseFilter, args []interface{}) (string, []interface{}) {
+	var conditions strings.Builder
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		fmt.Fprintf(&conditions, format, len(args))
+	}
+
+	if filter.Spam != nil {
+		addCondition(" AND spam = $%d", *filter.Spam)
+	}
+	if filter.Reviewed != nil {
+		addCondition(" AND reviewed = $%d", *filter.Reviewed)
+	}
+	if filter.Starred != nil {
+		addCondition(" AND starred = $%d", *filter.Starred)
+	}
+	if filter.Tag != "" {
+		addCondition(" AND tags ? $%d", filter.Tag)
+	}
+	if filter.SubmittedFrom != nil {
+		addCondition(" AND created_at >= $%d", *filter.SubmittedFrom)
+	}
+	if filter.SubmittedTo != nil {
+		addCondition(" AND created_at < $%d", *filter.SubmittedTo)
+	}
+
+	for _, answer := range filter.Answers {
+		args = append(args, answer.QuestionID)
+		fmt.Fprintf(&conditions, " AND EXISTS (SELECT 1 FROM filled_form_questions ffq WHERE ffq.filled_form_id = filled_forms.id AND ffq.question_id = $%d", len(args))
+		if answer.Equals != "" {
+			args = append(args, answer.Equals)
+			fmt.Fprintf(&conditions, " AND (ffq.answer = $%d OR ffq.selected_choices ? $%d)", len(args), len(args))
+		}
+		if answer.Contains != "" {
+			args = append(args, answer.Contains)
+			fmt.Fprintf(&conditions, " AND strpos(lower(ffq.answer), lower($%d)) > 0", len(args))
+		}
+		conditions.WriteString(")")
+	}
+
+	if filter.Search != "" {
+		args = append(args, filter.Search)
+		fmt.Fprintf(&conditions, " AND EXISTS (SELECT 1 FROM filled_form_questions ffq WHERE ffq.filled_form_id = filled_forms.id AND ffq.answer_tsv @@ "+searchQuery+")", len(args))
+	}
+
+	return conditions.String(), args
+}
+
+// searchQuery parses a search parameter with the text search configuration of
+// filled_form_questions.answer_tsv, so the GIN index on it is used
+const searchQuery = "websearch_to_tsquery('english', $%d)"
+
+// responseSortColumns maps the supported sorts of responses to their column
+var responseSortColumns = map[model.ResponseSort]string{
+	model.ResponseSortCreatedAt: "created_at",
+	model.ResponseSortUpdatedAt: "updated_at",
+}
+
+// listResponses retrieves one page of the responses of a form matching the
+// filter, without their answers. The returned cursor is nil on the last page.
+func (r *ResponseRepository) listResponses(ctx context.Context, formID uuid.UUID, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error) {
+	column, ok := responseSortColumns[model.ResponseSort(page.Sort)]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported sort %s", page.Sort)
+	}
+
+	conditions, args := responseFilterConditions(filter, []interface{}{formID})
+	pageCondition, orderLimit, args := pageClauses(page, column, "id", args)
 	query := `
-		SELECT id, form_id, name, email, user_ip, created_at, updated_at
+		SELECT ` + responseColumns + `
 		FROM filled_forms
-		WHERE form_id = $1
-		ORDER BY created_at DESC`
+		WHERE form_id = $1` + conditions + pageCondition + orderLimit
 
-	rows, err := r.db.QueryContext(ctx, query, formID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get responses: %w", err)
+		return nil, nil, fmt.Errorf("failed to get responses: %w", err)
 	}
 	defer rows.Close()
 
 	var responses []*model.FilledForm
 	for rows.Next() {
 		var response model.FilledForm
-		err := rows.Scan(
-			&response.ID,
-			&response.FormID,
-			&response.Name,
-			&response.Email,
-			&response.UserIP,
-			&response.CreatedAt,
-			&response.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan response: %w", err)
+		if err := rows.Scan(responseFields(&response)...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan response: %w", err)
 		}
 
+		responses = append(responses, &response)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to get responses: %w", err)
+	}
+
+	if len(responses) <= page.Limit {
+		return responses, nil, nil
+	}
+
+	responses = responses[:page.Limit]
+	last := responses[len(responses)-1]
+	value := last.CreatedAt
+	if column == "updated_at" {
+		value = last.UpdatedAt
+	}
+
+	return responses, page.NextCursor(value, last.ID), nil
+}
+
+// GetResponsesByFormID retrieves one page of the responses of a form matching
+// the filter with their answers. The returned cursor is nil on the last page.
+func (r *ResponseRepository) GetResponsesByFormID(ctx context.Context, formID uuid.UUID, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error) {
+	responses, next, err := r.listResponses(ctx, formID, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, response := range responses {
 		// Get answers for this response
 		answers, err := r.getAnswersByFilledFormID(ctx, response.ID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get answers for response %s: %w", response.ID, err)
+			return nil, nil, fmt.Errorf("failed to get answers for response %s: %w", response.ID, err)
 		}
 		response.Answers = answers
+	}
 
-		responses = append(responses, &response)
+	return responses, next, nil
+}
+
+// GetResponsesListByFormID retrieves one page of the responses of a form
+// matching the filter without answers (for listing). The returned cursor is
+// nil on the last page.
+func (r *ResponseRepository) GetResponsesListByFormID(ctx context.Context, formID uuid.UUID, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error) {
+	return r.listResponses(ctx, formID, filter, page)
+}
+
+// SearchResponses retrieves one page of the responses of a form matching the
+// filter whose text answers match the full-text search, with their answers
+// and a highlighted snippet of every matching answer. The returned cursor is
+// nil on the last page.
+func (r *ResponseRepository) SearchResponses(ctx context.Context, formID uuid.UUID, search string, filter model.ResponseFilter, page model.PageRequest) ([]*model.FilledForm, *model.Cursor, error) {
+	filter.Search = search
+	responses, next, err := r.GetResponsesByFormID(ctx, formID, filter, page)
+	if err != nil || len(responses) == 0 {
+		return responses, next, err
+	}
+
+	ids := make(pq.StringArray, len(responses))
+	byID := make(map[uuid.UUID]*model.FilledForm, len(responses))
+	for i, response := range responses {
+		ids[i] = response.ID.String()
+		byID[response.ID] = response
 	}
 
-	return responses, nil
+	tsquery := fmt.Sprintf(searchQuery, 2)
+	query := `
+		SELECT filled_form_id, question_id, ts_headline('english', answer, ` + tsquery + `, $3)
+		FROM filled_form_questions
+		WHERE filled_form_id = ANY($1::uuid[]) AND answer_tsv @@ ` + tsquery + `
+		ORDER BY created_at`
+	options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=20, MinWords=5", model.HighlightStart, model.HighlightStop)
+
+	rows, err := r.db.QueryContext(ctx, query, ids, search, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to highlight answers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var responseID, questionID uuid.UUID
+		var snippet string
+		if err := rows.Scan(&responseID, &questionID, &snippet); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan highlight: %w", err)
+		}
+		if response, ok := byID[responseID]; ok {
+			response.Highlights = append(response.Highlights, model.NewAnswerHighlight(questionID, snippet))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to highlight answers: %w", err)
+	}
+
+	return responses, next, nil
 }
 
-// GetResponsesListByFormID retrieves responses for a form without answers (for listing)
-func (r *ResponseRepository) GetResponsesListByFormID(ctx context.Context, formID uuid.UUID) ([]*model.FilledForm, error) {
+// StreamResponsesByFormID reads every response of a form with its answers from a
+// single cursor, in submission order, calling fn once per response. Unlike
+// GetResponsesByFormID it never holds more than one response in memory.
+func (r *ResponseRepository) StreamResponsesByFormID(ctx context.Context, formID uuid.UUID, fn func(*model.FilledForm) error) error {
 	query := `
-		SELECT id, form_id, name, email, user_ip, created_at, updated_at
-		FROM filled_forms
-		WHERE form_id = $1
-		ORDER BY created_at DESC`
+		SELECT ff.id, ff.form_id, ff.name, ff.email, ff.user_ip, ff.created_at, ff.updated_at,
+		       ff.started_at, ff.completion_time_ms, ff.flagged_fast,
+		       ffq.id, ffq.question_id, ffq.answer, ffq.selected_choices, ffq.created_at
+		FROM filled_forms ff
+		LEFT JOIN filled_form_questions ffq ON ffq.filled_form_id = ff.id
+		WHERE ff.form_id = $1
+		ORDER BY ff.created_at, ff.id`
 
 	rows, err := r.db.QueryContext(ctx, query, formID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get responses: %w", err)
+		return fmt.Errorf("failed to get responses: %w", err)
 	}
 	defer rows.Close()
 
-	var responses []*model.FilledForm
+	var current *model.FilledForm
 	for rows.Next() {
 		var response model.FilledForm
+		var answerID, questionID uuid.NullUUID
+		var answer sql.NullString
+		var selectedChoices []byte
+		var answerCreatedAt sql.NullTime
+
 		err := rows.Scan(
 			&response.ID,
 			&response.FormID,
@@ -174,28 +470,74 @@ func (r *ResponseRepository) GetResponsesListByFormID(ctx context.Context, formI
 			&response.UserIP,
 			&response.CreatedAt,
 			&response.UpdatedAt,
+			&response.StartedAt,
+			&response.CompletionTimeMs,
+			&response.FlaggedFast,
+			&answerID,
+			&questionID,
+			&answer,
+			&selectedChoices,
+			&answerCreatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan response: %w", err)
+			return fmt.Errorf("failed to scan response: %w", err)
 		}
 
-		responses = append(responses, &response)
+		// Rows of one response are adjacent, so a new ID means the previous one is complete
+		if current == nil || current.ID != response.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			current = &response
+		}
+
+		if !answerID.Valid {
+			continue
+		}
+
+		filledQuestion := model.FilledFormQuestion{
+			ID:           answerID.UUID,
+			FilledFormID: current.ID,
+			QuestionID:   questionID.UUID,
+			CreatedAt:    answerCreatedAt.Time,
+		}
+		if answer.Valid {
+			filledQuestion.Answer = &answer.String
+		}
+		if len(selectedChoices) > 0 {
+			if err := filledQuestion.SelectedChoices.Scan(selectedChoices); err != nil {
+				return fmt.Errorf("failed to parse selected choices: %w", err)
+			}
+		}
+
+		current.Answers = append(current.Answers, filledQuestion)
 	}
 
-	return responses, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read responses: %w", err)
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+
+	return nil
 }
 
-// getAnswersByFilledFormID retrieves all answers for a filled form
+// getAnswersByFilledFormID retrieves all answers for a filled form. Answers
+// keep the question they answered when it still exists, and come last
+// without one otherwise.
 func (r *ResponseRepository) getAnswersByFilledFormID(ctx context.Context, filledFormID uuid.UUID) ([]model.FilledFormQuestion, error) {
 	query := `
 		SELECT ffq.id, ffq.filled_form_id, ffq.question_id, ffq.answer, ffq.selected_choices, ffq.created_at,
-		       q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at,
-		       mc.choices, mc.allow_multiple
+		       ` + optionalQuestionColumns + `
 		FROM filled_form_questions ffq
-		INNER JOIN questions q ON ffq.question_id = q.id
-		LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id
+		LEFT JOIN questions q ON ffq.question_id = q.id
+		` + questionSettingsJoins + `
 		WHERE ffq.filled_form_id = $1
-		ORDER BY q.position`
+		ORDER BY q.position NULLS LAST, ffq.created_at`
 
 	rows, err := r.db.QueryContext(ctx, query, filledFormID)
 	if err != nil {
@@ -207,49 +549,36 @@ func (r *ResponseRepository) getAnswersByFilledFormID(ctx context.Context, fille
 	for rows.Next() {
 		var answer model.FilledFormQuestion
 		var question model.Question
-		var choices sql.NullString
-		var allowMultiple sql.NullBool
+		var settings questionSettings
 
-		err := rows.Scan(
+		dest := append([]interface{}{
 			&answer.ID,
 			&answer.FilledFormID,
 			&answer.QuestionID,
 			&answer.Answer,
 			&answer.SelectedChoices,
 			&answer.CreatedAt,
-			&question.ID,
-			&question.FormID,
-			&question.QuestionText,
-			&question.Answer,
-			&question.Type,
-			&question.Position,
-			&question.Required,
-			&question.CreatedAt,
-			&choices,
-			&allowMultiple,
-		)
-		if err != nil {
+		}, settings.dest(&question)...)
+
+		if err := rows.Scan(dest...); err != nil {
 			return nil, fmt.Errorf("failed to scan answer: %w", err)
 		}
 
-		// Handle multiple choice fields for question
-		if question.Type == model.QuestionTypeMultipleChoice {
-			if choices.Valid {
-				if err := question.Choices.Scan([]byte(choices.String)); err != nil {
-					return nil, fmt.Errorf("failed to parse question choices: %w", err)
-				}
-			}
-			question.AllowMultiple = allowMultiple.Bool
+		// Handle type-specific fields for question
+		if err := settings.apply(&question); err != nil {
+			return nil, fmt.Errorf("failed to parse question settings: %w", err)
 		}
 
-		answer.Question = &question
+		if question.ID != uuid.Nil {
+			answer.Question = &question
+		}
 		answers = append(answers, answer)
 	}
 
 	return answers, nil
 }
 
-// UpdateResponse updates a response and its answers
+// UpdateResponse updates a response and replaces its answers
 func (r *ResponseRepository) UpdateResponse(ctx context.Context, response *model.FilledForm, answers []model.UpdateAnswerRequest) error {
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -261,13 +590,14 @@ func (r *ResponseRepository) UpdateResponse(ctx context.Context, response *model
 	// Update filled form
 	query := `
 		UPDATE filled_forms 
-		SET name = $1, email = $2, updated_at = $3
-		WHERE id = $4`
+		SET name = $1, email = $2, updated_at = $3, form_version = $4
+		WHERE id = $5`
 
 	result, err := tx.ExecContext(ctx, query,
 		response.Name,
 		response.Email,
 		response.UpdatedAt,
+		response.FormVersion,
 		response.ID,
 	)
 
@@ -284,54 +614,42 @@ func (r *ResponseRepository) UpdateResponse(ctx context.Context, response *model
 		return fmt.Errorf("response not found")
 	}
 
-	// Update individual answers
+	// Replace the answers; answers that kept their ID keep it
+	deleteQuery := `DELETE FROM filled_form_questions WHERE filled_form_id = $1`
+	if _, err := tx.ExecContext(ctx, deleteQuery, response.ID); err != nil {
+		return fmt.Errorf("failed to delete answers: %w", err)
+	}
+
+	response.Answers = make([]model.FilledFormQuestion, 0, len(answers))
 	for _, answerReq := range answers {
+		answer := &model.FilledFormQuestion{}
+		createReq := model.CreateAnswerRequest{
+			QuestionID:      answerReq.QuestionID,
+			Answer:          answerReq.Answer,
+			SelectedChoices: answerReq.SelectedChoices,
+		}
+		answer.FromCreateRequest(&createReq, response.ID)
 		if answerReq.ID != nil {
-			// Update existing answer
-			updateQuery := `
-				UPDATE filled_form_questions 
-				SET question_id = $1, answer = $2, selected_choices = $3
-				WHERE id = $4 AND filled_form_id = $5`
-
-			selectedChoices := model.JSONStringArray(answerReq.SelectedChoices)
-			_, err = tx.ExecContext(ctx, updateQuery,
-				answerReq.QuestionID,
-				answerReq.Answer,
-				selectedChoices,
-				*answerReq.ID,
-				response.ID,
-			)
-
-			if err != nil {
-				return fmt.Errorf("failed to update answer %s: %w", *answerReq.ID, err)
-			}
-		} else {
-			// Create new answer
-			answer := &model.FilledFormQuestion{}
-			createReq := model.CreateAnswerRequest{
-				QuestionID:      answerReq.QuestionID,
-				Answer:          answerReq.Answer,
-				SelectedChoices: answerReq.SelectedChoices,
-			}
-			answer.FromCreateRequest(&createReq, response.ID)
-
-			insertQuery := `
-				INSERT INTO filled_form_questions (id, filled_form_id, question_id, answer, selected_choices, created_at)
-				VALUES ($1, $2, $3, $4, $5, $6)`
+			answer.ID = *answerReq.ID
+		}
 
-			_, err = tx.ExecContext(ctx, insertQuery,
-				answer.ID,
-				answer.FilledFormID,
-				answer.QuestionID,
-				answer.Answer,
-				answer.SelectedChoices,
-				answer.CreatedAt,
-			)
+		insertQuery := `
+			INSERT INTO filled_form_questions (id, filled_form_id, question_id, answer, selected_choices, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`
+
+		_, err = tx.ExecContext(ctx, insertQuery,
+			answer.ID,
+			answer.FilledFormID,
+			answer.QuestionID,
+			answer.Answer,
+			answer.SelectedChoices,
+			answer.CreatedAt,
+		)
 
-			if err != nil {
-				return fmt.Errorf("failed to create new answer for question %s: %w", answerReq.QuestionID, err)
-			}
+		if err != nil {
+			return fmt.Errorf("failed to create answer for question %s: %w", answerReq.QuestionID, err)
 		}
+		response.Answers = append(response.Answers, *answer)
 	}
 
 	// Commit transaction
@@ -351,10 +669,71 @@ func (r *ResponseRepository) DeleteResponse(ctx context.Context, responseID uuid
 	}
 	defer tx.Rollback()
 
+	if err := deleteResponse(ctx, tx, responseID); err != nil {
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteResponses deletes the responses among ids that belong to the form,
+// with their answers, and returns the IDs of the deleted responses
+func (r *ResponseRepository) DeleteResponses(ctx context.Context, formID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+
+	// Responses of other forms are skipped
+	ownedQuery := `SELECT id FROM filled_forms WHERE form_id = $1 AND id = ANY($2::uuid[]) FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, ownedQuery, formID, pq.StringArray(idStrings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get responses: %w", err)
+	}
+
+	var owned []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan response ID: %w", err)
+		}
+		owned = append(owned, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get responses: %w", err)
+	}
+
+	for _, id := range owned {
+		if err := deleteResponse(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return owned, nil
+}
+
+// deleteResponse deletes a response and its answers within tx
+func deleteResponse(ctx context.Context, tx *sql.Tx, responseID uuid.UUID) error {
 	// Delete answers first
 	deleteAnswersQuery := `DELETE FROM filled_form_questions WHERE filled_form_id = $1`
-	_, err = tx.ExecContext(ctx, deleteAnswersQuery, responseID)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, deleteAnswersQuery, responseID); err != nil {
 		return fmt.Errorf("failed to delete answers: %w", err)
 	}
 
@@ -374,9 +753,40 @@ func (r *ResponseRepository) DeleteResponse(ctx context.Context, responseID uuid
 		return fmt.Errorf("response not found")
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	return nil
+}
+
+// UpdateResponseTriage saves the triage state of a response
+func (r *ResponseRepository) UpdateResponseTriage(ctx context.Context, response *model.FilledForm) error {
+	tags := response.Tags
+	if tags == nil {
+		tags = model.JSONStringArray{}
+	}
+
+	query := `
+		UPDATE filled_forms
+		SET spam = $2, reviewed = $3, starred = $4, tags = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		response.ID,
+		response.Spam,
+		response.Reviewed,
+		response.Starred,
+		tags,
+		response.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update response: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("response not found")
 	}
 
 	return nil
@@ -388,17 +798,28 @@ func (r *ResponseRepository) GetFormSubmissionStats(ctx context.Context, formID
 		SELECT 
 			COUNT(*) as total_submissions,
 			COUNT(DISTINCT email) as unique_emails,
-			MAX(created_at) as last_submission
+			MAX(created_at) as last_submission,
+			COUNT(completion_time_ms) as timed_submissions,
+			COUNT(*) FILTER (WHERE flagged_fast) as fast_submissions,
+			AVG(completion_time_ms) as average_ms,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY completion_time_ms) as median_ms,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY completion_time_ms) as p90_ms
 		FROM filled_forms 
 		WHERE form_id = $1`
 
 	var stats model.FormSubmissionStats
 	var lastSubmission sql.NullTime
+	var averageMs, medianMs, p90Ms sql.NullFloat64
 
 	err := r.db.QueryRowContext(ctx, query, formID).Scan(
 		&stats.TotalSubmissions,
 		&stats.UniqueEmails,
 		&lastSubmission,
+		&stats.TimedSubmissions,
+		&stats.FastSubmissions,
+		&averageMs,
+		&medianMs,
+		&p90Ms,
 	)
 
 	if err != nil {
@@ -410,8 +831,228 @@ func (r *ResponseRepository) GetFormSubmissionStats(ctx context.Context, formID
 		stats.LastSubmission = &lastSubmission.Time
 	}
 
-	// Calculate average completion time (placeholder for now)
-	stats.AverageTime = 0.0
+	// Completion times are stored in milliseconds and reported in minutes
+	const msPerMinute = 60 * 1000
+	stats.AverageTime = averageMs.Float64 / msPerMinute
+	stats.MedianTime = medianMs.Float64 / msPerMinute
+	stats.P90Time = p90Ms.Float64 / msPerMinute
 
 	return &stats, nil
 }
+
+// answeredCondition matches filled_form_questions rows that carry an actual answer
+const answeredCondition = `(NULLIF(ffq.answer, '') IS NOT NULL OR jsonb_array_length(COALESCE(ffq.selected_choices, '[]'::jsonb)) > 0)`
+
+// GetFormAnalytics aggregates the answers of a form per question, along with
+// the number of submissions per interval
+func (r *ResponseRepository) GetFormAnalytics(ctx context.Context, formID uuid.UUID, interval model.AnalyticsInterval) (*model.FormAnalytics, error) {
+	analytics := &model.FormAnalytics{
+		FormID:    formID,
+		Interval:  interval,
+		Questions: []model.QuestionAnalytics{},
+		Timeline:  []model.SubmissionBucket{},
+	}
+
+	totalQuery := `SELECT COUNT(*) FROM filled_forms WHERE form_id = $1`
+	if err := r.db.QueryRowContext(ctx, totalQuery, formID).Scan(&analytics.TotalSubmissions); err != nil {
+		return nil, fmt.Errorf("failed to count submissions: %w", err)
+	}
+
+	if err := r.getQuestionAnswerRates(ctx, formID, analytics); err != nil {
+		return nil, err
+	}
+
+	if err := r.getChoiceDistributions(ctx, formID, analytics); err != nil {
+		return nil, err
+	}
+
+	if err := r.getChoiceCoOccurrence(ctx, formID, analytics); err != nil {
+		return nil, err
+	}
+
+	if err := r.getSubmissionTimeline(ctx, formID, analytics); err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// getQuestionAnswerRates counts answered submissions per question, including
+// every choice of choice questions so unselected ones show up with a zero count
+func (r *ResponseRepository) getQuestionAnswerRates(ctx context.Context, formID uuid.UUID, analytics *model.FormAnalytics) error {
+	query := `
+		SELECT q.id, q.question_text, q.type, q.position, mc.choices,
+		       COUNT(ffq.id) FILTER (WHERE ` + answeredCondition + `) AS answered
+		FROM questions q
+		LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id
+		LEFT JOIN filled_form_questions ffq ON ffq.question_id = q.id
+		WHERE q.form_id = $1
+		GROUP BY q.id, mc.choices
+		ORDER BY q.position`
+
+	rows, err := r.db.QueryContext(ctx, query, formID)
+	if err != nil {
+		return fmt.Errorf("failed to get answer rates: %w", err)
+	}
+	defer rows.Close()
+
+	total := analytics.TotalSubmissions
+	for rows.Next() {
+		var question model.QuestionAnalytics
+		var choices []byte
+
+		if err := rows.Scan(&question.QuestionID, &question.QuestionText, &question.Type, &question.Position, &choices, &question.Answered); err != nil {
+			return fmt.Errorf("failed to scan answer rate: %w", err)
+		}
+
+		question.Skipped = total - question.Answered
+		question.AnswerRate = model.Percentage(question.Answered, total)
+		question.SkipRate = model.Percentage(question.Skipped, total)
+
+		if len(choices) > 0 {
+			var choiceList model.JSONStringArray
+			if err := choiceList.Scan(choices); err != nil {
+				return fmt.Errorf("failed to parse choices: %w", err)
+			}
+			question.Choices = make([]model.ChoiceCount, len(choiceList))
+			for i, choice := range choiceList {
+				question.Choices[i] = model.ChoiceCount{Choice: choice}
+			}
+		}
+
+		analytics.Questions = append(analytics.Questions, question)
+	}
+
+	return rows.Err()
+}
+
+// getChoiceDistributions counts how often each choice was selected
+func (r *ResponseRepository) getChoiceDistributions(ctx context.Context, formID uuid.UUID, analytics *model.FormAnalytics) error {
+	query := `
+		SELECT ffq.question_id, choice.value, COUNT(*)
+		FROM filled_form_questions ffq
+		INNER JOIN questions q ON ffq.question_id = q.id
+		CROSS JOIN LATERAL jsonb_array_elements_text(ffq.selected_choices) AS choice(value)
+		WHERE q.form_id = $1
+		GROUP BY ffq.question_id, choice.value`
+
+	rows, err := r.db.QueryContext(ctx, query, formID)
+	if err != nil {
+		return fmt.Errorf("failed to get choice distributions: %w", err)
+	}
+	defer rows.Close()
+
+	byID := questionAnalyticsByID(analytics)
+	for rows.Next() {
+		var questionID uuid.UUID
+		var choice string
+		var count int
+
+		if err := rows.Scan(&questionID, &choice, &count); err != nil {
+			return fmt.Errorf("failed to scan choice count: %w", err)
+		}
+
+		question, ok := byID[questionID]
+		if !ok {
+			continue
+		}
+
+		found := false
+		for i := range question.Choices {
+			if question.Choices[i].Choice == choice {
+				question.Choices[i].Count = count
+				found = true
+				break
+			}
+		}
+		// Keep counts for choices that were since removed from the question
+		if !found {
+			question.Choices = append(question.Choices, model.ChoiceCount{Choice: choice, Count: count})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, question := range byID {
+		for i := range question.Choices {
+			question.Choices[i].Percentage = model.Percentage(question.Choices[i].Count, question.Answered)
+		}
+	}
+
+	return nil
+}
+
+// getChoiceCoOccurrence counts how often two choices of a multi-select question
+// were selected in the same submission
+func (r *ResponseRepository) getChoiceCoOccurrence(ctx context.Context, formID uuid.UUID, analytics *model.FormAnalytics) error {
+	query := `
+		SELECT ffq.question_id, choice_a.value, choice_b.value, COUNT(*) AS together
+		FROM filled_form_questions ffq
+		INNER JOIN questions q ON ffq.question_id = q.id
+		INNER JOIN multiple_choice_questions mc ON q.id = mc.question_id AND mc.allow_multiple
+		CROSS JOIN LATERAL jsonb_array_elements_text(ffq.selected_choices) AS choice_a(value)
+		CROSS JOIN LATERAL jsonb_array_elements_text(ffq.selected_choices) AS choice_b(value)
+		WHERE q.form_id = $1 AND choice_a.value < choice_b.value
+		GROUP BY ffq.question_id, choice_a.value, choice_b.value
+		ORDER BY together DESC, choice_a.value, choice_b.value`
+
+	rows, err := r.db.QueryContext(ctx, query, formID)
+	if err != nil {
+		return fmt.Errorf("failed to get choice co-occurrence: %w", err)
+	}
+	defer rows.Close()
+
+	byID := questionAnalyticsByID(analytics)
+	for rows.Next() {
+		var questionID uuid.UUID
+		var pair model.ChoicePairCount
+
+		if err := rows.Scan(&questionID, &pair.First, &pair.Second, &pair.Count); err != nil {
+			return fmt.Errorf("failed to scan choice co-occurrence: %w", err)
+		}
+
+		if question, ok := byID[questionID]; ok {
+			question.CoOccurrence = append(question.CoOccurrence, pair)
+		}
+	}
+
+	return rows.Err()
+}
+
+// getSubmissionTimeline counts submissions per hour or day, in UTC
+func (r *ResponseRepository) getSubmissionTimeline(ctx context.Context, formID uuid.UUID, analytics *model.FormAnalytics) error {
+	query := `
+		SELECT date_trunc($2, created_at AT TIME ZONE 'UTC') AS bucket, COUNT(*)
+		FROM filled_forms
+		WHERE form_id = $1
+		GROUP BY bucket
+		ORDER BY bucket`
+
+	rows, err := r.db.QueryContext(ctx, query, formID, string(analytics.Interval))
+	if err != nil {
+		return fmt.Errorf("failed to get submission timeline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket model.SubmissionBucket
+		if err := rows.Scan(&bucket.Start, &bucket.Count); err != nil {
+			return fmt.Errorf("failed to scan submission bucket: %w", err)
+		}
+		bucket.Start = bucket.Start.UTC()
+		analytics.Timeline = append(analytics.Timeline, bucket)
+	}
+
+	return rows.Err()
+}
+
+// questionAnalyticsByID indexes the question analytics by question ID
+func questionAnalyticsByID(analytics *model.FormAnalytics) map[uuid.UUID]*model.QuestionAnalytics {
+	byID := make(map[uuid.UUID]*model.QuestionAnalytics, len(analytics.Questions))
+	for i := range analytics.Questions {
+		byID[analytics.Questions[i].QuestionID] = &analytics.Questions[i]
+	}
+	return byID
+}
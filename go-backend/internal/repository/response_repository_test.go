@@ -39,6 +39,14 @@ func TestResponseRepositorySuite(t *testing.T) {
 	suite.Run(t, new(ResponseRepositorySuite))
 }
 
+var formLockColumns = []string{"max_responses", "duplicate_policy", "duplicate_window_seconds"}
+
+func (s *ResponseRepositorySuite) expectAnonymityLevel(formID uuid.UUID, level model.AnonymityLevel) {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT anonymity_level FROM forms WHERE id = $1`)).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"anonymity_level"}).AddRow(level))
+}
+
 func (s *ResponseRepositorySuite) TestCreateResponse_Success() {
 	response := &model.FilledForm{
 		ID:        uuid.New(),
@@ -52,11 +60,17 @@ func (s *ResponseRepositorySuite) TestCreateResponse_Success() {
 	}
 
 	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityIdentified)
+
+	// The form has no response limit or duplicate policy
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT max_responses, duplicate_policy, duplicate_window_seconds FROM forms WHERE id = $1 AND (max_responses IS NOT NULL OR duplicate_policy <> 'none') FOR UPDATE`)).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows(formLockColumns))
 
 	// Expect insert into filled_forms
-	ffQuery := `INSERT INTO filled_forms (id, form_id, name, email, user_ip, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ffQuery := `INSERT INTO filled_forms (id, form_id, name, email, user_ip, created_at, updated_at, started_at, completion_time_ms, flagged_fast, email_hash, ip_hash, browser_hash, edit_token_hash, form_version) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 	s.mock.ExpectExec(regexp.QuoteMeta(ffQuery)).
-		WithArgs(response.ID, response.FormID, response.Name, response.Email, response.UserIP, response.CreatedAt, response.UpdatedAt).
+		WithArgs(response.ID, response.FormID, response.Name, response.Email, response.UserIP, response.CreatedAt, response.UpdatedAt, response.StartedAt, response.CompletionTimeMs, response.FlaggedFast, response.EmailHash, response.IPHash, response.BrowserHash, response.EditTokenHash, response.FormVersion).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Expect inserts into filled_form_questions
@@ -69,8 +83,10 @@ func (s *ResponseRepositorySuite) TestCreateResponse_Success() {
 
 	s.mock.ExpectCommit()
 
-	err := s.repo.CreateResponse(context.Background(), response, answers)
+	formClosed, err := s.repo.CreateResponse(context.Background(), response, answers)
 	s.Require().NoError(err)
+	s.False(formClosed)
+	s.Len(response.Answers, 2)
 }
 
 func (s *ResponseRepositorySuite) TestCreateResponse_Rollback() {
@@ -82,37 +98,164 @@ func (s *ResponseRepositorySuite) TestCreateResponse_Rollback() {
 	}
 
 	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`SELECT anonymity_level FROM forms`).WillReturnRows(sqlmock.NewRows([]string{"anonymity_level"}).AddRow("identified"))
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).WillReturnRows(sqlmock.NewRows(formLockColumns))
 	s.mock.ExpectExec(`INSERT INTO filled_forms`).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.mock.ExpectExec(`INSERT INTO filled_form_questions`).WillReturnError(sql.ErrConnDone)
 	s.mock.ExpectRollback()
 
-	err := s.repo.CreateResponse(context.Background(), response, answers)
+	_, err := s.repo.CreateResponse(context.Background(), response, answers)
 	s.Require().Error(err)
 	s.Contains(err.Error(), "failed to create answer")
 }
 
+func (s *ResponseRepositorySuite) TestCreateResponse_LimitReached() {
+	response := &model.FilledForm{ID: uuid.New(), FormID: uuid.New()}
+
+	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityIdentified)
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows(formLockColumns).AddRow(10, "none", nil))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM filled_forms WHERE form_id = $1`)).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().Error(err)
+	s.Equal("form has reached its response limit", err.Error())
+}
+
+func (s *ResponseRepositorySuite) TestCreateResponse_LastSeatClosesForm() {
+	response := &model.FilledForm{ID: uuid.New(), FormID: uuid.New(), CreatedAt: time.Now()}
+
+	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityIdentified)
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows(formLockColumns).AddRow(10, "none", nil))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM filled_forms WHERE form_id = $1`)).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+	s.mock.ExpectExec(`INSERT INTO filled_forms`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE forms SET status = 'closed', updated_at = $2 WHERE id = $1 AND status = 'open'`)).
+		WithArgs(response.FormID, response.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	formClosed, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().NoError(err)
+	s.True(formClosed)
+}
+
+func (s *ResponseRepositorySuite) TestCreateResponse_Duplicate() {
+	emailHash := "abc123"
+	response := &model.FilledForm{ID: uuid.New(), FormID: uuid.New(), CreatedAt: time.Now(), EmailHash: &emailHash}
+
+	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityIdentified)
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows(formLockColumns).AddRow(nil, "email", 3600))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM filled_forms WHERE form_id = $1 AND email_hash = $2`)).
+		WithArgs(response.FormID, emailHash, response.CreatedAt.Add(-time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().Error(err)
+	s.Equal("duplicate response", err.Error())
+}
+
+func (s *ResponseRepositorySuite) TestCreateResponse_DuplicatePolicyWithoutIdentifier() {
+	response := &model.FilledForm{ID: uuid.New(), FormID: uuid.New(), CreatedAt: time.Now()}
+
+	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityIdentified)
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).
+		WithArgs(response.FormID).
+		WillReturnRows(sqlmock.NewRows(formLockColumns).AddRow(nil, "browser", nil))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "no browser identifier")
+}
+
+func (s *ResponseRepositorySuite) TestCreateResponse_AnonymousDropsIdentifiers() {
+	response := &model.FilledForm{
+		ID:        uuid.New(),
+		FormID:    uuid.New(),
+		Name:      stringPtr("Jane Doe"),
+		Email:     stringPtr("jane@example.com"),
+		UserIP:    stringPtr("192.168.1.1"),
+		CreatedAt: time.Now(),
+	}
+
+	s.mock.ExpectBegin()
+	s.expectAnonymityLevel(response.FormID, model.AnonymityAnonymous)
+	s.mock.ExpectQuery(`SELECT max_responses, duplicate_policy`).WillReturnRows(sqlmock.NewRows(formLockColumns))
+	s.mock.ExpectExec(`INSERT INTO filled_forms`).
+		WithArgs(response.ID, response.FormID, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	_, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().NoError(err)
+	s.Nil(response.Name)
+	s.Nil(response.Email)
+	s.Nil(response.UserIP)
+}
+
+func (s *ResponseRepositorySuite) TestCreateResponse_FormNotFound() {
+	response := &model.FilledForm{ID: uuid.New(), FormID: uuid.New()}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`SELECT anonymity_level FROM forms`).WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.CreateResponse(context.Background(), response, nil)
+	s.Require().Error(err)
+	s.Equal("form not found", err.Error())
+}
+
 func (s *ResponseRepositorySuite) TestGetResponseByID_Success() {
 	responseID := uuid.New()
 	formID := uuid.New()
 
 	// Mock for GetResponseByID itself
-	respRows := sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at"}).
-		AddRow(responseID, formID, nil, nil, nil, time.Now(), time.Now())
-	s.mock.ExpectQuery(`SELECT id, form_id, name, email, user_ip, created_at, updated_at FROM filled_forms WHERE id = \$1`).
+	respRows := sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at", "started_at", "completion_time_ms", "flagged_fast", "spam", "reviewed", "starred", "tags", "form_version", "edit_token_hash"}).
+		AddRow(responseID, formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, true, false, false, []byte(`["spam-wave"]`), nil, nil)
+	s.mock.ExpectQuery(`SELECT id, form_id, name, email, user_ip, created_at, updated_at, started_at, completion_time_ms, flagged_fast, spam, reviewed, starred, tags, form_version, edit_token_hash FROM filled_forms WHERE id = \$1`).
 		WithArgs(responseID).
 		WillReturnRows(respRows)
 
 	// Mock for the internal getAnswersByFilledFormID call
 	answerRows := sqlmock.NewRows([]string{
 		"ffq_id", "ffq_filled_form_id", "ffq_question_id", "ffq_answer", "ffq_selected_choices", "ffq_created_at",
-		"q_id", "q_form_id", "q_question_text", "q_answer", "q_type", "q_position", "q_required", "q_created_at",
+		"q_id", "q_form_id", "q_question_text", "q_answer", "q_type", "q_position", "q_required", "q_created_at", "q_display_logic",
 		"mc_choices", "mc_allow_multiple",
+		"rq_scale_min", "rq_scale_max", "rq_min_label", "rq_max_label",
+		"nq_min_value", "nq_max_value", "nq_integer_only",
+		"lt_max_length",
 	}).AddRow(
 		uuid.New(), responseID, uuid.New(), "Answer text", nil, time.Now(),
-		uuid.New(), formID, "Question text", nil, "basic", 1, true, time.Now(),
+		uuid.New(), formID, "Question text", nil, "basic", 1, true, time.Now(), nil,
+		nil, nil,
+		nil, nil, nil, nil,
+		nil, nil, nil,
+		nil,
+	).AddRow(
+		// The question of this answer was deleted
+		uuid.New(), responseID, uuid.New(), "Orphaned answer", nil, time.Now(),
+		nil, nil, "", nil, "", 0, false, time.Now(), nil,
 		nil, nil,
+		nil, nil, nil, nil,
+		nil, nil, nil,
+		nil,
 	)
-	s.mock.ExpectQuery(`SELECT ffq.id, ffq.filled_form_id, ffq.question_id, ffq.answer, ffq.selected_choices, ffq.created_at, q.id, q.form_id, q.question_text, q.answer, q.type, q.position, q.required, q.created_at, mc.choices, mc.allow_multiple FROM filled_form_questions ffq INNER JOIN questions q ON ffq.question_id = q.id LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id WHERE ffq.filled_form_id = \$1`).
+	s.mock.ExpectQuery(`SELECT ffq.id, ffq.filled_form_id, ffq.question_id, ffq.answer, ffq.selected_choices, ffq.created_at, q.id, q.form_id, COALESCE\(q.question_text, ''\), q.answer, COALESCE\(q.type, ''\), COALESCE\(q.position, 0\), COALESCE\(q.required, FALSE\), COALESCE\(q.created_at, ffq.created_at\), q.display_logic, mc.choices, mc.allow_multiple, rq.scale_min, rq.scale_max, rq.min_label, rq.max_label, nq.min_value, nq.max_value, nq.integer_only, lt.max_length FROM filled_form_questions ffq LEFT JOIN questions q ON ffq.question_id = q.id LEFT JOIN multiple_choice_questions mc ON q.id = mc.question_id LEFT JOIN rating_questions rq ON q.id = rq.question_id LEFT JOIN number_questions nq ON q.id = nq.question_id LEFT JOIN long_text_questions lt ON q.id = lt.question_id WHERE ffq.filled_form_id = \$1`).
 		WithArgs(responseID).
 		WillReturnRows(answerRows)
 
@@ -120,15 +263,18 @@ func (s *ResponseRepositorySuite) TestGetResponseByID_Success() {
 	s.Require().NoError(err)
 	s.Require().NotNil(resp)
 	s.Equal(responseID, resp.ID)
-	s.Len(resp.Answers, 1)
+	s.True(resp.Spam)
+	s.Equal(model.JSONStringArray{"spam-wave"}, resp.Tags)
+	s.Require().Len(resp.Answers, 2)
 	s.NotNil(resp.Answers[0].Question)
+	s.Nil(resp.Answers[1].Question)
 }
 
 func (s *ResponseRepositorySuite) TestGetResponseByID_GetAnswersFailure() {
 	responseID := uuid.New()
 	s.mock.ExpectQuery(`SELECT`).WithArgs(responseID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at"}).
-			AddRow(responseID, uuid.New(), nil, nil, nil, time.Now(), time.Now()))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at", "started_at", "completion_time_ms", "flagged_fast", "spam", "reviewed", "starred", "tags", "form_version", "edit_token_hash"}).
+			AddRow(responseID, uuid.New(), nil, nil, nil, time.Now(), time.Now(), nil, nil, false, false, false, false, []byte(`[]`), nil, nil))
 
 	s.mock.ExpectQuery(`SELECT`).WithArgs(responseID).WillReturnError(sql.ErrConnDone)
 
@@ -137,18 +283,186 @@ func (s *ResponseRepositorySuite) TestGetResponseByID_GetAnswersFailure() {
 	s.Contains(err.Error(), "failed to get answers")
 }
 
+var firstResponsesPage = model.PageRequest{Limit: model.DefaultPageSize, Sort: "created_at", Order: model.SortDesc}
+
 func (s *ResponseRepositorySuite) TestGetResponsesByFormID_ScanError() {
 	formID := uuid.New()
 	rows := sqlmock.NewRows([]string{"id"}).AddRow("not-a-uuid") // This will cause a scan error
-	s.mock.ExpectQuery(`SELECT id, form_id, name, email, user_ip, created_at, updated_at FROM filled_forms WHERE form_id = \$1`).
-		WithArgs(formID).
+	s.mock.ExpectQuery(`SELECT id, form_id, name, email, user_ip, created_at, updated_at, started_at, completion_time_ms, flagged_fast, spam, reviewed, starred, tags, form_version FROM filled_forms WHERE form_id = \$1`).
+		WithArgs(formID, 51).
 		WillReturnRows(rows)
 
-	_, err := s.repo.GetResponsesByFormID(context.Background(), formID)
+	_, _, err := s.repo.GetResponsesByFormID(context.Background(), formID, model.ResponseFilter{}, firstResponsesPage)
 	s.Require().Error(err)
 	s.Contains(err.Error(), "failed to scan response")
 }
 
+func (s *ResponseRepositorySuite) TestGetResponsesListByFormID_Filter() {
+	formID := uuid.New()
+	spam, starred := false, true
+	filter := model.ResponseFilter{Spam: &spam, Starred: &starred, Tag: "follow-up"}
+
+	rows := sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at", "started_at", "completion_time_ms", "flagged_fast", "spam", "reviewed", "starred", "tags", "form_version"}).
+		AddRow(uuid.New(), formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, false, true, true, []byte(`["follow-up"]`), 2)
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM filled_forms WHERE form_id = $1 AND spam = $2 AND starred = $3 AND tags ? $4 ORDER BY created_at DESC, id DESC LIMIT $5`)).
+		WithArgs(formID, false, true, "follow-up", 51).
+		WillReturnRows(rows)
+
+	responses, next, err := s.repo.GetResponsesListByFormID(context.Background(), formID, filter, firstResponsesPage)
+	s.Require().NoError(err)
+	s.Require().Len(responses, 1)
+	s.True(responses[0].Reviewed)
+	s.Nil(next)
+}
+
+func (s *ResponseRepositorySuite) TestGetResponsesListByFormID_AnswerAndDateFilters() {
+	formID, questionID := uuid.New(), uuid.New()
+	from := time.Now().Add(-24 * time.Hour)
+	filter := model.ResponseFilter{
+		SubmittedFrom: &from,
+		Answers: []model.AnswerFilter{
+			{QuestionID: questionID, Equals: "Yes"},
+			{QuestionID: questionID, Contains: "late"},
+		},
+	}
+	after := &model.Cursor{Sort: "updated_at", Order: model.SortAsc, Value: from, ID: uuid.New()}
+	page := model.PageRequest{Limit: 1, Sort: "updated_at", Order: model.SortAsc, After: after}
+
+	updated := time.Now()
+	firstID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at", "started_at", "completion_time_ms", "flagged_fast", "spam", "reviewed", "starred", "tags", "form_version"}).
+		AddRow(firstID, formID, nil, nil, nil, from, updated, nil, nil, false, false, false, false, []byte(`[]`), nil).
+		AddRow(uuid.New(), formID, nil, nil, nil, from, updated, nil, nil, false, false, false, false, []byte(`[]`), nil)
+	query := `WHERE form_id = $1 AND created_at >= $2` +
+		` AND EXISTS (SELECT 1 FROM filled_form_questions ffq WHERE ffq.filled_form_id = filled_forms.id AND ffq.question_id = $3 AND (ffq.answer = $4 OR ffq.selected_choices ? $4))` +
+		` AND EXISTS (SELECT 1 FROM filled_form_questions ffq WHERE ffq.filled_form_id = filled_forms.id AND ffq.question_id = $5 AND strpos(lower(ffq.answer), lower($6)) > 0)` +
+		` AND (updated_at, id) > ($7, $8) ORDER BY updated_at ASC, id ASC LIMIT $9`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(formID, from, questionID, "Yes", questionID, "late", after.Value, after.ID, 2).
+		WillReturnRows(rows)
+
+	responses, next, err := s.repo.GetResponsesListByFormID(context.Background(), formID, filter, page)
+	s.Require().NoError(err)
+	s.Require().Len(responses, 1)
+	s.Require().NotNil(next)
+	s.Equal(firstID, next.ID)
+	s.Equal("updated_at", next.Sort)
+	s.True(next.Value.Equal(updated))
+}
+
+func (s *ResponseRepositorySuite) TestGetResponsesListByFormID_UnsupportedSort() {
+	_, _, err := s.repo.GetResponsesListByFormID(context.Background(), uuid.New(), model.ResponseFilter{}, model.PageRequest{Limit: 10, Sort: "name; DROP TABLE forms"})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unsupported sort")
+}
+
+func (s *ResponseRepositorySuite) TestSearchResponses_Highlights() {
+	formID, responseID, questionID := uuid.New(), uuid.New(), uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at", "started_at", "completion_time_ms", "flagged_fast", "spam", "reviewed", "starred", "tags", "form_version"}).
+		AddRow(responseID, formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, false, false, false, []byte(`[]`), nil)
+	s.mock.ExpectQuery(regexp.QuoteMeta(`WHERE form_id = $1 AND EXISTS (SELECT 1 FROM filled_form_questions ffq WHERE ffq.filled_form_id = filled_forms.id AND ffq.answer_tsv @@ websearch_to_tsquery('english', $2)) ORDER BY created_at DESC, id DESC LIMIT $3`)).
+		WithArgs(formID, "late delivery", 51).
+		WillReturnRows(rows)
+
+	// Answers of the matching response
+	s.mock.ExpectQuery(`SELECT`).WithArgs(responseID).WillReturnRows(sqlmock.NewRows([]string{"ffq_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT filled_form_id, question_id, ts_headline('english', answer, websearch_to_tsquery('english', $2), $3) FROM filled_form_questions WHERE filled_form_id = ANY($1::uuid[]) AND answer_tsv @@ websearch_to_tsquery('english', $2)`)).
+		WithArgs(sqlmock.AnyArg(), "late delivery", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"filled_form_id", "question_id", "ts_headline"}).
+			AddRow(responseID, questionID, "<b>the</b> <mark>delivery</mark> was <mark>late</mark>"))
+
+	responses, next, err := s.repo.SearchResponses(context.Background(), formID, "late delivery", model.ResponseFilter{}, firstResponsesPage)
+	s.Require().NoError(err)
+	s.Nil(next)
+	s.Require().Len(responses, 1)
+	s.Require().Len(responses[0].Highlights, 1)
+	s.Equal(questionID, responses[0].Highlights[0].QuestionID)
+	s.Equal("&lt;b&gt;the&lt;/b&gt; <mark>delivery</mark> was <mark>late</mark>", responses[0].Highlights[0].Snippet)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *ResponseRepositorySuite) TestDeleteResponses_SkipsOtherForms() {
+	formID := uuid.New()
+	ownedID, foreignID := uuid.New(), uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM filled_forms WHERE form_id = $1 AND id = ANY($2::uuid[]) FOR UPDATE`)).
+		WithArgs(formID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ownedID))
+	s.mock.ExpectExec(`DELETE FROM filled_form_questions WHERE filled_form_id = \$1`).WithArgs(ownedID).WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(`DELETE FROM filled_forms WHERE id = \$1`).WithArgs(ownedID).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.repo.DeleteResponses(context.Background(), formID, []uuid.UUID{ownedID, foreignID})
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{ownedID}, deleted)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *ResponseRepositorySuite) TestUpdateResponseTriage() {
+	response := &model.FilledForm{ID: uuid.New(), Spam: true, UpdatedAt: time.Now()}
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE filled_forms SET spam = $2, reviewed = $3, starred = $4, tags = $5, updated_at = $6 WHERE id = $1`)).
+		WithArgs(response.ID, true, false, false, []byte(`[]`), response.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.UpdateResponseTriage(context.Background(), response)
+	s.Require().Error(err)
+	s.Equal("response not found", err.Error())
+}
+
+func (s *ResponseRepositorySuite) TestStreamResponsesByFormID_GroupsAnswers() {
+	formID := uuid.New()
+	firstID, secondID := uuid.New(), uuid.New()
+	questionID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at",
+		"started_at", "completion_time_ms", "flagged_fast",
+		"ffq_id", "question_id", "answer", "selected_choices", "ffq_created_at",
+	}).
+		AddRow(firstID, formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, uuid.New(), questionID, "Blue", nil, time.Now()).
+		AddRow(firstID, formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, uuid.New(), uuid.New(), nil, []byte(`["A","B"]`), time.Now()).
+		AddRow(secondID, formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, nil, nil, nil, nil, nil)
+	s.mock.ExpectQuery(`SELECT ff.id, .* FROM filled_forms ff LEFT JOIN filled_form_questions ffq ON ffq.filled_form_id = ff.id WHERE ff.form_id = \$1 ORDER BY ff.created_at, ff.id`).
+		WithArgs(formID).
+		WillReturnRows(rows)
+
+	var streamed []*model.FilledForm
+	err := s.repo.StreamResponsesByFormID(context.Background(), formID, func(response *model.FilledForm) error {
+		streamed = append(streamed, response)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(streamed, 2)
+
+	s.Equal(firstID, streamed[0].ID)
+	s.Require().Len(streamed[0].Answers, 2)
+	s.Equal(questionID, streamed[0].Answers[0].QuestionID)
+	s.Equal("Blue", *streamed[0].Answers[0].Answer)
+	s.Equal(model.JSONStringArray{"A", "B"}, streamed[0].Answers[1].SelectedChoices)
+
+	s.Equal(secondID, streamed[1].ID)
+	s.Empty(streamed[1].Answers)
+}
+
+func (s *ResponseRepositorySuite) TestStreamResponsesByFormID_CallbackError() {
+	formID := uuid.New()
+	rows := sqlmock.NewRows([]string{
+		"id", "form_id", "name", "email", "user_ip", "created_at", "updated_at",
+		"started_at", "completion_time_ms", "flagged_fast",
+		"ffq_id", "question_id", "answer", "selected_choices", "ffq_created_at",
+	}).AddRow(uuid.New(), formID, nil, nil, nil, time.Now(), time.Now(), nil, nil, false, nil, nil, nil, nil, nil)
+	s.mock.ExpectQuery(`SELECT ff.id`).WithArgs(formID).WillReturnRows(rows)
+
+	err := s.repo.StreamResponsesByFormID(context.Background(), formID, func(*model.FilledForm) error {
+		return sql.ErrConnDone
+	})
+	s.ErrorIs(err, sql.ErrConnDone)
+}
+
 func (s *ResponseRepositorySuite) TestUpdateResponse_TransactionFailure() {
 	response := &model.FilledForm{ID: uuid.New()}
 	answers := []model.UpdateAnswerRequest{{ID: new(uuid.UUID), QuestionID: uuid.New()}} // one answer to fail on
@@ -163,6 +477,36 @@ func (s *ResponseRepositorySuite) TestUpdateResponse_TransactionFailure() {
 	s.Require().Error(err)
 }
 
+func (s *ResponseRepositorySuite) TestUpdateResponse_ReplacesAnswers() {
+	response := &model.FilledForm{ID: uuid.New(), UpdatedAt: time.Now()}
+	keptID := uuid.New()
+	answers := []model.UpdateAnswerRequest{
+		{ID: &keptID, QuestionID: uuid.New(), Answer: stringPtr("Edited")},
+		{QuestionID: uuid.New(), SelectedChoices: []string{"Choice B"}},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE filled_forms SET name = $1, email = $2, updated_at = $3, form_version = $4 WHERE id = $5`)).
+		WithArgs(response.Name, response.Email, response.UpdatedAt, response.FormVersion, response.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM filled_form_questions WHERE filled_form_id = $1`)).
+		WithArgs(response.ID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectExec(`INSERT INTO filled_form_questions`).
+		WithArgs(keptID, response.ID, answers[0].QuestionID, answers[0].Answer, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO filled_form_questions`).
+		WithArgs(sqlmock.AnyArg(), response.ID, answers[1].QuestionID, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.UpdateResponse(context.Background(), response, answers)
+	s.Require().NoError(err)
+	s.Require().Len(response.Answers, 2)
+	s.Equal(keptID, response.Answers[0].ID)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
 func (s *ResponseRepositorySuite) TestDeleteResponse_Success() {
 	respID := uuid.New()
 
@@ -174,3 +518,71 @@ func (s *ResponseRepositorySuite) TestDeleteResponse_Success() {
 	err := s.repo.DeleteResponse(context.Background(), respID)
 	s.Require().NoError(err)
 }
+
+func (s *ResponseRepositorySuite) TestGetFormAnalytics() {
+	formID := uuid.New()
+	colorsID, commentID := uuid.New(), uuid.New()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM filled_forms WHERE form_id = \$1`).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	s.mock.ExpectQuery(`SELECT q.id, q.question_text, q.type, q.position, mc.choices, COUNT\(ffq.id\) FILTER`).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_text", "type", "position", "choices", "answered"}).
+			AddRow(colorsID, "Colors", "multiple_choice", 1, []byte(`["Red","Blue","Green"]`), 2).
+			AddRow(commentID, "Comment", "basic", 2, nil, 1))
+	s.mock.ExpectQuery(`jsonb_array_elements_text\(ffq.selected_choices\) AS choice\(value\)`).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"question_id", "value", "count"}).
+			AddRow(colorsID, "Red", 2).
+			AddRow(colorsID, "Blue", 1))
+	s.mock.ExpectQuery(`AS choice_a\(value\)`).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"question_id", "first", "second", "together"}).
+			AddRow(colorsID, "Blue", "Red", 1))
+	s.mock.ExpectQuery(`SELECT date_trunc\(\$2, created_at AT TIME ZONE 'UTC'\)`).
+		WithArgs(formID, "day").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(day, 4))
+
+	analytics, err := s.repo.GetFormAnalytics(context.Background(), formID, model.AnalyticsIntervalDay)
+	s.Require().NoError(err)
+	s.Equal(4, analytics.TotalSubmissions)
+	s.Require().Len(analytics.Questions, 2)
+
+	colors := analytics.Questions[0]
+	s.Equal(2, colors.Skipped)
+	s.Equal(50.0, colors.AnswerRate)
+	s.Equal([]model.ChoiceCount{
+		{Choice: "Red", Count: 2, Percentage: 100},
+		{Choice: "Blue", Count: 1, Percentage: 50},
+		{Choice: "Green", Count: 0, Percentage: 0},
+	}, colors.Choices)
+	s.Equal([]model.ChoicePairCount{{First: "Blue", Second: "Red", Count: 1}}, colors.CoOccurrence)
+
+	comment := analytics.Questions[1]
+	s.Equal(75.0, comment.SkipRate)
+	s.Empty(comment.Choices)
+
+	s.Equal([]model.SubmissionBucket{{Start: day, Count: 4}}, analytics.Timeline)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *ResponseRepositorySuite) TestGetFormSubmissionStats_CompletionTimes() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(`percentile_cont\(0.5\) WITHIN GROUP \(ORDER BY completion_time_ms\)`).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"total_submissions", "unique_emails", "last_submission", "timed_submissions", "fast_submissions",
+			"average_ms", "median_ms", "p90_ms",
+		}).AddRow(10, 8, time.Now(), 6, 1, 90000.0, 60000.0, 150000.0))
+
+	stats, err := s.repo.GetFormSubmissionStats(context.Background(), formID)
+	s.Require().NoError(err)
+	s.Equal(10, stats.TotalSubmissions)
+	s.Equal(6, stats.TimedSubmissions)
+	s.Equal(1, stats.FastSubmissions)
+	s.Equal(1.5, stats.AverageTime)
+	s.Equal(1.0, stats.MedianTime)
+	s.Equal(2.5, stats.P90Time)
+}
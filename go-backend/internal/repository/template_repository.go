@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const templateColumns = `id, author_id, name, description, visibility, definition, source_form_id, created_at, updated_at`
+
+// CreateTemplate creates a new template
+func (r *TemplateRepository) CreateTemplate(ctx context.Context, template *model.FormTemplate) error {
+	query := `
+		INSERT INTO form_templates (id, author_id, name, description, visibility, definition, source_form_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		template.ID,
+		template.AuthorID,
+		template.Name,
+		template.Description,
+		template.Visibility,
+		template.Definition,
+		template.SourceFormID,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return nil
+}
+
+// GetTemplateByID retrieves a template by ID
+func (r *TemplateRepository) GetTemplateByID(ctx context.Context, id uuid.UUID) (*model.FormTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM form_templates WHERE id = $1`
+
+	var template model.FormTemplate
+	if err := r.db.GetContext(ctx, &template, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return &template, nil
+}
+
+// ListTemplates retrieves one page of the templates in scope for a user,
+// newest first. The returned cursor is nil on the last page.
+func (r *TemplateRepository) ListTemplates(ctx context.Context, userID uuid.UUID, scope model.TemplateScope, page model.PageRequest) ([]*model.FormTemplate, *model.Cursor, error) {
+	var condition string
+	var args []interface{}
+	switch scope {
+	case model.TemplateScopeAll:
+		condition, args = `(author_id = $1 OR visibility = 'public')`, []interface{}{userID}
+	case model.TemplateScopeMine:
+		condition, args = `author_id = $1`, []interface{}{userID}
+	case model.TemplateScopePublic:
+		condition = `visibility = 'public'`
+	default:
+		return nil, nil, fmt.Errorf("unsupported template scope %s", scope)
+	}
+
+	pageCondition, orderLimit, args := pageClauses(page, "created_at", "id", args)
+	query := `
+		SELECT ` + templateColumns + `
+		FROM form_templates
+		WHERE ` + condition + pageCondition + orderLimit
+
+	var templates []*model.FormTemplate
+	if err := r.db.SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	if len(templates) <= page.Limit {
+		return templates, nil, nil
+	}
+
+	templates = templates[:page.Limit]
+	last := templates[len(templates)-1]
+	return templates, page.NextCursor(last.CreatedAt, last.ID), nil
+}
+
+// UpdateTemplate updates the name, description and visibility of a template
+func (r *TemplateRepository) UpdateTemplate(ctx context.Context, template *model.FormTemplate) error {
+	query := `
+		UPDATE form_templates
+		SET name = $2, description = $3, visibility = $4, updated_at = $5
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		template.ID,
+		template.Name,
+		template.Description,
+		template.Visibility,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("template not found")
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes a template. Forms created from it are kept.
+func (r *TemplateRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM form_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("template not found")
+	}
+
+	return nil
+}
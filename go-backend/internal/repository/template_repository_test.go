@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type TemplateRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *TemplateRepository
+}
+
+func (s *TemplateRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &TemplateRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *TemplateRepositorySuite) TearDownTest() {
+	s.mock.ExpectationsWereMet()
+}
+
+func TestTemplateRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TemplateRepositorySuite))
+}
+
+var templateRowColumns = []string{"id", "author_id", "name", "description", "visibility", "definition", "source_form_id", "created_at", "updated_at"}
+
+func (s *TemplateRepositorySuite) TestCreateTemplate_Success() {
+	template := &model.FormTemplate{
+		ID:         uuid.New(),
+		AuthorID:   uuid.New(),
+		Name:       "Weekly check-in",
+		Visibility: model.TemplateVisibilityPrivate,
+		Definition: model.FormDefinition{Title: "Check-in", Questions: []model.QuestionDefinition{}},
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	query := `INSERT INTO form_templates (id, author_id, name, description, visibility, definition, source_form_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(template.ID, template.AuthorID, template.Name, "", template.Visibility, []byte(`{"title":"Check-in","description":"","questions":[]}`), nil, template.CreatedAt, template.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := s.repo.CreateTemplate(context.Background(), template)
+	s.Require().NoError(err)
+}
+
+func (s *TemplateRepositorySuite) TestGetTemplateByID_Success() {
+	id := uuid.New()
+	rows := sqlmock.NewRows(templateRowColumns).
+		AddRow(id, uuid.New(), "Weekly check-in", "", "public", []byte(`{"title":"Check-in","questions":[{"id":"`+uuid.NewString()+`","question_text":"How was your week?","type":"basic","position":1,"required":true}]}`), nil, time.Now(), time.Now())
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_templates WHERE id = $1`)).WithArgs(id).WillReturnRows(rows)
+
+	template, err := s.repo.GetTemplateByID(context.Background(), id)
+	s.Require().NoError(err)
+	s.Equal(model.TemplateVisibilityPublic, template.Visibility)
+	s.Require().Len(template.Definition.Questions, 1)
+	s.Equal("How was your week?", template.Definition.Questions[0].QuestionText)
+}
+
+func (s *TemplateRepositorySuite) TestGetTemplateByID_NotFound() {
+	id := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_templates WHERE id = $1`)).WithArgs(id).WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetTemplateByID(context.Background(), id)
+	s.Require().Error(err)
+	s.Equal("template not found", err.Error())
+}
+
+func (s *TemplateRepositorySuite) TestListTemplates_Scopes() {
+	userID := uuid.New()
+	page := model.PageRequest{Limit: 10, Sort: "created_at", Order: model.SortDesc}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_templates WHERE (author_id = $1 OR visibility = 'public') ORDER BY created_at DESC, id DESC LIMIT $2`)).
+		WithArgs(userID, 11).
+		WillReturnRows(sqlmock.NewRows(templateRowColumns))
+	_, _, err := s.repo.ListTemplates(context.Background(), userID, model.TemplateScopeAll, page)
+	s.Require().NoError(err)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_templates WHERE visibility = 'public' ORDER BY created_at DESC, id DESC LIMIT $1`)).
+		WithArgs(11).
+		WillReturnRows(sqlmock.NewRows(templateRowColumns))
+	_, _, err = s.repo.ListTemplates(context.Background(), userID, model.TemplateScopePublic, page)
+	s.Require().NoError(err)
+
+	_, _, err = s.repo.ListTemplates(context.Background(), userID, model.TemplateScope("everything"), page)
+	s.Require().Error(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *TemplateRepositorySuite) TestDeleteTemplate_NotFound() {
+	id := uuid.New()
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM form_templates WHERE id = $1`)).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.DeleteTemplate(context.Background(), id)
+	s.Require().Error(err)
+	s.Equal("template not found", err.Error())
+}
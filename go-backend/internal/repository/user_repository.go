@@ -15,6 +15,11 @@ import (
 	"github.com/ayan-sh03/anoq/internal/model"
 )
 
+const sessionColumns = `id, user_id, token, user_agent, ip_address, device, expires_at, last_used_at, created_at, updated_at`
+
+// sessionTouchInterval limits how often a session's last use is written
+const sessionTouchInterval = time.Minute
+
 // CreateUser creates a new user with password
 func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) error {
 	query := `
@@ -126,8 +131,9 @@ func (r *UserRepository) CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
-// CreateSession creates a new session for a user
-func (r *UserRepository) CreateSession(ctx context.Context, userID uuid.UUID) (*model.UserSession, error) {
+// CreateSession creates a new session for a user that expires after ttl
+// unless it is used
+func (r *UserRepository) CreateSession(ctx context.Context, userID uuid.UUID, client model.SessionClient, ttl time.Duration) (*model.UserSession, error) {
 	// Generate random token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -135,24 +141,21 @@ func (r *UserRepository) CreateSession(ctx context.Context, userID uuid.UUID) (*
 	}
 	token := hex.EncodeToString(tokenBytes)
 
-	session := &model.UserSession{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Token:     token,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hour sessions
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	session := model.NewUserSession(userID, token, client, time.Now(), ttl)
 
 	query := `
-		INSERT INTO user_sessions (id, user_id, token, expires_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO user_sessions (id, user_id, token, user_agent, ip_address, device, expires_at, last_used_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	_, err := r.db.ExecContext(ctx, query,
 		session.ID,
 		session.UserID,
 		session.Token,
+		session.UserAgent,
+		session.IPAddress,
+		session.Device,
 		session.ExpiresAt,
+		session.LastUsedAt,
 		session.CreatedAt,
 		session.UpdatedAt,
 	)
@@ -167,7 +170,7 @@ func (r *UserRepository) CreateSession(ctx context.Context, userID uuid.UUID) (*
 // GetSessionByToken retrieves a session by token
 func (r *UserRepository) GetSessionByToken(ctx context.Context, token string) (*model.UserSession, error) {
 	query := `
-		SELECT id, user_id, token, expires_at, created_at, updated_at
+		SELECT ` + sessionColumns + `
 		FROM user_sessions
 		WHERE token = $1 AND expires_at > NOW()`
 
@@ -183,6 +186,71 @@ func (r *UserRepository) GetSessionByToken(ctx context.Context, token string) (*
 	return &session, nil
 }
 
+// TouchSession records that a session was used and pushes its expiry back to
+// ttl from now. It writes at most once per sessionTouchInterval so busy
+// sessions do not cost a write per request.
+func (r *UserRepository) TouchSession(ctx context.Context, id uuid.UUID, now time.Time, ttl time.Duration) error {
+	query := `
+		UPDATE user_sessions
+		SET last_used_at = $2, expires_at = $3, updated_at = $2
+		WHERE id = $1 AND last_used_at < $4`
+
+	if _, err := r.db.ExecContext(ctx, query, id, now, now.Add(ttl), now.Add(-sessionTouchInterval)); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	return nil
+}
+
+// ListSessionsByUserID retrieves the unexpired sessions of a user, most
+// recently used first
+func (r *UserRepository) ListSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*model.UserSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY last_used_at DESC, id`
+
+	var sessions []*model.UserSession
+	if err := r.db.SelectContext(ctx, &sessions, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// DeleteUserSession deletes one of a user's sessions
+func (r *UserRepository) DeleteUserSession(ctx context.Context, userID, id uuid.UUID) error {
+	query := `DELETE FROM user_sessions WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
+// DeleteUserSessions deletes every session of a user
+func (r *UserRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM user_sessions WHERE user_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteSession deletes a session
 func (r *UserRepository) DeleteSession(ctx context.Context, token string) error {
 	query := `DELETE FROM user_sessions WHERE token = $1`
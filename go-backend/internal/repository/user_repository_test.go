@@ -213,18 +213,22 @@ func (s *UserRepositorySuite) TestPasswordHashing() {
 	s.False(s.repo.CheckPassword("wrongpassword", hash))
 }
 
+var sessionRowColumns = []string{"id", "user_id", "token", "user_agent", "ip_address", "device", "expires_at", "last_used_at", "created_at", "updated_at"}
+
 func (s *UserRepositorySuite) TestCreateSession_Success() {
 	userID := uuid.New()
-	query := `INSERT INTO user_sessions (id, user_id, token, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	client := model.SessionClient{UserAgent: "curl/8.4.0", IPAddress: "203.0.113.7"}
+	query := `INSERT INTO user_sessions (id, user_id, token, user_agent, ip_address, device, expires_at, last_used_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 	s.mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(sqlmock.AnyArg(), userID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), userID, sqlmock.AnyArg(), "curl/8.4.0", "203.0.113.7", "curl", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	session, err := s.repo.CreateSession(context.Background(), userID)
+	session, err := s.repo.CreateSession(context.Background(), userID, client, 12*time.Hour)
 	s.Require().NoError(err)
 	s.Require().NotNil(session)
 	s.Equal(userID, session.UserID)
 	s.NotEmpty(session.Token)
+	s.Equal(session.CreatedAt.Add(12*time.Hour), session.ExpiresAt)
 }
 
 func (s *UserRepositorySuite) TestGetSessionByToken_Success() {
@@ -234,10 +238,10 @@ func (s *UserRepositorySuite) TestGetSessionByToken_Success() {
 		UserID: uuid.New(),
 		Token:  token,
 	}
-	rows := sqlmock.NewRows([]string{"id", "user_id", "token", "expires_at", "created_at", "updated_at"}).
-		AddRow(expectedSession.ID, expectedSession.UserID, expectedSession.Token, time.Now().Add(time.Hour), time.Now(), time.Now())
+	rows := sqlmock.NewRows(sessionRowColumns).
+		AddRow(expectedSession.ID, expectedSession.UserID, expectedSession.Token, "", "", "", time.Now().Add(time.Hour), time.Now(), time.Now(), time.Now())
 
-	query := `SELECT id, user_id, token, expires_at, created_at, updated_at FROM user_sessions WHERE token = $1 AND expires_at > NOW()`
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE token = $1 AND expires_at > NOW()`
 	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(token).WillReturnRows(rows)
 
 	session, err := s.repo.GetSessionByToken(context.Background(), token)
@@ -249,13 +253,57 @@ func (s *UserRepositorySuite) TestGetSessionByToken_Success() {
 
 func (s *UserRepositorySuite) TestGetSessionByToken_GenericError() {
 	token := "invalid-token"
-	query := `SELECT id, user_id, token, expires_at, created_at, updated_at FROM user_sessions WHERE token = $1 AND expires_at > NOW()`
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE token = $1 AND expires_at > NOW()`
 	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(token).WillReturnError(sql.ErrConnDone)
 
 	_, err := s.repo.GetSessionByToken(context.Background(), token)
 	s.Require().Error(err)
 }
 
+func (s *UserRepositorySuite) TestTouchSession_SlidesExpiry() {
+	id := uuid.New()
+	now := time.Now()
+	query := `UPDATE user_sessions SET last_used_at = $2, expires_at = $3, updated_at = $2 WHERE id = $1 AND last_used_at < $4`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(id, now, now.Add(24*time.Hour), now.Add(-time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.repo.TouchSession(context.Background(), id, now, 24*time.Hour)
+	s.Require().NoError(err)
+}
+
+func (s *UserRepositorySuite) TestListSessionsByUserID_Success() {
+	userID := uuid.New()
+	rows := sqlmock.NewRows(sessionRowColumns).
+		AddRow(uuid.New(), userID, "token", "curl/8.4.0", "203.0.113.7", "curl", time.Now().Add(time.Hour), time.Now(), time.Now(), time.Now())
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE user_id = $1 AND expires_at > NOW() ORDER BY last_used_at DESC, id`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnRows(rows)
+
+	sessions, err := s.repo.ListSessionsByUserID(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal("curl", sessions[0].Device)
+}
+
+func (s *UserRepositorySuite) TestDeleteUserSession_NotFound() {
+	userID, id := uuid.New(), uuid.New()
+	query := `DELETE FROM user_sessions WHERE id = $1 AND user_id = $2`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.DeleteUserSession(context.Background(), userID, id)
+	s.Require().Error(err)
+	s.Equal("session not found", err.Error())
+}
+
+func (s *UserRepositorySuite) TestDeleteUserSessions_Success() {
+	userID := uuid.New()
+	query := `DELETE FROM user_sessions WHERE user_id = $1`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := s.repo.DeleteUserSessions(context.Background(), userID)
+	s.Require().NoError(err)
+}
+
 func (s *UserRepositorySuite) TestDeleteSession_Success() {
 	token := "token-to-delete"
 	query := `DELETE FROM user_sessions WHERE token = $1`
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const versionColumns = `form_id, version, definition, created_at`
+
+// maxVersionAttempts bounds the retries of EnsureVersion when concurrent
+// submissions record versions of the same form
+const maxVersionAttempts = 3
+
+// EnsureVersion returns the latest version of a form, first recording the
+// definition as a new version when it differs from the latest one. Versions
+// are never modified once recorded.
+func (r *VersionRepository) EnsureVersion(ctx context.Context, formID uuid.UUID, definition *model.FormDefinition) (*model.FormVersion, error) {
+	for attempt := 0; attempt < maxVersionAttempts; attempt++ {
+		latest, err := r.GetLatestVersion(ctx, formID)
+		if err != nil && err.Error() != "version not found" {
+			return nil, err
+		}
+		if latest != nil && model.SameDefinition(&latest.Definition, definition) {
+			return latest, nil
+		}
+
+		version := &model.FormVersion{
+			FormID:     formID,
+			Version:    1,
+			Definition: *definition,
+			CreatedAt:  time.Now(),
+		}
+		if latest != nil {
+			version.Version = latest.Version + 1
+		}
+
+		// A concurrent submission may have recorded this version number
+		// first, in which case its version is compared on the next attempt
+		query := `
+			INSERT INTO form_versions (form_id, version, definition, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (form_id, version) DO NOTHING`
+
+		result, err := r.db.ExecContext(ctx, query, version.FormID, version.Version, version.Definition, version.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create form version: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			return version, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to create form version: conflicting concurrent versions")
+}
+
+// GetVersion retrieves a version of a form
+func (r *VersionRepository) GetVersion(ctx context.Context, formID uuid.UUID, version int) (*model.FormVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM form_versions WHERE form_id = $1 AND version = $2`
+
+	var formVersion model.FormVersion
+	if err := r.db.GetContext(ctx, &formVersion, query, formID, version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("version not found")
+		}
+		return nil, fmt.Errorf("failed to get form version: %w", err)
+	}
+
+	return &formVersion, nil
+}
+
+// GetLatestVersion retrieves the most recent version of a form
+func (r *VersionRepository) GetLatestVersion(ctx context.Context, formID uuid.UUID) (*model.FormVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM form_versions WHERE form_id = $1 ORDER BY version DESC LIMIT 1`
+
+	var formVersion model.FormVersion
+	if err := r.db.GetContext(ctx, &formVersion, query, formID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("version not found")
+		}
+		return nil, fmt.Errorf("failed to get form version: %w", err)
+	}
+
+	return &formVersion, nil
+}
+
+// ListVersions retrieves the versions of a form, newest first, with the
+// number of responses recorded against each
+func (r *VersionRepository) ListVersions(ctx context.Context, formID uuid.UUID) ([]*model.FormVersionSummary, error) {
+	query := `
+		SELECT v.version,
+		       jsonb_array_length(COALESCE(v.definition->'questions', '[]'::jsonb)) AS question_count,
+		       (SELECT COUNT(*) FROM filled_forms ff WHERE ff.form_id = v.form_id AND ff.form_version = v.version) AS response_count,
+		       v.created_at
+		FROM form_versions v
+		WHERE v.form_id = $1
+		ORDER BY v.version DESC`
+
+	versions := []*model.FormVersionSummary{}
+	if err := r.db.SelectContext(ctx, &versions, query, formID); err != nil {
+		return nil, fmt.Errorf("failed to list form versions: %w", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type VersionRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *VersionRepository
+}
+
+func (s *VersionRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &VersionRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *VersionRepositorySuite) TearDownTest() {
+	s.mock.ExpectationsWereMet()
+}
+
+func TestVersionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(VersionRepositorySuite))
+}
+
+var versionRowColumns = []string{"form_id", "version", "definition", "created_at"}
+
+const latestVersionQuery = `SELECT form_id, version, definition, created_at FROM form_versions WHERE form_id = $1 ORDER BY version DESC LIMIT 1`
+
+const insertVersionQuery = `INSERT INTO form_versions (form_id, version, definition, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (form_id, version) DO NOTHING`
+
+func versionDefinition(questionText string) *model.FormDefinition {
+	return &model.FormDefinition{
+		Title: "Survey",
+		Questions: []model.QuestionDefinition{{
+			ID:                    uuid.MustParse("550e8400-e29b-41d4-a716-446655440003"),
+			CreateQuestionRequest: model.CreateQuestionRequest{QuestionText: questionText, Type: model.QuestionTypeBasic, Position: 1},
+		}},
+	}
+}
+
+func (s *VersionRepositorySuite) versionRow(formID uuid.UUID, version int, definition *model.FormDefinition) *sqlmock.Rows {
+	data, err := json.Marshal(definition)
+	s.Require().NoError(err)
+	return sqlmock.NewRows(versionRowColumns).AddRow(formID, version, data, time.Now())
+}
+
+func (s *VersionRepositorySuite) TestEnsureVersion_ReusesUnchangedVersion() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(latestVersionQuery)).WithArgs(formID).
+		WillReturnRows(s.versionRow(formID, 2, versionDefinition("Name?")))
+
+	version, err := s.repo.EnsureVersion(context.Background(), formID, versionDefinition("Name?"))
+	s.Require().NoError(err)
+	s.Equal(2, version.Version)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *VersionRepositorySuite) TestEnsureVersion_RecordsChangedDefinition() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(latestVersionQuery)).WithArgs(formID).
+		WillReturnRows(s.versionRow(formID, 2, versionDefinition("Name?")))
+	s.mock.ExpectExec(regexp.QuoteMeta(insertVersionQuery)).
+		WithArgs(formID, 3, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	version, err := s.repo.EnsureVersion(context.Background(), formID, versionDefinition("Full name?"))
+	s.Require().NoError(err)
+	s.Equal(3, version.Version)
+	s.Equal("Full name?", version.Definition.Questions[0].QuestionText)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *VersionRepositorySuite) TestEnsureVersion_FirstVersion() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(latestVersionQuery)).WithArgs(formID).WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectExec(regexp.QuoteMeta(insertVersionQuery)).
+		WithArgs(formID, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	version, err := s.repo.EnsureVersion(context.Background(), formID, versionDefinition("Name?"))
+	s.Require().NoError(err)
+	s.Equal(1, version.Version)
+}
+
+func (s *VersionRepositorySuite) TestEnsureVersion_ConcurrentInsertReadsWinner() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(latestVersionQuery)).WithArgs(formID).
+		WillReturnRows(s.versionRow(formID, 1, versionDefinition("Name?")))
+	// Another submission recorded version 2 first
+	s.mock.ExpectExec(regexp.QuoteMeta(insertVersionQuery)).
+		WithArgs(formID, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(latestVersionQuery)).WithArgs(formID).
+		WillReturnRows(s.versionRow(formID, 2, versionDefinition("Full name?")))
+
+	version, err := s.repo.EnsureVersion(context.Background(), formID, versionDefinition("Full name?"))
+	s.Require().NoError(err)
+	s.Equal(2, version.Version)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *VersionRepositorySuite) TestGetVersion_NotFound() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_versions WHERE form_id = $1 AND version = $2`)).
+		WithArgs(formID, 7).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetVersion(context.Background(), formID, 7)
+	s.Require().Error(err)
+	s.Equal("version not found", err.Error())
+}
+
+func (s *VersionRepositorySuite) TestListVersions() {
+	formID := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM form_versions v WHERE v.form_id = $1 ORDER BY v.version DESC`)).
+		WithArgs(formID).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "question_count", "response_count", "created_at"}).
+			AddRow(2, 3, 10, time.Now()).
+			AddRow(1, 2, 4, time.Now()))
+
+	versions, err := s.repo.ListVersions(context.Background(), formID)
+	s.Require().NoError(err)
+	s.Require().Len(versions, 2)
+	s.Equal(2, versions[0].Version)
+	s.Equal(10, versions[0].ResponseCount)
+}
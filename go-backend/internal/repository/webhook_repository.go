@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const webhookColumns = `id, form_id, url, secret, events, active, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, form_id, event, url, payload, signature, status, attempts,
+		       last_status_code, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// CreateWebhook creates a new webhook
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, form_id, url, secret, events, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		webhook.ID,
+		webhook.FormID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.Active,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookByID retrieves a webhook by ID
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	var webhook model.Webhook
+	if err := r.db.GetContext(ctx, &webhook, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhooksByFormID retrieves all webhooks of a form
+func (r *WebhookRepository) ListWebhooksByFormID(ctx context.Context, formID uuid.UUID) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE form_id = $1 ORDER BY created_at`
+
+	var webhooks []*model.Webhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, formID); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// ListActiveWebhooks retrieves the active webhooks of a form subscribed to an event
+func (r *WebhookRepository) ListActiveWebhooks(ctx context.Context, formID uuid.UUID, event model.WebhookEvent) ([]*model.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE form_id = $1 AND active AND events ? $2`
+
+	var webhooks []*model.Webhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, formID, string(event)); err != nil {
+		return nil, fmt.Errorf("failed to list active webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook updates an existing webhook
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $1, secret = $2, events = $3, active = $4, updated_at = $5
+		WHERE id = $6`
+
+	result, err := r.db.ExecContext(ctx, query,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.Active,
+		webhook.UpdatedAt,
+		webhook.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes a webhook along with its pending deliveries
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		// Deliveries outlive their webhook only when its form is deleted
+		pendingQuery := `DELETE FROM webhook_deliveries WHERE webhook_id = $1 AND status = 'pending'`
+		if _, err := tx.ExecContext(ctx, pendingQuery, id); err != nil {
+			return fmt.Errorf("failed to delete pending deliveries: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("webhook not found")
+		}
+
+		return nil
+	})
+}
+
+// CreateDeliveries queues deliveries in a single transaction
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO webhook_deliveries (id, webhook_id, form_id, event, url, payload, signature, status, attempts, next_attempt_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+		for _, delivery := range deliveries {
+			_, err := tx.ExecContext(ctx, query,
+				delivery.ID,
+				delivery.WebhookID,
+				delivery.FormID,
+				delivery.Event,
+				delivery.URL,
+				delivery.Payload,
+				delivery.Signature,
+				delivery.Status,
+				delivery.Attempts,
+				delivery.NextAttemptAt,
+				delivery.CreatedAt,
+				delivery.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create delivery: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetDeliveryByID retrieves a delivery by ID
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	var delivery model.WebhookDelivery
+	if err := r.db.GetContext(ctx, &delivery, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("delivery not found")
+		}
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// ListDeliveriesByWebhookID retrieves the most recent deliveries of a webhook
+func (r *WebhookRepository) ListDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	var deliveries []*model.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries picks up to limit pending deliveries that are due and
+// pushes their next attempt back by lease, so that other workers skip them
+// while they are being sent
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	var deliveries []*model.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, limit, lease.Seconds()); err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDeliveryAttempt records the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_status_code = $3, last_error = $4,
+		    next_attempt_at = $5, delivered_at = $6, updated_at = $7
+		WHERE id = $8`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.UpdatedAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	return nil
+}
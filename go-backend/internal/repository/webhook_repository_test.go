@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type WebhookRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *WebhookRepository
+}
+
+func (s *WebhookRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &WebhookRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *WebhookRepositorySuite) TearDownTest() {
+	s.mock.ExpectationsWereMet()
+}
+
+func TestWebhookRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositorySuite))
+}
+
+var webhookRowColumns = []string{"id", "form_id", "url", "secret", "events", "active", "created_at", "updated_at"}
+
+func (s *WebhookRepositorySuite) TestCreateWebhook_Success() {
+	webhook := &model.Webhook{
+		ID:        uuid.New(),
+		FormID:    uuid.New(),
+		URL:       "https://example.com/hooks",
+		Secret:    "secret",
+		Events:    model.JSONStringArray{"response.created"},
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	query := `INSERT INTO webhooks (id, form_id, url, secret, events, active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	s.mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(webhook.ID, webhook.FormID, webhook.URL, webhook.Secret, webhook.Events, webhook.Active, webhook.CreatedAt, webhook.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := s.repo.CreateWebhook(context.Background(), webhook)
+	s.Require().NoError(err)
+}
+
+func (s *WebhookRepositorySuite) TestGetWebhookByID_NotFound() {
+	id := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM webhooks WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetWebhookByID(context.Background(), id)
+	s.Require().Error(err)
+	s.Equal("webhook not found", err.Error())
+}
+
+func (s *WebhookRepositorySuite) TestListActiveWebhooks_Success() {
+	formID := uuid.New()
+	now := time.Now()
+	rows := sqlmock.NewRows(webhookRowColumns).
+		AddRow(uuid.New(), formID, "https://example.com/hooks", "secret", []byte(`["response.created","form.closed"]`), true, now, now)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM webhooks WHERE form_id = $1 AND active AND events ? $2`)).
+		WithArgs(formID, "form.closed").
+		WillReturnRows(rows)
+
+	webhooks, err := s.repo.ListActiveWebhooks(context.Background(), formID, model.WebhookEventFormClosed)
+	s.Require().NoError(err)
+	s.Require().Len(webhooks, 1)
+	s.Equal(model.JSONStringArray{"response.created", "form.closed"}, webhooks[0].Events)
+}
+
+func (s *WebhookRepositorySuite) TestDeleteWebhook_RemovesPendingDeliveries() {
+	id := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhook_deliveries WHERE webhook_id = $1 AND status = 'pending'`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhooks WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.DeleteWebhook(context.Background(), id)
+	s.Require().NoError(err)
+}
+
+func (s *WebhookRepositorySuite) TestDeleteWebhook_NotFound() {
+	id := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhook_deliveries`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhooks WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteWebhook(context.Background(), id)
+	s.Require().Error(err)
+	s.Equal("webhook not found", err.Error())
+}
+
+func (s *WebhookRepositorySuite) TestClaimDueDeliveries_Success() {
+	webhookID := uuid.New()
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "webhook_id", "form_id", "event", "url", "payload", "signature", "status", "attempts",
+		"last_status_code", "last_error", "next_attempt_at", "delivered_at", "created_at", "updated_at",
+	}).AddRow(uuid.New(), webhookID, uuid.New(), "response.created", "https://example.com/hooks", "{}", "sha256=abc", "pending", 1,
+		500, "unexpected status code 500", now, nil, now, now)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE webhook_deliveries SET next_attempt_at = NOW() + make_interval(secs => $2)`)).
+		WithArgs(20, float64(30)).
+		WillReturnRows(rows)
+
+	deliveries, err := s.repo.ClaimDueDeliveries(context.Background(), 20, 30*time.Second)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.Equal(webhookID, *deliveries[0].WebhookID)
+	s.Equal(model.DeliveryStatusPending, deliveries[0].Status)
+	s.Equal(500, *deliveries[0].LastStatusCode)
+	s.Nil(deliveries[0].DeliveredAt)
+}
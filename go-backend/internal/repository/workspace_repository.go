@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+const (
+	workspaceColumns  = `id, name, created_by, created_at, updated_at`
+	invitationColumns = `id, workspace_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at`
+)
+
+// CreateWorkspace creates a workspace with its creator as its first admin in
+// a single transaction
+func (r *WorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *model.Workspace, adminID uuid.UUID) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO workspaces (id, name, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.ExecContext(ctx, query, workspace.ID, workspace.Name, workspace.CreatedBy, workspace.CreatedAt, workspace.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+
+		member := &model.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      adminID,
+			Role:        model.WorkspaceRoleAdmin,
+			CreatedAt:   workspace.CreatedAt,
+			UpdatedAt:   workspace.CreatedAt,
+		}
+		return addMember(ctx, tx, member)
+	})
+}
+
+// GetWorkspaceByID retrieves a workspace by ID
+func (r *WorkspaceRepository) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + ` FROM workspaces WHERE id = $1`
+
+	var workspace model.Workspace
+	if err := r.db.GetContext(ctx, &workspace, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workspace not found")
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// ListWorkspacesByUserID retrieves the workspaces a user is a member of, with
+// their role in each, by name
+func (r *WorkspaceRepository) ListWorkspacesByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, w.created_by, w.created_at, w.updated_at, m.role
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.name, w.id`
+
+	var workspaces []*model.Workspace
+	if err := r.db.SelectContext(ctx, &workspaces, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// UpdateWorkspace renames a workspace
+func (r *WorkspaceRepository) UpdateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	query := `UPDATE workspaces SET name = $2, updated_at = $3 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, workspace.ID, workspace.Name, workspace.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("workspace not found")
+	}
+
+	return nil
+}
+
+// DeleteWorkspace deletes a workspace with its memberships and invitations.
+// Its forms are kept and return to their authors.
+func (r *WorkspaceRepository) DeleteWorkspace(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM workspaces WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("workspace not found")
+	}
+
+	return nil
+}
+
+// GetMember retrieves a user's membership of a workspace
+func (r *WorkspaceRepository) GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error) {
+	query := `
+		SELECT workspace_id, user_id, role, created_at, updated_at
+		FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2`
+
+	var member model.WorkspaceMember
+	if err := r.db.GetContext(ctx, &member, query, workspaceID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("member not found")
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// ListMembers retrieves the members of a workspace with their email
+// addresses, in the order they joined
+func (r *WorkspaceRepository) ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]*model.WorkspaceMember, error) {
+	query := `
+		SELECT m.workspace_id, m.user_id, u.email, m.role, m.created_at, m.updated_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.created_at, m.user_id`
+
+	var members []*model.WorkspaceMember
+	if err := r.db.SelectContext(ctx, &members, query, workspaceID); err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a member. The last admin of a
+// workspace cannot be demoted.
+func (r *WorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role model.WorkspaceRole, now time.Time) error {
+	return r.changeMembers(ctx, workspaceID, func(tx *sqlx.Tx) (sql.Result, error) {
+		query := `
+			UPDATE workspace_members
+			SET role = $3, updated_at = $4
+			WHERE workspace_id = $1 AND user_id = $2`
+		return tx.ExecContext(ctx, query, workspaceID, userID, role, now)
+	})
+}
+
+// RemoveMember removes a member from a workspace. The last admin of a
+// workspace cannot be removed.
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	return r.changeMembers(ctx, workspaceID, func(tx *sqlx.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+	})
+}
+
+// changeMembers applies a change to one member of a workspace and checks the
+// workspace still has an admin. The workspace row is locked so concurrent
+// changes cannot remove every admin between them.
+func (r *WorkspaceRepository) changeMembers(ctx context.Context, workspaceID uuid.UUID, change func(tx *sqlx.Tx) (sql.Result, error)) error {
+	return r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		var locked uuid.UUID
+		if err := tx.GetContext(ctx, &locked, `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`, workspaceID); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("workspace not found")
+			}
+			return fmt.Errorf("failed to lock workspace: %w", err)
+		}
+
+		result, err := change(tx)
+		if err != nil {
+			return fmt.Errorf("failed to change member: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("member not found")
+		}
+
+		var admins int
+		if err := tx.GetContext(ctx, &admins, `SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = 'admin'`, workspaceID); err != nil {
+			return fmt.Errorf("failed to count admins: %w", err)
+		}
+
+		if admins == 0 {
+			return fmt.Errorf("workspace needs an admin")
+		}
+
+		return nil
+	})
+}
+
+// addMember inserts a membership
+func addMember(ctx context.Context, exec execer, member *model.WorkspaceMember) error {
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := exec.ExecContext(ctx, query, member.WorkspaceID, member.UserID, member.Role, member.CreatedAt, member.UpdatedAt)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return fmt.Errorf("already a member")
+		}
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+
+	return nil
+}
+
+// CreateInvitation creates an invitation to a workspace
+func (r *WorkspaceRepository) CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error {
+	query := `
+		INSERT INTO workspace_invitations (id, workspace_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		invitation.ID,
+		invitation.WorkspaceID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitationByTokenHash retrieves the invitation whose token hashes to tokenHash
+func (r *WorkspaceRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*model.WorkspaceInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM workspace_invitations WHERE token_hash = $1`
+
+	var invitation model.WorkspaceInvitation
+	if err := r.db.GetContext(ctx, &invitation, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// ListPendingInvitations retrieves the invitations to a workspace that have
+// not been accepted, newest first
+func (r *WorkspaceRepository) ListPendingInvitations(ctx context.Context, workspaceID uuid.UUID) ([]*model.WorkspaceInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM workspace_invitations
+		WHERE workspace_id = $1 AND accepted_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+
+	var invitations []*model.WorkspaceInvitation
+	if err := r.db.SelectContext(ctx, &invitations, query, workspaceID); err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// DeleteInvitation revokes a pending invitation to a workspace
+func (r *WorkspaceRepository) DeleteInvitation(ctx context.Context, workspaceID, id uuid.UUID) error {
+	query := `DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND accepted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("invitation not found")
+	}
+
+	return nil
+}
+
+// AcceptInvitation marks an invitation accepted and adds the user to its
+// workspace with the invited role in a single transaction. An invitation can
+// only be accepted once.
+func (r *WorkspaceRepository) AcceptInvitation(ctx context.Context, invitation *model.WorkspaceInvitation, userID uuid.UUID, now time.Time) (*model.WorkspaceMember, error) {
+	member := &model.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err := r.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE workspace_invitations
+			SET accepted_at = $2
+			WHERE id = $1 AND accepted_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, invitation.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("invitation already accepted")
+		}
+
+		return addMember(ctx, tx, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.AcceptedAt = &now
+	return member, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ayan-sh03/anoq/internal/db"
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type WorkspaceRepositorySuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *WorkspaceRepository
+}
+
+func (s *WorkspaceRepositorySuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = sqlx.NewDb(mockDB, "sqlmock")
+	s.mock = mock
+	s.repo = &WorkspaceRepository{db: &db.DB{DB: s.db}}
+}
+
+func (s *WorkspaceRepositorySuite) TearDownTest() {
+	s.Require().NoError(s.mock.ExpectationsWereMet())
+}
+
+func TestWorkspaceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceRepositorySuite))
+}
+
+var invitationRowColumns = []string{"id", "workspace_id", "email", "role", "token_hash", "invited_by", "expires_at", "accepted_at", "created_at"}
+
+const (
+	insertMemberQuery = `INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
+	lockWorkspace     = `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`
+	countAdmins       = `SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = 'admin'`
+)
+
+func (s *WorkspaceRepositorySuite) TestCreateWorkspace_AddsCreatorAsAdmin() {
+	adminID := uuid.New()
+	workspace := model.NewWorkspace(&model.CreateWorkspaceRequest{Name: "Research"}, adminID, time.Now())
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO workspaces (id, name, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)).
+		WithArgs(workspace.ID, "Research", workspace.CreatedBy, workspace.CreatedAt, workspace.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(insertMemberQuery)).
+		WithArgs(workspace.ID, adminID, model.WorkspaceRoleAdmin, workspace.CreatedAt, workspace.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.CreateWorkspace(context.Background(), workspace, adminID)
+	s.Require().NoError(err)
+}
+
+func (s *WorkspaceRepositorySuite) TestGetWorkspaceByID_NotFound() {
+	id := uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM workspaces WHERE id = $1`)).WithArgs(id).WillReturnError(sql.ErrNoRows)
+
+	workspace, err := s.repo.GetWorkspaceByID(context.Background(), id)
+	s.Nil(workspace)
+	s.EqualError(err, "workspace not found")
+}
+
+func (s *WorkspaceRepositorySuite) TestListWorkspacesByUserID_IncludesRole() {
+	userID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "name", "created_by", "created_at", "updated_at", "role"}).
+		AddRow(uuid.New(), "Research", nil, time.Now(), time.Now(), "analyst")
+	s.mock.ExpectQuery(regexp.QuoteMeta(`JOIN workspace_members m ON m.workspace_id = w.id WHERE m.user_id = $1`)).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	workspaces, err := s.repo.ListWorkspacesByUserID(context.Background(), userID)
+	s.Require().NoError(err)
+	s.Require().Len(workspaces, 1)
+	s.Equal(model.WorkspaceRoleAnalyst, workspaces[0].Role)
+}
+
+func (s *WorkspaceRepositorySuite) TestGetMember_NotFound() {
+	workspaceID, userID := uuid.New(), uuid.New()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`)).
+		WithArgs(workspaceID, userID).
+		WillReturnError(sql.ErrNoRows)
+
+	member, err := s.repo.GetMember(context.Background(), workspaceID, userID)
+	s.Nil(member)
+	s.EqualError(err, "member not found")
+}
+
+func (s *WorkspaceRepositorySuite) TestUpdateMemberRole_Success() {
+	workspaceID, userID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(lockWorkspace)).WithArgs(workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(workspaceID))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE workspace_members SET role = $3, updated_at = $4 WHERE workspace_id = $1 AND user_id = $2`)).
+		WithArgs(workspaceID, userID, model.WorkspaceRoleEditor, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(countAdmins)).WithArgs(workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.UpdateMemberRole(context.Background(), workspaceID, userID, model.WorkspaceRoleEditor, now)
+	s.Require().NoError(err)
+}
+
+func (s *WorkspaceRepositorySuite) TestUpdateMemberRole_LastAdmin() {
+	workspaceID, userID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(lockWorkspace)).WithArgs(workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(workspaceID))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE workspace_members`)).
+		WithArgs(workspaceID, userID, model.WorkspaceRoleViewer, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(countAdmins)).WithArgs(workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateMemberRole(context.Background(), workspaceID, userID, model.WorkspaceRoleViewer, now)
+	s.EqualError(err, "workspace needs an admin")
+}
+
+func (s *WorkspaceRepositorySuite) TestRemoveMember_NotFound() {
+	workspaceID, userID := uuid.New(), uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(lockWorkspace)).WithArgs(workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(workspaceID))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`)).
+		WithArgs(workspaceID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.RemoveMember(context.Background(), workspaceID, userID)
+	s.EqualError(err, "member not found")
+}
+
+func (s *WorkspaceRepositorySuite) TestRemoveMember_UnknownWorkspace() {
+	workspaceID := uuid.New()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(lockWorkspace)).WithArgs(workspaceID).WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	err := s.repo.RemoveMember(context.Background(), workspaceID, uuid.New())
+	s.EqualError(err, "workspace not found")
+}
+
+func (s *WorkspaceRepositorySuite) TestGetInvitationByTokenHash_NotFound() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`FROM workspace_invitations WHERE token_hash = $1`)).
+		WithArgs("hash").
+		WillReturnError(sql.ErrNoRows)
+
+	invitation, err := s.repo.GetInvitationByTokenHash(context.Background(), "hash")
+	s.Nil(invitation)
+	s.EqualError(err, "invitation not found")
+}
+
+func (s *WorkspaceRepositorySuite) TestListPendingInvitations() {
+	workspaceID := uuid.New()
+	rows := sqlmock.NewRows(invitationRowColumns).
+		AddRow(uuid.New(), workspaceID, "a@example.com", "viewer", "hash", nil, time.Now().Add(time.Hour), nil, time.Now())
+	s.mock.ExpectQuery(regexp.QuoteMeta(`WHERE workspace_id = $1 AND accepted_at IS NULL ORDER BY created_at DESC, id DESC`)).
+		WithArgs(workspaceID).
+		WillReturnRows(rows)
+
+	invitations, err := s.repo.ListPendingInvitations(context.Background(), workspaceID)
+	s.Require().NoError(err)
+	s.Require().Len(invitations, 1)
+	s.Equal("a@example.com", invitations[0].Email)
+}
+
+func (s *WorkspaceRepositorySuite) TestAcceptInvitation_Success() {
+	invitation := &model.WorkspaceInvitation{ID: uuid.New(), WorkspaceID: uuid.New(), Role: model.WorkspaceRoleAnalyst}
+	userID := uuid.New()
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE workspace_invitations SET accepted_at = $2 WHERE id = $1 AND accepted_at IS NULL`)).
+		WithArgs(invitation.ID, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(insertMemberQuery)).
+		WithArgs(invitation.WorkspaceID, userID, model.WorkspaceRoleAnalyst, now, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	member, err := s.repo.AcceptInvitation(context.Background(), invitation, userID, now)
+	s.Require().NoError(err)
+	s.Equal(model.WorkspaceRoleAnalyst, member.Role)
+	s.Equal(&now, invitation.AcceptedAt)
+}
+
+func (s *WorkspaceRepositorySuite) TestAcceptInvitation_AlreadyAccepted() {
+	invitation := &model.WorkspaceInvitation{ID: uuid.New(), WorkspaceID: uuid.New(), Role: model.WorkspaceRoleViewer}
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE workspace_invitations`)).
+		WithArgs(invitation.ID, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.AcceptInvitation(context.Background(), invitation, uuid.New(), now)
+	s.EqualError(err, "invitation already accepted")
+	s.Nil(invitation.AcceptedAt)
+}
+
+func (s *WorkspaceRepositorySuite) TestAcceptInvitation_AlreadyMember() {
+	invitation := &model.WorkspaceInvitation{ID: uuid.New(), WorkspaceID: uuid.New(), Role: model.WorkspaceRoleViewer}
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE workspace_invitations`)).
+		WithArgs(invitation.ID, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(insertMemberQuery)).
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key"})
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.AcceptInvitation(context.Background(), invitation, uuid.New(), now)
+	s.EqualError(err, "already a member")
+}
+
+func (s *WorkspaceRepositorySuite) TestDeleteInvitation_NotFound() {
+	workspaceID, id := uuid.New(), uuid.New()
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND accepted_at IS NULL`)).
+		WithArgs(id, workspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repo.DeleteInvitation(context.Background(), workspaceID, id)
+	s.EqualError(err, "invitation not found")
+}
@@ -0,0 +1,99 @@
+// Package respondent derives stable, non-reversible identifiers for the people
+// answering a form, so duplicate submissions can be detected without storing
+// their email or IP address
+package respondent
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the type of identifier being hashed
+type Kind string
+
+const (
+	KindEmail   Kind = "email"
+	KindIP      Kind = "ip"
+	KindBrowser Kind = "browser"
+)
+
+// BrowserCookie is the name of the cookie holding the browser token
+const BrowserCookie = "anoq_browser"
+
+// browserIDSize is the number of random bytes identifying a browser
+const browserIDSize = 16
+
+// Identifier hashes respondent identifiers and issues browser tokens
+type Identifier struct {
+	secret []byte
+}
+
+// NewIdentifier creates an identifier keyed with secret
+func NewIdentifier(secret string) *Identifier {
+	return &Identifier{
+		secret: []byte(secret),
+	}
+}
+
+// Hash returns the hex encoded HMAC-SHA256 of the value. The form ID is part
+// of the input so the same person cannot be linked across forms. Emails are
+// compared case-insensitively.
+func (i *Identifier) Hash(formID uuid.UUID, kind Kind, value string) string {
+	value = strings.TrimSpace(value)
+	if kind == KindEmail {
+		value = strings.ToLower(value)
+	}
+
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write(formID[:])
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IssueBrowserToken returns a new signed token identifying a browser
+func (i *Identifier) IssueBrowserToken() (string, error) {
+	id := make([]byte, browserIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(id) + "." +
+		base64.RawURLEncoding.EncodeToString(i.sign(id)), nil
+}
+
+// VerifyBrowserToken returns the browser ID of a token issued by IssueBrowserToken
+func (i *Identifier) VerifyBrowserToken(token string) (string, bool) {
+	encodedID, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return "", false
+	}
+
+	id, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil || len(id) != browserIDSize {
+		return "", false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, i.sign(id)) {
+		return "", false
+	}
+
+	return encodedID, true
+}
+
+// sign returns the HMAC-SHA256 of a browser ID
+func (i *Identifier) sign(id []byte) []byte {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(KindBrowser))
+	mac.Write([]byte{0})
+	mac.Write(id)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,50 @@
+package respondent
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifier_Hash(t *testing.T) {
+	identifier := NewIdentifier("secret")
+	formID := uuid.New()
+
+	hash := identifier.Hash(formID, KindEmail, "Jane@Example.com ")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, identifier.Hash(formID, KindEmail, "jane@example.com"))
+	assert.NotEqual(t, hash, identifier.Hash(uuid.New(), KindEmail, "jane@example.com"))
+	assert.NotEqual(t, hash, identifier.Hash(formID, KindIP, "jane@example.com"))
+	assert.NotEqual(t, hash, NewIdentifier("other").Hash(formID, KindEmail, "jane@example.com"))
+
+	// Only emails are case-insensitive
+	assert.NotEqual(t, identifier.Hash(formID, KindBrowser, "AbC"), identifier.Hash(formID, KindBrowser, "abc"))
+}
+
+func TestIdentifier_BrowserToken(t *testing.T) {
+	identifier := NewIdentifier("secret")
+
+	token, err := identifier.IssueBrowserToken()
+	require.NoError(t, err)
+
+	id, ok := identifier.VerifyBrowserToken(token)
+	assert.True(t, ok)
+	assert.NotEmpty(t, id)
+
+	other, err := identifier.IssueBrowserToken()
+	require.NoError(t, err)
+	otherID, _ := identifier.VerifyBrowserToken(other)
+	assert.NotEqual(t, id, otherID)
+
+	tampered := []byte(token)
+	tampered[0] ^= 1
+
+	for _, invalid := range []string{"", "garbage", string(tampered), "." + token} {
+		_, ok := identifier.VerifyBrowserToken(invalid)
+		assert.False(t, ok, invalid)
+	}
+	_, ok = NewIdentifier("other").VerifyBrowserToken(token)
+	assert.False(t, ok)
+}
@@ -0,0 +1,69 @@
+// Package scheduler opens and closes forms when their scheduled times pass
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+	"github.com/ayan-sh03/anoq/internal/webhook"
+)
+
+// Store is the persistence the scheduler needs
+type Store interface {
+	ApplyDueSchedules(ctx context.Context, now time.Time) ([]*model.Form, error)
+}
+
+// Scheduler periodically applies due form schedules. The store applies each
+// scheduled time once, so every replica can run its own scheduler.
+type Scheduler struct {
+	store    Store
+	webhooks webhook.Publisher
+	interval time.Duration
+}
+
+// NewScheduler creates a scheduler checking for due schedules every interval
+func NewScheduler(store Store, webhooks webhook.Publisher, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		webhooks: webhooks,
+		interval: interval,
+	}
+}
+
+// Run applies due schedules until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick applies the schedules due at now and publishes the resulting status changes
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	forms, err := s.store.ApplyDueSchedules(ctx, now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to apply form schedules")
+		return
+	}
+
+	for _, form := range forms {
+		event := model.WebhookEventFormOpened
+		if form.Status == model.FormStatusClosed {
+			event = model.WebhookEventFormClosed
+		}
+
+		log.Info().Str("form_id", form.ID.String()).Str("status", string(form.Status)).Msg("Applied form schedule")
+		s.webhooks.Publish(ctx, form.ID, event, form.ToResponse())
+	}
+}
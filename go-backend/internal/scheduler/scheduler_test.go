@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+type fakeStore struct {
+	forms []*model.Form
+	now   time.Time
+}
+
+func (s *fakeStore) ApplyDueSchedules(ctx context.Context, now time.Time) ([]*model.Form, error) {
+	s.now = now
+	return s.forms, nil
+}
+
+type publishedEvent struct {
+	formID uuid.UUID
+	event  model.WebhookEvent
+}
+
+type fakePublisher struct {
+	events []publishedEvent
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}) {
+	p.events = append(p.events, publishedEvent{formID, event})
+}
+
+func (p *fakePublisher) PublishOnSuccess(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}, fn func() error) error {
+	if err := fn(); err != nil {
+		return err
+	}
+	p.Publish(ctx, formID, event, data)
+	return nil
+}
+
+func TestTick_PublishesStatusChanges(t *testing.T) {
+	opened := &model.Form{ID: uuid.New(), Status: model.FormStatusOpen}
+	closed := &model.Form{ID: uuid.New(), Status: model.FormStatusClosed}
+	store := &fakeStore{forms: []*model.Form{opened, closed}}
+	publisher := &fakePublisher{}
+	now := time.Now()
+
+	NewScheduler(store, publisher, time.Minute).Tick(context.Background(), now)
+
+	assert.Equal(t, now, store.now)
+	assert.Equal(t, []publishedEvent{
+		{opened.ID, model.WebhookEventFormOpened},
+		{closed.ID, model.WebhookEventFormClosed},
+	}, publisher.events)
+}
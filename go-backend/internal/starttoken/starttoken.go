@@ -0,0 +1,56 @@
+// Package starttoken issues and verifies signed tokens recording when a
+// respondent started filling in a form
+package starttoken
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ayan-sh03/anoq/internal/formtoken"
+)
+
+var (
+	ErrInvalid = errors.New("invalid start token")
+	ErrExpired = errors.New("start token expired")
+)
+
+// purpose keeps start tokens apart from other form tokens
+const purpose = "form-start"
+
+// Signer issues and verifies start tokens
+type Signer struct {
+	tokens *formtoken.Signer
+	ttl    time.Duration
+}
+
+// NewSigner creates a signer; tokens older than ttl are rejected
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{
+		tokens: formtoken.NewSigner(secret, purpose),
+		ttl:    ttl,
+	}
+}
+
+// Issue returns a token recording that the form was started at startedAt
+func (s *Signer) Issue(formID uuid.UUID, startedAt time.Time) string {
+	return s.tokens.Issue(formID, startedAt)
+}
+
+// Verify checks the token was issued for formID and returns when the form was started
+func (s *Signer) Verify(token string, formID uuid.UUID, now time.Time) (time.Time, error) {
+	startedAt, err := s.tokens.Verify(token, formID)
+	if err != nil {
+		return time.Time{}, ErrInvalid
+	}
+
+	if startedAt.After(now) {
+		return time.Time{}, ErrInvalid
+	}
+	if s.ttl > 0 && now.Sub(startedAt) > s.ttl {
+		return time.Time{}, ErrExpired
+	}
+
+	return startedAt, nil
+}
@@ -0,0 +1,61 @@
+package starttoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	formID := uuid.New()
+	startedAt := time.Now().Add(-2 * time.Minute).Truncate(time.Millisecond)
+
+	startedAtVerified, err := signer.Verify(signer.Issue(formID, startedAt), formID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, startedAt.Equal(startedAtVerified))
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	formID := uuid.New()
+	now := time.Now()
+	token := signer.Issue(formID, now.Add(-time.Minute))
+
+	// The same payload signed without the purpose, as another token type would
+	encodedPayload, _, _ := strings.Cut(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(encodedPayload)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	otherPurpose := encodedPayload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name    string
+		token   string
+		formID  uuid.UUID
+		now     time.Time
+		wantErr error
+	}{
+		{"other form", token, uuid.New(), now, ErrInvalid},
+		{"other secret", NewSigner("other", time.Hour).Issue(formID, now.Add(-time.Minute)), formID, now, ErrInvalid},
+		{"other purpose", otherPurpose, formID, now, ErrInvalid},
+		{"tampered", token[:len(token)-2] + "AA", formID, now, ErrInvalid},
+		{"garbage", "not-a-token", formID, now, ErrInvalid},
+		{"expired", token, formID, now.Add(2 * time.Hour), ErrExpired},
+		{"from the future", signer.Issue(formID, now.Add(time.Minute)), formID, now, ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token, tt.formID, tt.now)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
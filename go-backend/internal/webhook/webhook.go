@@ -0,0 +1,438 @@
+// Package webhook queues form events for webhook subscribers and delivers
+// them in the background with signed requests and exponential-backoff retries
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Anoq-Event"
+	HeaderDelivery  = "X-Anoq-Delivery"
+	HeaderSignature = "X-Anoq-Signature-256"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked failed
+	MaxAttempts = 8
+	// BaseBackoff is the delay before the first retry, doubled on every retry after it
+	BaseBackoff = 30 * time.Second
+
+	requestTimeout = 10 * time.Second
+	maxRedirects   = 3
+	pollInterval   = 5 * time.Second
+	batchSize      = 20
+	// lease keeps a claimed delivery away from other workers while it is sent
+	lease = requestTimeout + 20*time.Second
+	// maxErrorLength bounds the error message stored on a delivery
+	maxErrorLength = 500
+)
+
+// ErrForbiddenTarget is returned when a webhook URL points at a loopback,
+// link-local, private or otherwise internal address
+var ErrForbiddenTarget = errors.New("webhook target address is not allowed")
+
+// internalPrefixes are ranges not covered by the netip predicates that must
+// not be reachable either: "this network" and shared carrier-grade NAT space
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Store is the persistence the dispatcher needs
+type Store interface {
+	ListActiveWebhooks(ctx context.Context, formID uuid.UUID, event model.WebhookEvent) ([]*model.Webhook, error)
+	CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	UpdateDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+// Publisher queues form events for delivery
+type Publisher interface {
+	Publish(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{})
+	PublishOnSuccess(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}, fn func() error) error
+}
+
+// Dispatcher queues events in the store and delivers them from Run
+type Dispatcher struct {
+	store        Store
+	client       *http.Client
+	allowPrivate bool
+	wake         chan struct{}
+}
+
+// NewDispatcher creates a new dispatcher. Unless allowPrivate is set,
+// deliveries refuse to connect to internal addresses, checked on the address
+// actually dialled so DNS names and redirects cannot get around it.
+func NewDispatcher(store Store, allowPrivate bool) *Dispatcher {
+	return &Dispatcher{
+		store:        store,
+		client:       newClient(allowPrivate),
+		allowPrivate: allowPrivate,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// newClient builds the HTTP client deliveries are sent with
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivate {
+		dialer.Control = dialControl
+	}
+
+	return &http.Client{
+		Timeout: requestTimeout,
+		// No proxy: the guard must see the address of the webhook itself
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: requestTimeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// dialControl rejects connections to internal addresses after DNS resolution
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenTarget, addr)
+	}
+	return nil
+}
+
+// checkRedirect follows a few http or https redirects; every hop is dialled
+// through the same guard
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+	}
+	return nil
+}
+
+// IsPublicAddr returns false for loopback, link-local, private, unspecified,
+// multicast and other internal addresses
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckTarget rejects a webhook URL whose host is localhost or an internal
+// address literal, so obviously unreachable targets fail when registered.
+// Names resolving to internal addresses are refused when dialled.
+func (d *Dispatcher) CheckTarget(rawURL string) error {
+	if d.allowPrivate {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenTarget
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
+
+// Publish queues a delivery of the event for every active webhook of the form
+// subscribed to it. Failures are logged so they never fail the caller's request.
+func (d *Dispatcher) Publish(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}) {
+	deliveries, err := d.prepare(ctx, formID, event, data)
+	if err == nil {
+		err = d.queue(ctx, deliveries)
+	}
+	if err != nil {
+		logPublishError(err, formID, event)
+	}
+}
+
+// PublishOnSuccess looks up the subscribers of the event before running fn and
+// queues their deliveries only once fn succeeds. It is meant for events such
+// as form.deleted, where fn removes the subscribers themselves. The error of
+// fn is returned unchanged; publishing failures are logged.
+func (d *Dispatcher) PublishOnSuccess(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}, fn func() error) error {
+	deliveries, prepareErr := d.prepare(ctx, formID, event, data)
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if prepareErr == nil {
+		prepareErr = d.queue(ctx, deliveries)
+	}
+	if prepareErr != nil {
+		logPublishError(prepareErr, formID, event)
+	}
+	return nil
+}
+
+// prepare builds one delivery per subscribed webhook, all sharing the same payload
+func (d *Dispatcher) prepare(ctx context.Context, formID uuid.UUID, event model.WebhookEvent, data interface{}) ([]*model.WebhookDelivery, error) {
+	webhooks, err := d.store.ListActiveWebhooks(ctx, formID, event)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		FormID:    formID,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	deliveries := make([]*model.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		webhookID := webhook.ID
+		deliveries[i] = &model.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     &webhookID,
+			FormID:        formID,
+			Event:         event,
+			URL:           webhook.URL,
+			Payload:       string(body),
+			Signature:     Sign(webhook.Secret, body),
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+
+	return deliveries, nil
+}
+
+// queue stores the deliveries and wakes Run
+func (d *Dispatcher) queue(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := d.store.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	d.notify()
+	return nil
+}
+
+// Redeliver queues a new delivery with the same URL, payload and signature as
+// an earlier one
+func (d *Dispatcher) Redeliver(ctx context.Context, original *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	now := time.Now()
+	delivery := &model.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     original.WebhookID,
+		FormID:        original.FormID,
+		Event:         original.Event,
+		URL:           original.URL,
+		Payload:       original.Payload,
+		Signature:     original.Signature,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := d.queue(ctx, []*model.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Run delivers due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// drain sends batches of due deliveries until none are left
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.store.ClaimDueDeliveries(ctx, batchSize, lease)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to claim webhook deliveries")
+			return
+		}
+
+		for _, delivery := range deliveries {
+			d.attempt(ctx, delivery)
+		}
+
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	delivery.LastStatusCode = nil
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.LastError = truncatedError(err)
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(Backoff(delivery.Attempts))
+		delivery.LastError = truncatedError(err)
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := d.store.UpdateDeliveryAttempt(ctx, delivery); err != nil {
+		log.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to record webhook delivery attempt")
+	}
+}
+
+// send posts the delivery and returns the response status code
+func (d *Dispatcher) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Anoq-Webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, delivery.Signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded part of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// notify wakes Run without blocking when it is already awake
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Backoff returns the delay before retrying a delivery that failed attempts times
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return BaseBackoff
+	}
+	return BaseBackoff << (attempts - 1)
+}
+
+// Sign returns the signature header value of a payload, the hex encoded
+// HMAC-SHA256 of the body keyed with the webhook secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func logPublishError(err error, formID uuid.UUID, event model.WebhookEvent) {
+	log.Error().Err(err).Str("form_id", formID.String()).Str("event", string(event)).Msg("Failed to queue webhook deliveries")
+}
+
+func truncatedError(err error) *string {
+	msg := err.Error()
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	return &msg
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayan-sh03/anoq/internal/model"
+)
+
+// fakeStore keeps webhooks and deliveries in memory
+type fakeStore struct {
+	mu         sync.Mutex
+	webhooks   []*model.Webhook
+	deliveries []*model.WebhookDelivery
+}
+
+func (s *fakeStore) ListActiveWebhooks(ctx context.Context, formID uuid.UUID, event model.WebhookEvent) ([]*model.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var webhooks []*model.Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.FormID == formID && webhook.Active && webhook.Subscribes(event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *fakeStore) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries = append(s.deliveries, deliveries...)
+	return nil
+}
+
+func (s *fakeStore) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var claimed []*model.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if delivery.Status == model.DeliveryStatusPending && !delivery.NextAttemptAt.After(now) {
+			next := now.Add(lease)
+			delivery.NextAttemptAt = &next
+			claimed = append(claimed, delivery)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *fakeStore) UpdateDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func newWebhook(formID uuid.UUID, url string, events ...string) *model.Webhook {
+	return &model.Webhook{
+		ID:     uuid.New(),
+		FormID: formID,
+		URL:    url,
+		Secret: "secret-" + url,
+		Events: events,
+		Active: true,
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Reference value from: echo -n '{"a":1}' | openssl dgst -sha256 -hmac key
+	assert.Equal(t, "sha256=88a67f24bbcdaed0e6c997404bb79a743baf44c6bab2f4c27328e3009d22e342", Sign("key", []byte(`{"a":1}`)))
+	assert.NotEqual(t, Sign("key", []byte("body")), Sign("other", []byte("body")))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 2*time.Minute, Backoff(3))
+	assert.Equal(t, 32*time.Minute, Backoff(MaxAttempts-1))
+}
+
+func TestPublish_QueuesSubscribedWebhooks(t *testing.T) {
+	formID := uuid.New()
+	subscribed := newWebhook(formID, "https://a.example.com", "response.created")
+	store := &fakeStore{webhooks: []*model.Webhook{
+		subscribed,
+		newWebhook(formID, "https://b.example.com", "form.closed"),
+		newWebhook(uuid.New(), "https://c.example.com", "response.created"),
+	}}
+	dispatcher := NewDispatcher(store, true)
+
+	dispatcher.Publish(context.Background(), formID, model.WebhookEventResponseCreated, map[string]string{"id": "42"})
+
+	require.Len(t, store.deliveries, 1)
+	delivery := store.deliveries[0]
+	assert.Equal(t, subscribed.ID, *delivery.WebhookID)
+	assert.Equal(t, subscribed.URL, delivery.URL)
+	assert.Equal(t, model.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, Sign(subscribed.Secret, []byte(delivery.Payload)), delivery.Signature)
+
+	var payload model.WebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+	assert.Equal(t, model.WebhookEventResponseCreated, payload.Event)
+	assert.Equal(t, formID, payload.FormID)
+	assert.Equal(t, map[string]interface{}{"id": "42"}, payload.Data)
+}
+
+func TestPublishOnSuccess(t *testing.T) {
+	formID := uuid.New()
+	store := &fakeStore{webhooks: []*model.Webhook{newWebhook(formID, "https://a.example.com", "form.deleted")}}
+	dispatcher := NewDispatcher(store, true)
+
+	err := dispatcher.PublishOnSuccess(context.Background(), formID, model.WebhookEventFormDeleted, nil, func() error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, store.deliveries)
+
+	err = dispatcher.PublishOnSuccess(context.Background(), formID, model.WebhookEventFormDeleted, nil, func() error {
+		// Subscribers are gone once the form is deleted
+		store.webhooks = nil
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, store.deliveries, 1)
+}
+
+func TestAttempt(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		status   = http.StatusInternalServerError
+		received *http.Request
+		body     []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	formID := uuid.New()
+	hook := newWebhook(formID, server.URL, "form.opened")
+	store := &fakeStore{webhooks: []*model.Webhook{hook}}
+	dispatcher := NewDispatcher(store, true)
+	dispatcher.Publish(context.Background(), formID, model.WebhookEventFormOpened, nil)
+	require.Len(t, store.deliveries, 1)
+	delivery := store.deliveries[0]
+
+	// A failed attempt is retried after a backoff
+	dispatcher.drain(context.Background())
+	assert.Equal(t, model.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, *delivery.LastStatusCode)
+	assert.Contains(t, *delivery.LastError, "unexpected status code 500")
+	assert.WithinDuration(t, time.Now().Add(BaseBackoff), *delivery.NextAttemptAt, 5*time.Second)
+
+	assert.Equal(t, "form.opened", received.Header.Get(HeaderEvent))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderDelivery))
+	assert.Equal(t, Sign(hook.Secret, body), received.Header.Get(HeaderSignature))
+
+	// A successful attempt completes the delivery
+	status = http.StatusNoContent
+	dispatcher.attempt(context.Background(), delivery)
+	assert.Equal(t, model.DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Nil(t, delivery.LastError)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestAttempt_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	delivery := &model.WebhookDelivery{
+		ID:            uuid.New(),
+		Event:         model.WebhookEventFormClosed,
+		URL:           server.URL,
+		Payload:       "{}",
+		Status:        model.DeliveryStatusPending,
+		Attempts:      MaxAttempts - 1,
+		NextAttemptAt: &now,
+	}
+
+	NewDispatcher(&fakeStore{}, true).attempt(context.Background(), delivery)
+
+	assert.Equal(t, model.DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, MaxAttempts, delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestAttempt_RefusesInternalAddresses(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	delivery := &model.WebhookDelivery{ID: uuid.New(), URL: server.URL, Payload: "{}", Status: model.DeliveryStatusPending}
+	NewDispatcher(&fakeStore{}, false).attempt(context.Background(), delivery)
+
+	assert.False(t, hit)
+	assert.Equal(t, model.DeliveryStatusPending, delivery.Status)
+	assert.Nil(t, delivery.LastStatusCode)
+	assert.Contains(t, *delivery.LastError, ErrForbiddenTarget.Error())
+}
+
+func TestSend_LimitsRedirects(t *testing.T) {
+	redirects := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirects++
+		http.Redirect(w, r, server.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	delivery := &model.WebhookDelivery{ID: uuid.New(), URL: server.URL, Payload: "{}"}
+	_, err := NewDispatcher(&fakeStore{}, true).send(context.Background(), delivery)
+
+	assert.ErrorContains(t, err, "stopped after 3 redirects")
+	assert.Equal(t, maxRedirects, redirects)
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.100.100.200", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	dispatcher := NewDispatcher(&fakeStore{}, false)
+
+	assert.NoError(t, dispatcher.CheckTarget("https://hooks.example.com/anoq"))
+	assert.NoError(t, dispatcher.CheckTarget("https://93.184.216.34/anoq"))
+	for _, target := range []string{
+		"http://localhost:5432",
+		"http://api.localhost/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]:8080/",
+		"http://10.1.2.3/hook",
+	} {
+		assert.ErrorIs(t, dispatcher.CheckTarget(target), ErrForbiddenTarget, target)
+	}
+
+	assert.NoError(t, NewDispatcher(&fakeStore{}, true).CheckTarget("http://localhost:5432"))
+}
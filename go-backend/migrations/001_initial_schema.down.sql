@@ -0,0 +1,9 @@
+-- Migration 001 (down): Drop the initial schema
+DROP TABLE IF EXISTS filled_form_questions;
+DROP TABLE IF EXISTS filled_forms;
+DROP TABLE IF EXISTS multiple_choice_questions;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS forms;
+DROP TABLE IF EXISTS users;
+
+DROP TYPE IF EXISTS form_status;
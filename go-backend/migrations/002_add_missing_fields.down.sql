@@ -0,0 +1,16 @@
+-- Migration 002 (down): Remove sessions and password hashes
+DROP TABLE IF EXISTS user_sessions;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
+
+DROP INDEX IF EXISTS idx_users_email;
+
+-- Restore the original multiple_choice_questions structure
+DROP TABLE IF EXISTS multiple_choice_questions;
+
+CREATE TABLE multiple_choice_questions (
+    question_id UUID PRIMARY KEY REFERENCES questions(id) ON DELETE CASCADE,
+    choices JSONB NOT NULL DEFAULT '[]',
+    selected_choice JSONB NOT NULL DEFAULT '[]',
+    allow_multiple BOOLEAN NOT NULL DEFAULT false
+);
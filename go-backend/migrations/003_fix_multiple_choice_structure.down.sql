@@ -0,0 +1,3 @@
+-- Migration 003 (down): Restore selected_choice on multiple_choice_questions
+ALTER TABLE multiple_choice_questions ADD COLUMN selected_choice JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE multiple_choice_questions ALTER COLUMN question_id DROP NOT NULL;
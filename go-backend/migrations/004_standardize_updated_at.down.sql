@@ -0,0 +1,6 @@
+-- Migration 004 (down): Rename updated_at back to modified_at
+ALTER TABLE users RENAME COLUMN updated_at TO modified_at;
+
+ALTER TABLE forms RENAME COLUMN updated_at TO modified_at;
+
+ALTER TABLE filled_forms RENAME COLUMN updated_at TO modified_at;
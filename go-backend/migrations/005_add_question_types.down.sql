@@ -0,0 +1,4 @@
+-- Migration 005 (down): Drop rating, number and long text question storage
+DROP TABLE IF EXISTS long_text_questions;
+DROP TABLE IF EXISTS number_questions;
+DROP TABLE IF EXISTS rating_questions;
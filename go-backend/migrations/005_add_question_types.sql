@@ -0,0 +1,31 @@
+-- Migration 005: Add storage for rating, number and long text questions
+-- Date, time, email, URL and yes/no questions need no extra settings;
+-- dropdown questions reuse multiple_choice_questions.
+
+CREATE TABLE rating_questions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    question_id UUID UNIQUE NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    scale_min INTEGER NOT NULL DEFAULT 1,
+    scale_max INTEGER NOT NULL DEFAULT 5,
+    min_label VARCHAR(255),
+    max_label VARCHAR(255),
+    CHECK (scale_min < scale_max)
+);
+
+CREATE TABLE number_questions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    question_id UUID UNIQUE NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    min_value DOUBLE PRECISION,
+    max_value DOUBLE PRECISION,
+    integer_only BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE TABLE long_text_questions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    question_id UUID UNIQUE NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    max_length INTEGER
+);
+
+CREATE INDEX idx_rating_questions_question_id ON rating_questions(question_id);
+CREATE INDEX idx_number_questions_question_id ON number_questions(question_id);
+CREATE INDEX idx_long_text_questions_question_id ON long_text_questions(question_id);
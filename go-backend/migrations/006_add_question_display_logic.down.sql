@@ -0,0 +1,2 @@
+-- Migration 006 (down): Remove skip logic from questions
+ALTER TABLE questions DROP COLUMN IF EXISTS display_logic;
@@ -0,0 +1,4 @@
+-- Migration 006: Add skip logic to questions
+-- Skip logic: a question is only shown when its display_logic conditions on
+-- earlier answers match. NULL means the question is always shown.
+ALTER TABLE questions ADD COLUMN display_logic JSONB;
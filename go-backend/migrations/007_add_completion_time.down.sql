@@ -0,0 +1,4 @@
+-- Migration 007 (down): Stop tracking completion time
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS flagged_fast;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS completion_time_ms;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS started_at;
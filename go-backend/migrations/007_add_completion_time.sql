@@ -0,0 +1,7 @@
+-- Migration 007: Track how long respondents take to fill in a form
+-- started_at comes from the signed start token issued with the form;
+-- submissions without a token leave these columns NULL.
+
+ALTER TABLE filled_forms ADD COLUMN started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE filled_forms ADD COLUMN completion_time_ms BIGINT CHECK (completion_time_ms >= 0);
+ALTER TABLE filled_forms ADD COLUMN flagged_fast BOOLEAN NOT NULL DEFAULT FALSE;
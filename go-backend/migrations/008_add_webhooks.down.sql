@@ -0,0 +1,3 @@
+-- Migration 008 (down): Drop webhooks and their delivery log
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,7 @@
+-- Migration 009 (down): Stop scheduling form open/close times
+DROP INDEX IF EXISTS idx_forms_closes_at;
+DROP INDEX IF EXISTS idx_forms_opens_at;
+ALTER TABLE forms DROP CONSTRAINT IF EXISTS forms_schedule_order;
+ALTER TABLE forms DROP COLUMN IF EXISTS schedule_applied_at;
+ALTER TABLE forms DROP COLUMN IF EXISTS closes_at;
+ALTER TABLE forms DROP COLUMN IF EXISTS opens_at;
@@ -0,0 +1,13 @@
+-- Migration 009: Open and close forms automatically at scheduled times
+-- schedule_applied_at records when the schedule last set the status, so a
+-- time that has passed is applied once and manual changes after it stick.
+
+ALTER TABLE forms ADD COLUMN opens_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE forms ADD COLUMN closes_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE forms ADD COLUMN schedule_applied_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE forms ADD CONSTRAINT forms_schedule_order
+    CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at);
+
+CREATE INDEX idx_forms_opens_at ON forms(opens_at) WHERE opens_at IS NOT NULL;
+CREATE INDEX idx_forms_closes_at ON forms(closes_at) WHERE closes_at IS NOT NULL;
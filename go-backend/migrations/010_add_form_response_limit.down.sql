@@ -0,0 +1,2 @@
+-- Migration 010 (down): Remove form response limits
+ALTER TABLE forms DROP COLUMN IF EXISTS max_responses;
@@ -0,0 +1,4 @@
+-- Migration 010: Optional limit on the number of responses a form accepts
+-- The submission reaching the limit closes the form.
+
+ALTER TABLE forms ADD COLUMN max_responses INTEGER CHECK (max_responses > 0);
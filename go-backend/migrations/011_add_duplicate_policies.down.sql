@@ -0,0 +1,9 @@
+-- Migration 011 (down): Remove duplicate submission policies
+DROP INDEX IF EXISTS idx_filled_forms_browser_hash;
+DROP INDEX IF EXISTS idx_filled_forms_ip_hash;
+DROP INDEX IF EXISTS idx_filled_forms_email_hash;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS browser_hash;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS ip_hash;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS email_hash;
+ALTER TABLE forms DROP COLUMN IF EXISTS duplicate_window_seconds;
+ALTER TABLE forms DROP COLUMN IF EXISTS duplicate_policy;
@@ -0,0 +1,15 @@
+-- Migration 011: Per-form policies preventing duplicate submissions
+-- Respondents are identified by salted hashes of their email, IP address or
+-- browser token, so the raw identifiers are not needed to enforce a policy.
+
+ALTER TABLE forms ADD COLUMN duplicate_policy VARCHAR(20) NOT NULL DEFAULT 'none'
+    CHECK (duplicate_policy IN ('none', 'email', 'ip', 'browser'));
+ALTER TABLE forms ADD COLUMN duplicate_window_seconds INTEGER CHECK (duplicate_window_seconds > 0);
+
+ALTER TABLE filled_forms ADD COLUMN email_hash VARCHAR(64);
+ALTER TABLE filled_forms ADD COLUMN ip_hash VARCHAR(64);
+ALTER TABLE filled_forms ADD COLUMN browser_hash VARCHAR(64);
+
+CREATE INDEX idx_filled_forms_email_hash ON filled_forms(form_id, email_hash) WHERE email_hash IS NOT NULL;
+CREATE INDEX idx_filled_forms_ip_hash ON filled_forms(form_id, ip_hash) WHERE ip_hash IS NOT NULL;
+CREATE INDEX idx_filled_forms_browser_hash ON filled_forms(form_id, browser_hash) WHERE browser_hash IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Migration 012 (down): Remove anonymity levels
+ALTER TABLE forms DROP COLUMN IF EXISTS anonymity_level;
@@ -0,0 +1,6 @@
+-- Migration 012: Per-form anonymity levels
+-- identified keeps the respondent's IP, name and email, pseudonymous keeps only
+-- salted hashes of the email and IP, anonymous keeps none of them.
+
+ALTER TABLE forms ADD COLUMN anonymity_level VARCHAR(20) NOT NULL DEFAULT 'identified'
+    CHECK (anonymity_level IN ('identified', 'pseudonymous', 'anonymous'));
@@ -0,0 +1,3 @@
+-- Migration 013 (down): Remove respondent edit links
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS edit_token_hash;
+ALTER TABLE forms DROP COLUMN IF EXISTS edit_window_seconds;
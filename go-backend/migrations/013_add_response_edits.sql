@@ -0,0 +1,7 @@
+-- Migration 013: Respondent edit links
+-- Forms with an edit window give respondents a secret token to edit or delete
+-- their response until the window expires. Only a hash of the token is stored.
+
+ALTER TABLE forms ADD COLUMN edit_window_seconds INTEGER CHECK (edit_window_seconds > 0);
+
+ALTER TABLE filled_forms ADD COLUMN edit_token_hash VARCHAR(64);
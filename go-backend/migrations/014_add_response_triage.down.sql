@@ -0,0 +1,7 @@
+-- Migration 014 (down): Remove response triage
+DROP INDEX IF EXISTS idx_filled_forms_tags;
+DROP INDEX IF EXISTS idx_filled_forms_spam;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS tags;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS starred;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS reviewed;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS spam;
@@ -0,0 +1,10 @@
+-- Migration 014: Owner-side response triage
+-- Form owners can mark responses as spam, reviewed or starred and tag them.
+
+ALTER TABLE filled_forms ADD COLUMN spam BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE filled_forms ADD COLUMN reviewed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE filled_forms ADD COLUMN starred BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE filled_forms ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX idx_filled_forms_spam ON filled_forms(form_id) WHERE spam;
+CREATE INDEX idx_filled_forms_tags ON filled_forms USING GIN (tags);
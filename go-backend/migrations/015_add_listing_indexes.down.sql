@@ -0,0 +1,4 @@
+-- Migration 015 (down): Remove listing indexes
+DROP INDEX IF EXISTS idx_forms_author_created;
+DROP INDEX IF EXISTS idx_filled_forms_form_updated;
+DROP INDEX IF EXISTS idx_filled_forms_form_created;
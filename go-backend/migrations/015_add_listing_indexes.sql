@@ -0,0 +1,6 @@
+-- Migration 015: Indexes for paginated listings
+-- Keyset pagination orders by a timestamp with the ID breaking ties.
+
+CREATE INDEX idx_filled_forms_form_created ON filled_forms(form_id, created_at, id);
+CREATE INDEX idx_filled_forms_form_updated ON filled_forms(form_id, updated_at, id);
+CREATE INDEX idx_forms_author_created ON forms(author_id, created_at, id);
@@ -0,0 +1,3 @@
+-- Migration 016 (down): Remove answer search
+DROP INDEX IF EXISTS idx_filled_form_questions_answer_tsv;
+ALTER TABLE filled_form_questions DROP COLUMN IF EXISTS answer_tsv;
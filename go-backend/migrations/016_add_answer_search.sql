@@ -0,0 +1,7 @@
+-- Migration 016: Full-text search over text answers
+-- The search vector is generated from the answer so it can never go stale.
+
+ALTER TABLE filled_form_questions
+    ADD COLUMN answer_tsv TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', COALESCE(answer, ''))) STORED;
+
+CREATE INDEX idx_filled_form_questions_answer_tsv ON filled_form_questions USING GIN (answer_tsv);
@@ -0,0 +1,2 @@
+-- Migration 017 (down): Remove form templates
+DROP TABLE IF EXISTS form_templates;
@@ -0,0 +1,18 @@
+-- Migration 017: Form templates
+-- A template stores the definition of a form (settings and questions) as a
+-- snapshot, so later edits to the source form do not change it.
+
+CREATE TABLE form_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'public')),
+    definition JSONB NOT NULL,
+    source_form_id UUID REFERENCES forms(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_form_templates_author_created ON form_templates(author_id, created_at, id);
+CREATE INDEX idx_form_templates_public_created ON form_templates(created_at, id) WHERE visibility = 'public';
@@ -0,0 +1,13 @@
+-- Migration 018 (down): Remove form versions
+-- Answers to deleted questions cannot be kept once the cascade is restored
+DELETE FROM filled_form_questions ffq
+WHERE NOT EXISTS (SELECT 1 FROM questions q WHERE q.id = ffq.question_id);
+
+ALTER TABLE filled_form_questions ADD CONSTRAINT filled_form_questions_question_id_fkey
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_filled_forms_form_version;
+ALTER TABLE filled_forms DROP CONSTRAINT IF EXISTS filled_forms_form_version_fkey;
+ALTER TABLE filled_forms DROP COLUMN IF EXISTS form_version;
+
+DROP TABLE IF EXISTS form_versions;
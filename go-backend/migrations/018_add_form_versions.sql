@@ -0,0 +1,21 @@
+-- Migration 018: Immutable form versions
+-- A version snapshots a form's settings and questions as respondents saw
+-- them. Responses record the version they answered, and answers outlive the
+-- questions they answered so older responses keep their history.
+
+CREATE TABLE form_versions (
+    form_id UUID NOT NULL REFERENCES forms(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    definition JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (form_id, version)
+);
+
+-- Responses submitted before versioning have no version
+ALTER TABLE filled_forms ADD COLUMN form_version INTEGER;
+ALTER TABLE filled_forms ADD CONSTRAINT filled_forms_form_version_fkey
+    FOREIGN KEY (form_id, form_version) REFERENCES form_versions(form_id, version);
+
+CREATE INDEX idx_filled_forms_form_version ON filled_forms(form_id, form_version);
+
+ALTER TABLE filled_form_questions DROP CONSTRAINT filled_form_questions_question_id_fkey;
@@ -0,0 +1,12 @@
+-- Migration 019 (down): Remove draft forms
+-- Enum values cannot be dropped, so the type is recreated without 'draft'
+-- and drafts are kept as closed forms
+ALTER TABLE forms ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE forms ALTER COLUMN status TYPE TEXT USING status::TEXT;
+UPDATE forms SET status = 'closed' WHERE status = 'draft';
+
+DROP TYPE form_status;
+CREATE TYPE form_status AS ENUM ('open', 'closed');
+
+ALTER TABLE forms ALTER COLUMN status TYPE form_status USING status::form_status;
+ALTER TABLE forms ALTER COLUMN status SET DEFAULT 'open';
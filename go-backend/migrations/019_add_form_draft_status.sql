@@ -0,0 +1,6 @@
+-- Migration 019: Draft forms
+-- Forms start as drafts that only their owner can see, and respondents can
+-- reach them once they are published. Existing forms are already published.
+-- Adding an enum value inside the migration transaction needs PostgreSQL 12
+-- or later; the value is not used until the transaction commits
+ALTER TYPE form_status ADD VALUE IF NOT EXISTS 'draft' BEFORE 'open';
@@ -0,0 +1,8 @@
+-- Migration 020 (down): Remove workspaces
+-- Forms stay with their authors
+DROP INDEX IF EXISTS idx_forms_workspace_created;
+ALTER TABLE forms DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
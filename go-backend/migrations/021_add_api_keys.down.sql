@@ -0,0 +1,2 @@
+-- Migration 021 (down): Remove API keys
+DROP TABLE IF EXISTS api_keys;
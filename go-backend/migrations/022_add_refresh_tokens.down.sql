@@ -0,0 +1,2 @@
+-- Migration 022 (down): Remove refresh tokens
+DROP TABLE IF EXISTS refresh_tokens;
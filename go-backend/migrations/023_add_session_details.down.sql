@@ -0,0 +1,5 @@
+-- Migration 023 (down): Remove session details
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS device;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS user_agent;
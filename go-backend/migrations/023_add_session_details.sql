@@ -0,0 +1,8 @@
+-- Migration 023: Session details for listing and revoking sessions
+-- Sessions record the client that created them and when they were last used.
+-- Using a session pushes its expiry back, so only idle sessions expire.
+
+ALTER TABLE user_sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN device VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL schema migrations so they ship inside the binary.
+//
+// Files are named NNN_description.sql for the up migration and
+// NNN_description.down.sql for the matching down migration.
+package migrations
+
+import "embed"
+
+// FS holds every migration file in this directory
+//
+//go:embed *.sql
+var FS embed.FS